@@ -115,3 +115,33 @@ User=` + store.GetOSUser() + `
 	}
 	return nil
 }
+
+// NewScheduleConfigurer installs the brev schedule daemon as a systemd service
+// running as user, a user name or ID. It returns nil on platforms without
+// systemd.
+func NewScheduleConfigurer(store AutoStartStore, user string) DaemonConfigurer {
+	if runtime.GOOS != osLinux {
+		return nil
+	}
+	return LinuxSystemdConfigurer{
+		Store: store,
+		ValueConfigFile: `
+[Install]
+WantedBy=multi-user.target
+
+[Unit]
+Description=Brev instance schedule daemon
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+ExecStart=` + targetBin + ` schedule daemon
+Restart=always
+User=` + user + `
+`,
+		ServiceName: "brevschedule.service",
+		ServiceType: "user",
+		TargetBin:   targetBin,
+	}
+}
@@ -0,0 +1,375 @@
+// Package apply converges instances on a declarative fleet spec
+package apply
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/budget"
+	"github.com/brevdev/brev-cli/pkg/cmd/gpucreate"
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyLong = `Create, start, stop and delete instances to match a fleet spec.
+
+The spec is a YAML or JSON file with a list of instance groups. Each group
+declares a name (use {i} for the 1-based index; with count > 1 the default is
+<name>-1, <name>-2, ...), a count, the desired state (running or stopped) and
+how to create missing instances: instance types to try in order, or search
+filters picked like 'brev create'. Build keys match the flags of 'brev create'.
+
+  groups:
+    - name: trainer
+      count: 3
+      filters:
+        min-vram: 80
+        stoppable: true
+      startup-script: "@setup.sh"
+    - name: notebook
+      types: [g5.xlarge, g5.2xlarge]
+      state: stopped
+    - name: demo
+      launchable: env-XXX
+
+Apply manages your instances, or every instance in the org when logged in
+with an API key. Instances apply creates are labelled managed-by=brev-apply.
+Instances that are not declared are left alone unless --prune is given, and
+--prune only deletes undeclared instances with that label, never ones apply
+did not create. Existing instances are never recreated to change their type.
+Use --dry-run to only print the plan.`
+
+	applyExample = `
+  # Show what would change
+  brev apply -f fleet.yaml --dry-run
+
+  # Converge, deleting instances that are no longer declared
+  brev apply -f fleet.yaml --prune
+
+  # Read the spec from stdin
+  cat fleet.json | brev apply -f -
+`
+)
+
+// ApplyStore lists, creates, starts, stops and deletes instances
+type ApplyStore interface {
+	gpucreate.GPUCreateStore
+	GetWorkspaces(organizationID string, options *store.GetWorkspacesOptions) ([]entity.Workspace, error)
+	StartWorkspace(workspaceID string) (*entity.Workspace, error)
+	StopWorkspace(workspaceID string) (*entity.Workspace, error)
+}
+
+// ApplyOptions holds the options of 'brev apply'
+type ApplyOptions struct {
+	File   string
+	DryRun bool
+	Prune  bool
+	Yes    bool
+	// create instances even if a spending cap would be exceeded
+	AllowOverBudget bool
+}
+
+// NewCmdApply creates the apply command
+func NewCmdApply(t *terminal.Terminal, applyStore ApplyStore) *cobra.Command {
+	var opts ApplyOptions
+
+	cmd := &cobra.Command{
+		Annotations:           map[string]string{"workspace": ""},
+		Use:                   "apply -f <file>",
+		DisableFlagsInUseLine: true,
+		Short:                 "Converge instances on a declarative fleet spec",
+		Long:                  applyLong,
+		Example:               applyExample,
+		Args:                  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunApply(t, applyStore, terminal.Prompter{}, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.File, "file", "f", "", "Fleet spec file (YAML or JSON), or - for stdin")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the plan without changing anything")
+	cmd.Flags().BoolVar(&opts.Prune, "prune", false, "Delete instances apply created that are no longer declared in the spec")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Do not ask for confirmation before deleting instances")
+	budget.AddFlags(cmd, &opts.AllowOverBudget)
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// RunApply plans the changes and, unless it is a dry run, executes them
+func RunApply(t *terminal.Terminal, applyStore ApplyStore, confirmer terminal.Confirmer, opts ApplyOptions) error {
+	spec, err := ReadFleetSpec(opts.File)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	workspaces, err := getUserWorkspaces(applyStore)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	plan := BuildPlan(spec, workspaces, opts.Prune)
+	displayPlan(t, plan)
+
+	if opts.DryRun || !plan.HasChanges() {
+		return nil
+	}
+
+	if deletes := plan.Count(ActionDelete); deletes > 0 && !opts.Yes {
+		if !confirmer.ConfirmYesNo(fmt.Sprintf("Delete %d instance(s) that are not declared?", deletes)) {
+			t.Vprint("Cancelled, nothing was changed")
+			return nil
+		}
+	}
+
+	// Creates need current capacity, so skip the catalog cache
+	gpusearch.ApplyCatalogCachePolicy(applyStore, gpusearch.CatalogCachePolicy{Mode: gpusearch.CatalogCacheRefresh})
+
+	return executePlan(t, applyStore, confirmer, plan, opts)
+}
+
+// getUserWorkspaces lists the instances apply manages: the current user's, or the whole org with an API key
+func getUserWorkspaces(applyStore ApplyStore) ([]entity.Workspace, error) {
+	org, err := applyStore.GetActiveOrganizationOrDefault()
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if org == nil {
+		return nil, breverrors.NewValidationError("no organization found")
+	}
+
+	options := &store.GetWorkspacesOptions{}
+	if !auth.IsAPIKeyAuthStore(applyStore) {
+		user, err := applyStore.GetCurrentUser()
+		if err != nil {
+			return nil, breverrors.WrapAndTrace(err)
+		}
+		options.UserID = user.ID
+	}
+
+	workspaces, err := applyStore.GetWorkspaces(org.ID, options)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	return workspaces, nil
+}
+
+// executePlan runs every action, continuing past failures, and returns the combined error
+func executePlan(t *terminal.Terminal, applyStore ApplyStore, confirmer terminal.Confirmer, plan Plan, opts ApplyOptions) error {
+	var allErr error
+	creators := map[*GroupSpec]*groupCreator{}
+	for _, action := range plan.Actions {
+		if action.Kind == ActionCreate && creators[action.Group] == nil {
+			creators[action.Group] = &groupCreator{t: t, store: applyStore, group: action.Group}
+		}
+	}
+
+	if err := checkBudget(t, applyStore, confirmer, plan, creators, opts); err != nil {
+		return err
+	}
+
+	for _, action := range plan.Actions {
+		var err error
+		switch action.Kind {
+		case ActionCreate:
+			err = creators[action.Group].create(action.Name)
+		case ActionStart:
+			t.Vprintf("Starting %s...\n", action.Name)
+			_, err = applyStore.StartWorkspace(action.Workspace.ID)
+		case ActionStop:
+			t.Vprintf("Stopping %s...\n", action.Name)
+			_, err = applyStore.StopWorkspace(action.Workspace.ID)
+		case ActionDelete:
+			t.Vprintf("Deleting %s...\n", action.Name)
+			_, err = applyStore.DeleteWorkspace(action.Workspace.ID)
+		default:
+			continue
+		}
+		if err != nil {
+			t.Vprint(t.Red("  %s %s failed: %s", action.Kind, action.Name, err.Error()))
+			allErr = multierror.Append(allErr, fmt.Errorf("%s %s: %w", action.Kind, action.Name, err))
+		}
+	}
+
+	if allErr != nil {
+		return breverrors.WrapAndTrace(allErr)
+	}
+	t.Vprint(t.Green("\nFleet converged. Run 'brev ls' to check status."))
+	return nil
+}
+
+// checkBudget applies the spending caps once to everything the plan creates
+// and starts, so max_per_command covers the whole apply and the user is asked
+// at most once. Groups whose options cannot be resolved create nothing and
+// are left to fail on their own.
+func checkBudget(t *terminal.Terminal, applyStore ApplyStore, confirmer terminal.Confirmer, plan Plan, creators map[*GroupSpec]*groupCreator, opts ApplyOptions) error {
+	var planned []budget.Planned
+	for _, action := range plan.Actions {
+		switch action.Kind {
+		case ActionCreate:
+			creator := creators[action.Group]
+			if creator.resolve() != nil {
+				continue
+			}
+			types := make([]string, 0, len(creator.opts.InstanceTypes))
+			for _, spec := range creator.opts.InstanceTypes {
+				types = append(types, spec.Type)
+			}
+			planned = append(planned, budget.Planned{InstanceTypes: types, Count: 1})
+		case ActionStart:
+			start := budget.Planned{Count: 1}
+			if action.Workspace.InstanceType != "" {
+				start.InstanceTypes = []string{action.Workspace.InstanceType}
+			}
+			planned = append(planned, start)
+		}
+	}
+	if len(planned) == 0 {
+		return nil
+	}
+
+	org, err := applyStore.GetActiveOrganizationOrDefault()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	if org == nil {
+		return breverrors.NewValidationError("no organization found")
+	}
+	_, err = budget.Check(applyStore, org.ID, planned, budget.Options{
+		AllowOverBudget: opts.AllowOverBudget,
+		Confirmer:       confirmer,
+		Logf:            t.Eprintf,
+	})
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	return nil
+}
+
+// groupCreator creates the missing instances of a group. Instance types and the
+// launchable are resolved once, on the first use.
+type groupCreator struct {
+	t        *terminal.Terminal
+	store    ApplyStore
+	group    *GroupSpec
+	resolved bool
+	opts     gpucreate.GPUCreateOptions
+	err      error
+}
+
+// resolve builds the group's create options the first time it is called
+func (c *groupCreator) resolve() error {
+	if !c.resolved {
+		c.resolved = true
+		c.opts, c.err = c.resolveOptions()
+	}
+	return c.err
+}
+
+func (c *groupCreator) create(name string) error {
+	if err := c.resolve(); err != nil {
+		return err
+	}
+
+	opts := c.opts
+	opts.Name = name
+	return gpucreate.RunGPUCreate(c.t, c.store, opts) //nolint:wrapcheck // errors are already wrapped by create
+}
+
+// resolveOptions builds the create options shared by the group's instances.
+// The budget was checked for the whole plan, so each create skips its own
+// confirmation.
+func (c *groupCreator) resolveOptions() (gpucreate.GPUCreateOptions, error) {
+	g := c.group
+	opts := gpucreate.GPUCreateOptions{
+		Count:           1,
+		Parallel:        1,
+		Detached:        true,
+		Timeout:         300 * time.Second,
+		StartupScript:   g.StartupScript,
+		Mode:            g.BuildMode(),
+		Jupyter:         true,
+		ContainerImage:  g.ContainerImage,
+		ComposeFile:     g.ComposeFile,
+		AllowOverBudget: true,
+		Labels:          map[string]string{ManagedLabel: ManagedValue},
+	}
+	if g.Jupyter != nil {
+		opts.Jupyter = *g.Jupyter
+		opts.JupyterSet = true
+	}
+
+	if g.Launchable != "" {
+		id, err := gpucreate.ParseLaunchableID(g.Launchable)
+		if err != nil {
+			return opts, breverrors.WrapAndTrace(err)
+		}
+		info, err := gpucreate.FetchAndDisplayLaunchable(c.store, c.t, id)
+		if err != nil {
+			return opts, breverrors.WrapAndTrace(err)
+		}
+		opts.LaunchableID = id
+		opts.LaunchableInfo = info
+		if len(g.Types) == 0 && len(g.Filters) == 0 && g.Preset == "" {
+			if info.CreateWorkspaceRequest.InstanceType == "" {
+				return opts, breverrors.NewValidationError(fmt.Sprintf("launchable %s has no instance type configured; add types to group %s", id, g.Name))
+			}
+			opts.InstanceTypes = []gpucreate.InstanceSpec{{Type: info.CreateWorkspaceRequest.InstanceType}}
+			return opts, nil
+		}
+	}
+
+	if len(g.Types) > 0 {
+		for _, instanceType := range g.Types {
+			opts.InstanceTypes = append(opts.InstanceTypes, gpucreate.InstanceSpec{Type: instanceType})
+		}
+		return opts, nil
+	}
+
+	filters, err := c.groupFilters()
+	if err != nil {
+		return opts, err
+	}
+	opts.InstanceTypes, err = gpucreate.InstanceTypesForFilters(c.store, filters)
+	if err != nil {
+		return opts, breverrors.WrapAndTrace(err)
+	}
+	if len(opts.InstanceTypes) == 0 {
+		return opts, breverrors.NewValidationError(fmt.Sprintf("no GPU instances match the filters of group %s. Try 'brev search' to see available options", g.Name))
+	}
+	return opts, nil
+}
+
+// groupFilters merges the group's preset with its filters; filters win
+func (c *groupCreator) groupFilters() (files.SearchPreset, error) {
+	filters := files.SearchPreset{}
+	if c.group.Preset != "" {
+		presetStore, ok := c.store.(gpusearch.PresetStore)
+		if !ok {
+			return nil, breverrors.NewValidationError("presets are not supported here")
+		}
+		presets, err := presetStore.GetSearchPresets()
+		if err != nil {
+			return nil, breverrors.WrapAndTrace(err)
+		}
+		preset, ok := presets[c.group.Preset]
+		if !ok {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("group %s: preset %q not found (see 'brev search presets ls')", c.group.Name, c.group.Preset))
+		}
+		for k, v := range preset {
+			filters[k] = v
+		}
+	}
+	for k, v := range c.group.Filters {
+		filters[k] = v
+	}
+	return filters, nil
+}
@@ -0,0 +1,301 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockApplyStore struct {
+	ApplyStore // unimplemented methods panic
+	workspaces []entity.Workspace
+	calls      []string
+	failIDs    map[string]bool
+	apiKey     string
+}
+
+func (m *mockApplyStore) GetAuthTokens() (*entity.AuthTokens, error) {
+	return &entity.AuthTokens{APIKey: m.apiKey}, nil
+}
+
+func (m *mockApplyStore) GetActiveOrganizationOrDefault() (*entity.Organization, error) {
+	return &entity.Organization{ID: "org-1"}, nil
+}
+
+func (m *mockApplyStore) GetCurrentUser() (*entity.User, error) {
+	return &entity.User{ID: "user-1"}, nil
+}
+
+func (m *mockApplyStore) GetWorkspaces(_ string, options *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	if options == nil {
+		// the budget check lists the whole org
+		return m.workspaces, nil
+	}
+	m.calls = append(m.calls, "list "+options.UserID)
+	return m.workspaces, nil
+}
+
+func (m *mockApplyStore) record(verb, id string) (*entity.Workspace, error) {
+	m.calls = append(m.calls, verb+" "+id)
+	if m.failIDs[id] {
+		return nil, fmt.Errorf("%s failed", verb)
+	}
+	return &entity.Workspace{ID: id}, nil
+}
+
+func (m *mockApplyStore) StartWorkspace(id string) (*entity.Workspace, error) {
+	return m.record("start", id)
+}
+
+func (m *mockApplyStore) StopWorkspace(id string) (*entity.Workspace, error) {
+	return m.record("stop", id)
+}
+
+func (m *mockApplyStore) DeleteWorkspace(id string) (*entity.Workspace, error) {
+	return m.record("delete", id)
+}
+
+type mockConfirmer struct {
+	answer bool
+	asked  int
+}
+
+func (m *mockConfirmer) ConfirmYesNo(string) bool {
+	m.asked++
+	return m.answer
+}
+
+func TestGroupInstanceNames(t *testing.T) {
+	two := 2
+	one := 1
+	zero := 0
+	assert.Equal(t, []string{"box"}, GroupSpec{Name: "box"}.InstanceNames())
+	assert.Equal(t, []string{"trainer-1", "trainer-2"}, GroupSpec{Name: "trainer", Count: &two}.InstanceNames())
+	assert.Equal(t, []string{"gpu1-a", "gpu2-a"}, GroupSpec{Name: "gpu{i}-a", Count: &two}.InstanceNames())
+	assert.Equal(t, []string{"node-1"}, GroupSpec{Name: "node-{i}", Count: &one}.InstanceNames())
+	assert.Empty(t, GroupSpec{Name: "gone", Count: &zero}.InstanceNames())
+}
+
+func TestParseFleetSpec(t *testing.T) {
+	spec, err := ParseFleetSpec([]byte(`
+groups:
+  - name: trainer
+    count: 2
+    filters:
+      min-vram: 80
+      stoppable: true
+  - name: notebook
+    types: [g5.xlarge, g5.2xlarge]
+    state: stopped
+    mode: container
+    container-image: nvcr.io/nvidia/pytorch:24.01-py3
+`))
+	require.NoError(t, err)
+	require.Len(t, spec.Groups, 2)
+	assert.Equal(t, "80", spec.Groups[0].Filters["min-vram"])
+	assert.Equal(t, "true", spec.Groups[0].Filters["stoppable"])
+	assert.Equal(t, StateStopped, spec.Groups[1].DesiredState())
+	assert.Equal(t, StateRunning, spec.Groups[0].DesiredState())
+
+	spec, err = ParseFleetSpec([]byte(`{"groups": [{"name": "box", "types": ["g5.xlarge"]}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "vm", spec.Groups[0].BuildMode())
+}
+
+func TestParseFleetSpecErrors(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		message string
+	}{
+		"empty":         {"", "empty"},
+		"no groups":     {"groups: []", "no groups"},
+		"unknown key":   {"groups:\n  - name: a\n    typo: 1\n", "line 3"},
+		"missing name":  {"groups:\n  - count: 1\n", "name is required"},
+		"bad state":     {"groups:\n  - name: a\n    state: paused\n", "invalid state"},
+		"bad name":      {"groups:\n  - name: -bad\n", "name must start"},
+		"bad mode":      {"groups:\n  - name: a\n    mode: lxc\n", "invalid mode"},
+		"types+filters": {"groups:\n  - name: a\n    types: [x]\n    filters: {min-vram: 80}\n", "either types"},
+		"duplicate":     {"groups:\n  - name: a\n    count: 2\n  - name: a-2\n", "declared by both"},
+		"negative":      {"groups:\n  - name: a\n    count: -1\n", "negative"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseFleetSpec([]byte(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestReadFleetSpecResolvesStartupScript(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "setup.sh"), []byte("echo hi\n"), 0o600))
+	path := filepath.Join(dir, "fleet.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("groups:\n  - name: a\n    startup-script: \"@setup.sh\"\n"), 0o600))
+
+	spec, err := ReadFleetSpec(path)
+	require.NoError(t, err)
+	assert.Equal(t, "echo hi\n", spec.Groups[0].StartupScript)
+}
+
+func mustParse(t *testing.T, input string) *FleetSpec {
+	t.Helper()
+	spec, err := ParseFleetSpec([]byte(input))
+	require.NoError(t, err)
+	return spec
+}
+
+func planSummary(plan Plan) map[string]ActionKind {
+	summary := map[string]ActionKind{}
+	for _, a := range plan.Actions {
+		summary[a.Name] = a.Kind
+	}
+	return summary
+}
+
+func TestBuildPlan(t *testing.T) {
+	spec := mustParse(t, `
+groups:
+  - name: trainer
+    count: 3
+    types: [g5.xlarge]
+  - name: notebook
+    state: stopped
+  - name: dev
+    state: stopped
+`)
+	stoppable := &entity.InstanceTypeInfo{Stoppable: true}
+	workspaces := []entity.Workspace{
+		{ID: "1", Name: "trainer-1", Status: entity.Running, InstanceType: "g5.xlarge"},
+		{ID: "2", Name: "trainer-2", Status: entity.Stopped, InstanceType: "g5.xlarge"},
+		{ID: "3", Name: "notebook", Status: entity.Running, InstanceTypeInfo: stoppable},
+		{ID: "4", Name: "dev", Status: entity.Running},
+		{ID: "5", Name: "old", Status: entity.Running, Labels: entity.Labels{ManagedLabel: ManagedValue}},
+		{ID: "6", Name: "trainer-3", Status: entity.Deleting},
+		{ID: "7", Name: "manual", Status: entity.Running},
+	}
+
+	plan := BuildPlan(spec, workspaces, false)
+	assert.Equal(t, map[string]ActionKind{
+		"trainer-1": ActionNone,
+		"trainer-2": ActionStart,
+		"trainer-3": ActionCreate,
+		"notebook":  ActionStop,
+		"dev":       ActionNone,
+		"old":       ActionNone,
+		"manual":    ActionNone,
+	}, planSummary(plan))
+	assert.Equal(t, ActionCreate, plan.Actions[0].Kind)
+	assert.True(t, plan.HasChanges())
+
+	// prune only deletes what apply created
+	plan = BuildPlan(spec, workspaces, true)
+	assert.Equal(t, ActionDelete, planSummary(plan)["old"])
+	assert.Equal(t, ActionNone, planSummary(plan)["manual"])
+	assert.Equal(t, 1, plan.Count(ActionDelete))
+}
+
+func TestBuildPlanNoChanges(t *testing.T) {
+	spec := mustParse(t, "groups:\n  - name: box\n    types: [g5.xlarge]\n")
+	plan := BuildPlan(spec, []entity.Workspace{{ID: "1", Name: "box", Status: entity.Running, InstanceType: "g4dn.xlarge"}}, true)
+	assert.False(t, plan.HasChanges())
+	assert.Contains(t, plan.Actions[0].Detail, "type g4dn.xlarge is not declared")
+}
+
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fleet.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRunApply(t *testing.T) {
+	path := writeSpec(t, "groups:\n  - name: a\n  - name: b\n    state: stopped\n")
+	workspaces := []entity.Workspace{
+		{ID: "1", Name: "a", Status: entity.Stopped},
+		{ID: "2", Name: "b", Status: entity.Running, InstanceTypeInfo: &entity.InstanceTypeInfo{Stoppable: true}},
+		{ID: "3", Name: "c", Status: entity.Running, Labels: entity.Labels{ManagedLabel: ManagedValue}},
+		{ID: "4", Name: "d", Status: entity.Running},
+	}
+	term := terminal.New()
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		s := &mockApplyStore{workspaces: workspaces}
+		err := RunApply(term, s, &mockConfirmer{}, ApplyOptions{File: path, DryRun: true, Prune: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"list user-1"}, s.calls)
+	})
+
+	t.Run("prune asks before deleting", func(t *testing.T) {
+		s := &mockApplyStore{workspaces: workspaces}
+		confirmer := &mockConfirmer{answer: false}
+		require.NoError(t, RunApply(term, s, confirmer, ApplyOptions{File: path, Prune: true}))
+		assert.Equal(t, 1, confirmer.asked)
+		assert.Equal(t, []string{"list user-1"}, s.calls)
+	})
+
+	t.Run("converges and prunes only instances apply created", func(t *testing.T) {
+		s := &mockApplyStore{workspaces: workspaces}
+		confirmer := &mockConfirmer{}
+		require.NoError(t, RunApply(term, s, confirmer, ApplyOptions{File: path, Prune: true, Yes: true}))
+		assert.Equal(t, 0, confirmer.asked)
+		assert.Equal(t, []string{"list user-1", "start 1", "stop 2", "delete 3"}, s.calls)
+	})
+
+	t.Run("api key prune spares instances apply did not create", func(t *testing.T) {
+		s := &mockApplyStore{workspaces: workspaces, apiKey: auth.BrevAPIKeyPrefix + "key"}
+		require.NoError(t, RunApply(term, s, &mockConfirmer{}, ApplyOptions{File: path, Prune: true, Yes: true}))
+		assert.Equal(t, []string{"list ", "start 1", "stop 2", "delete 3"}, s.calls)
+	})
+
+	t.Run("continues past failures", func(t *testing.T) {
+		s := &mockApplyStore{workspaces: workspaces, failIDs: map[string]bool{"1": true}}
+		err := RunApply(term, s, &mockConfirmer{}, ApplyOptions{File: path})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "start a")
+		assert.Equal(t, []string{"list user-1", "start 1", "stop 2"}, s.calls)
+	})
+}
+
+// budgetApplyStore has a $5/hr cap per command and prices g5.xlarge at $2/hr
+type budgetApplyStore struct {
+	mockApplyStore
+}
+
+func (m *budgetApplyStore) GetTeamBudgetPolicy() (*files.BudgetPolicy, string, error) {
+	return nil, "", nil
+}
+
+func (m *budgetApplyStore) GetPersonalBudgetPolicy() (*files.BudgetPolicy, error) {
+	return &files.BudgetPolicy{MaxPerCommand: 5}, nil
+}
+
+func (m *budgetApplyStore) GetInstanceTypes(_ bool) (*gpusearch.InstanceTypesResponse, error) {
+	return &gpusearch.InstanceTypesResponse{Items: []gpusearch.InstanceType{
+		{Type: "g5.xlarge", BasePrice: gpusearch.BasePrice{Amount: "2.00"}},
+	}}, nil
+}
+
+func TestRunApplyChecksBudgetOnceForThePlan(t *testing.T) {
+	// each create is within the cap, the three together are not
+	path := writeSpec(t, "groups:\n  - name: trainer\n    count: 3\n    types: [g5.xlarge]\n")
+	s := &budgetApplyStore{}
+	confirmer := &mockConfirmer{answer: false}
+
+	err := RunApply(terminal.New(), s, confirmer, ApplyOptions{File: path})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "over budget")
+	assert.Equal(t, 1, confirmer.asked)
+	// nothing was created
+	assert.Equal(t, []string{"list user-1"}, s.calls)
+}
@@ -0,0 +1,237 @@
+package apply
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+)
+
+// ActionKind is the change apply makes to a single instance
+type ActionKind string
+
+// Actions in the order they are executed
+const (
+	ActionCreate ActionKind = "create"
+	ActionStart  ActionKind = "start"
+	ActionStop   ActionKind = "stop"
+	ActionDelete ActionKind = "delete"
+	ActionNone   ActionKind = "ok"
+)
+
+// Action is one planned change, or ActionNone with the reason nothing is done
+type Action struct {
+	Kind      ActionKind
+	Name      string
+	Group     *GroupSpec        // nil for pruned instances
+	Workspace *entity.Workspace // nil for creates
+	Detail    string
+}
+
+// Plan is the list of actions that converge the existing instances on a spec
+type Plan struct {
+	Actions []Action
+}
+
+// Count returns the number of actions of the given kind
+func (p Plan) Count(kind ActionKind) int {
+	n := 0
+	for _, a := range p.Actions {
+		if a.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// HasChanges returns true if the plan does anything
+func (p Plan) HasChanges() bool {
+	return len(p.Actions) > p.Count(ActionNone)
+}
+
+// The label apply sets on the instances it creates
+const (
+	ManagedLabel = "managed-by"
+	ManagedValue = "brev-apply"
+)
+
+// IsManaged returns true if apply created the instance
+func IsManaged(ws entity.Workspace) bool {
+	return ws.Labels[ManagedLabel] == ManagedValue
+}
+
+// BuildPlan compares the spec with the existing instances. Instances that are
+// being deleted are ignored. Undeclared instances are only deleted with prune,
+// and only if apply created them.
+func BuildPlan(spec *FleetSpec, workspaces []entity.Workspace, prune bool) Plan {
+	existing := map[string]*entity.Workspace{}
+	for i := range workspaces {
+		ws := &workspaces[i]
+		if ws.Status == entity.Deleting {
+			continue
+		}
+		existing[ws.Name] = ws
+	}
+
+	var plan Plan
+	declared := map[string]bool{}
+	for i := range spec.Groups {
+		group := &spec.Groups[i]
+		for _, name := range group.InstanceNames() {
+			declared[name] = true
+			ws, ok := existing[name]
+			if !ok {
+				detail := ""
+				if group.DesiredState() == StateStopped {
+					detail = "declared stopped; re-run apply to stop it once it is running"
+				}
+				plan.Actions = append(plan.Actions, Action{Kind: ActionCreate, Name: name, Group: group, Detail: detail})
+				continue
+			}
+			plan.Actions = append(plan.Actions, planExisting(group, ws))
+		}
+	}
+
+	var undeclared []string
+	for name := range existing {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		ws := existing[name]
+		switch {
+		case prune && !IsManaged(*ws):
+			plan.Actions = append(plan.Actions, Action{Kind: ActionNone, Name: name, Workspace: ws, Detail: "not declared; not created by brev apply, so --prune leaves it"})
+		case prune:
+			plan.Actions = append(plan.Actions, Action{Kind: ActionDelete, Name: name, Workspace: ws, Detail: "not declared"})
+		default:
+			plan.Actions = append(plan.Actions, Action{Kind: ActionNone, Name: name, Workspace: ws, Detail: "not declared (use --prune to delete)"})
+		}
+	}
+
+	sort.SliceStable(plan.Actions, func(i, j int) bool {
+		return actionOrder(plan.Actions[i].Kind) < actionOrder(plan.Actions[j].Kind)
+	})
+	return plan
+}
+
+// planExisting decides what to do with a declared instance that already exists
+func planExisting(group *GroupSpec, ws *entity.Workspace) Action {
+	action := Action{Kind: ActionNone, Name: ws.Name, Group: group, Workspace: ws, Detail: strings.ToLower(ws.Status)}
+	if drift := typeDrift(group, ws); drift != "" {
+		action.Detail += "; " + drift
+	}
+
+	switch group.DesiredState() {
+	case StateRunning:
+		switch ws.Status {
+		case entity.Stopped:
+			action.Kind = ActionStart
+		case entity.Stopping:
+			action.Detail += "; re-run apply to start it once it has stopped"
+		case entity.Failure:
+			action.Detail += "; delete it to have apply recreate it"
+		}
+	case StateStopped:
+		switch ws.Status {
+		case entity.Running:
+			if ws.InstanceTypeInfo != nil && ws.InstanceTypeInfo.Stoppable {
+				action.Kind = ActionStop
+			} else {
+				action.Detail += "; instance does not support stop"
+			}
+		case entity.Starting, entity.Deploying:
+			action.Detail += "; re-run apply to stop it once it is running"
+		}
+	}
+	if action.Kind != ActionNone {
+		action.Detail = ""
+	}
+	return action
+}
+
+// typeDrift notes an existing instance whose type is not among the declared types.
+// Apply does not recreate instances to change their type.
+func typeDrift(group *GroupSpec, ws *entity.Workspace) string {
+	if len(group.Types) == 0 || ws.InstanceType == "" {
+		return ""
+	}
+	for _, t := range group.Types {
+		if t == ws.InstanceType {
+			return ""
+		}
+	}
+	return fmt.Sprintf("type %s is not declared (not changed)", ws.InstanceType)
+}
+
+func actionOrder(kind ActionKind) int {
+	switch kind {
+	case ActionCreate:
+		return 0
+	case ActionStart:
+		return 1
+	case ActionStop:
+		return 2
+	case ActionDelete:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// displayPlan prints the plan, one instance per line, followed by a summary
+func displayPlan(t *terminal.Terminal, plan Plan) {
+	width := 0
+	for _, a := range plan.Actions {
+		width = max(width, len(a.Name))
+	}
+
+	for _, a := range plan.Actions {
+		line := fmt.Sprintf("%-7s %-*s", a.Kind, width, a.Name)
+		if detail := actionDetail(a); detail != "" {
+			line += "  " + detail
+		}
+		switch a.Kind {
+		case ActionCreate:
+			t.Vprint(t.Green("  + %s", line))
+		case ActionStart, ActionStop:
+			t.Vprint(t.Yellow("  ~ %s", line))
+		case ActionDelete:
+			t.Vprint(t.Red("  - %s", line))
+		default:
+			t.Vprintf("    %s\n", line)
+		}
+	}
+
+	t.Vprintf("\nPlan: %d to create, %d to start, %d to stop, %d to delete.\n",
+		plan.Count(ActionCreate), plan.Count(ActionStart), plan.Count(ActionStop), plan.Count(ActionDelete))
+}
+
+// actionDetail describes how an instance is created, or why nothing is done
+func actionDetail(a Action) string {
+	if a.Kind != ActionCreate {
+		return a.Detail
+	}
+	g := a.Group
+	var source string
+	switch {
+	case g.Launchable != "":
+		source = "launchable " + g.Launchable
+	case len(g.Types) > 0:
+		source = strings.Join(g.Types, ", ")
+	default:
+		source = "cheapest match for filters"
+		if g.Preset != "" {
+			source += " (preset " + g.Preset + ")"
+		}
+	}
+	detail := fmt.Sprintf("%s, %s", source, g.BuildMode())
+	if a.Detail != "" {
+		detail += "; " + a.Detail
+	}
+	return detail
+}
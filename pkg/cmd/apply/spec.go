@@ -0,0 +1,206 @@
+package apply
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpucreate"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/names"
+	"gopkg.in/yaml.v3"
+)
+
+// Desired instance states
+const (
+	StateRunning = "running"
+	StateStopped = "stopped"
+)
+
+// indexPlaceholder is replaced by the 1-based instance index in group names
+const indexPlaceholder = "{i}"
+
+// FleetSpec declares the instances that should exist
+type FleetSpec struct {
+	Groups []GroupSpec `yaml:"groups"`
+}
+
+// GroupSpec declares a group of identical instances. Keys match the flags of 'brev create'.
+type GroupSpec struct {
+	// Name is the instance name, or a pattern where {i} is the 1-based index.
+	// With count > 1 and no {i}, instances are named <name>-1, <name>-2, ...
+	Name  string `yaml:"name"`
+	Count *int   `yaml:"count"`
+	// State is running (default) or stopped
+	State string `yaml:"state"`
+
+	// Types are tried in order; when empty, Filters (and Preset) pick types like 'brev create'
+	Types   []string           `yaml:"types"`
+	Filters files.SearchPreset `yaml:"filters"`
+	Preset  string             `yaml:"preset"`
+
+	Mode           string `yaml:"mode"`
+	ContainerImage string `yaml:"container-image"`
+	ComposeFile    string `yaml:"compose-file"`
+	Jupyter        *bool  `yaml:"jupyter"`
+	StartupScript  string `yaml:"startup-script"`
+	Launchable     string `yaml:"launchable"`
+}
+
+// InstanceCount returns the declared count, defaulting to 1
+func (g GroupSpec) InstanceCount() int {
+	if g.Count == nil {
+		return 1
+	}
+	return *g.Count
+}
+
+// DesiredState returns the declared state, defaulting to running
+func (g GroupSpec) DesiredState() string {
+	if g.State == "" {
+		return StateRunning
+	}
+	return g.State
+}
+
+// BuildMode returns the declared build mode, defaulting to vm
+func (g GroupSpec) BuildMode() string {
+	if g.Mode == "" {
+		return "vm"
+	}
+	return g.Mode
+}
+
+// InstanceNames expands the name pattern into the names of the group's instances
+func (g GroupSpec) InstanceNames() []string {
+	count := g.InstanceCount()
+	pattern := g.Name
+	if count > 1 && !strings.Contains(pattern, indexPlaceholder) {
+		pattern += "-" + indexPlaceholder
+	}
+	instanceNames := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		instanceNames = append(instanceNames, strings.ReplaceAll(pattern, indexPlaceholder, fmt.Sprint(i)))
+	}
+	return instanceNames
+}
+
+// ReadFleetSpec reads a fleet spec from a YAML or JSON file, or stdin when path is "-".
+// Relative @file startup scripts are resolved against the spec's directory.
+func ReadFleetSpec(path string) (*FleetSpec, error) {
+	var data []byte
+	var err error
+	baseDir := "."
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path) //nolint:gosec // user-provided spec path
+		baseDir = filepath.Dir(path)
+	}
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+
+	spec, err := ParseFleetSpec(data)
+	if err != nil {
+		return nil, err
+	}
+	for i := range spec.Groups {
+		script, err := readStartupScript(spec.Groups[i].StartupScript, baseDir)
+		if err != nil {
+			return nil, breverrors.WrapAndTrace(err)
+		}
+		spec.Groups[i].StartupScript = script
+	}
+	return spec, nil
+}
+
+// ParseFleetSpec parses and validates a fleet spec. JSON is accepted as a subset of YAML.
+// Unknown keys are rejected so typos do not silently change the fleet.
+func ParseFleetSpec(data []byte) (*FleetSpec, error) {
+	var spec FleetSpec
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, breverrors.NewValidationError("fleet spec is empty")
+		}
+		return nil, breverrors.NewValidationError(fmt.Sprintf("invalid fleet spec: %v", err))
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate checks the groups and that no two groups declare the same instance name
+func (s FleetSpec) Validate() error {
+	if len(s.Groups) == 0 {
+		return breverrors.NewValidationError("fleet spec declares no groups")
+	}
+	owner := map[string]string{}
+	for i, g := range s.Groups {
+		label := g.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		if err := g.validate(); err != nil {
+			return breverrors.NewValidationError(fmt.Sprintf("group %s: %v", label, err))
+		}
+		for _, name := range g.InstanceNames() {
+			if other, ok := owner[name]; ok {
+				return breverrors.NewValidationError(fmt.Sprintf("instance %q is declared by both group %s and group %s", name, other, label))
+			}
+			owner[name] = label
+		}
+	}
+	return nil
+}
+
+func (g GroupSpec) validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if g.InstanceCount() < 0 {
+		return fmt.Errorf("count must not be negative")
+	}
+	if state := g.DesiredState(); state != StateRunning && state != StateStopped {
+		return fmt.Errorf("invalid state %q: must be %s or %s", state, StateRunning, StateStopped)
+	}
+	for _, name := range g.InstanceNames() {
+		if err := names.ValidateNodeName(name); err != nil {
+			return err //nolint:wrapcheck // already a validation error
+		}
+	}
+	if g.Launchable != "" {
+		if _, err := gpucreate.ParseLaunchableID(g.Launchable); err != nil {
+			return err
+		}
+		return nil
+	}
+	if len(g.Types) > 0 && (len(g.Filters) > 0 || g.Preset != "") {
+		return fmt.Errorf("use either types or filters/preset, not both")
+	}
+	return gpucreate.ValidateBuildMode(g.BuildMode(), g.ContainerImage, g.ComposeFile) //nolint:wrapcheck // already a validation error
+}
+
+// readStartupScript inlines an @file startup script, resolving relative paths against baseDir
+func readStartupScript(value, baseDir string) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	path := strings.TrimPrefix(value, "@")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	content, err := os.ReadFile(path) //nolint:gosec // user-provided script path
+	if err != nil {
+		return "", breverrors.WrapAndTrace(err)
+	}
+	return string(content), nil
+}
@@ -0,0 +1,251 @@
+// Package budget checks local spending caps before instances are created or started
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/spf13/cobra"
+)
+
+// FlagHelp describes --allow-over-budget
+const FlagHelp = "Proceed even if a spending cap from the team policy or personal settings would be exceeded"
+
+// PolicyStore reads the spending caps
+type PolicyStore interface {
+	GetTeamBudgetPolicy() (*files.BudgetPolicy, string, error)
+	GetPersonalBudgetPolicy() (*files.BudgetPolicy, error)
+}
+
+// Store is everything the guardrail needs. Stores that do not implement it are not checked.
+type Store interface {
+	PolicyStore
+	GetWorkspaces(organizationID string, options *store.GetWorkspacesOptions) ([]entity.Workspace, error)
+	GetInstanceTypes(includeCPU bool) (*gpusearch.InstanceTypesResponse, error)
+}
+
+// AddFlags registers --allow-over-budget on a command
+func AddFlags(cmd *cobra.Command, allowOverBudget *bool) {
+	cmd.Flags().BoolVar(allowOverBudget, "allow-over-budget", false, FlagHelp)
+}
+
+// Caps are the effective spending caps. Zero means no cap.
+type Caps struct {
+	MaxHourly     float64
+	MaxPerCommand float64
+	OnExceed      string
+	Sources       []string // where the caps came from
+}
+
+// IsZero returns true if no cap is set
+func (c Caps) IsZero() bool {
+	return c.MaxHourly == 0 && c.MaxPerCommand == 0
+}
+
+// LoadCaps merges the team policy and personal settings. When both set a cap
+// the stricter one applies, and refuse wins over confirm.
+func LoadCaps(s PolicyStore) (Caps, error) {
+	caps := Caps{OnExceed: files.OnExceedConfirm}
+	team, path, err := s.GetTeamBudgetPolicy()
+	if err != nil {
+		return caps, breverrors.WrapAndTrace(err)
+	}
+	personal, err := s.GetPersonalBudgetPolicy()
+	if err != nil {
+		return caps, breverrors.WrapAndTrace(err)
+	}
+	if team != nil {
+		caps.merge(*team, "team policy "+path)
+	}
+	if personal != nil {
+		caps.merge(*personal, "personal settings")
+	}
+	return caps, nil
+}
+
+func (c *Caps) merge(p files.BudgetPolicy, source string) {
+	if p.MaxHourly == 0 && p.MaxPerCommand == 0 {
+		return
+	}
+	c.MaxHourly = stricter(c.MaxHourly, p.MaxHourly)
+	c.MaxPerCommand = stricter(c.MaxPerCommand, p.MaxPerCommand)
+	if p.OnExceed == files.OnExceedRefuse {
+		c.OnExceed = files.OnExceedRefuse
+	}
+	c.Sources = append(c.Sources, source)
+}
+
+func stricter(current, next float64) float64 {
+	if next > 0 && (current == 0 || next < current) {
+		return next
+	}
+	return current
+}
+
+// Planned is a number of instances a command is about to create or start.
+// When there are several candidate types the most expensive one is assumed.
+type Planned struct {
+	InstanceTypes []string
+	Count         int
+}
+
+// Spend is the hourly spend before and after the command
+type Spend struct {
+	RunningHourly float64
+	RunningCount  int
+	PlannedHourly float64
+	Unpriced      []string // instance types without a catalog price, counted as $0
+}
+
+// Violations returns a message for each cap the spend would exceed
+func (c Caps) Violations(s Spend) []string {
+	var violations []string
+	if c.MaxPerCommand > 0 && s.PlannedHourly > c.MaxPerCommand {
+		violations = append(violations, fmt.Sprintf("this command adds %s, over the per-command cap of %s", formatHourly(s.PlannedHourly), formatHourly(c.MaxPerCommand)))
+	}
+	if total := s.RunningHourly + s.PlannedHourly; c.MaxHourly > 0 && total > c.MaxHourly {
+		violations = append(violations, fmt.Sprintf("running instances would cost %s (%s for %d running + %s), over the hourly cap of %s",
+			formatHourly(total), formatHourly(s.RunningHourly), s.RunningCount, formatHourly(s.PlannedHourly), formatHourly(c.MaxHourly)))
+	}
+	return violations
+}
+
+func formatHourly(v float64) string {
+	return fmt.Sprintf("$%.2f/hr", v)
+}
+
+// CatalogPrices returns the hourly price of every instance type in the catalog
+func CatalogPrices(s Store) (map[string]float64, error) {
+	response, err := s.GetInstanceTypes(true)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	prices := map[string]float64{}
+	for _, item := range response.Items {
+		if price, err := strconv.ParseFloat(item.BasePrice.Amount, 64); err == nil {
+			prices[item.Type] = price
+		}
+	}
+	return prices, nil
+}
+
+// isBilled returns true for instances that count towards the hourly spend
+func isBilled(status string) bool {
+	return status == entity.Running || status == entity.Starting || status == entity.Deploying
+}
+
+// CurrentSpend sums the hourly price of the org's running instances and of the planned ones
+func CurrentSpend(workspaces []entity.Workspace, prices map[string]float64, planned []Planned) Spend {
+	var spend Spend
+	unpriced := map[string]bool{}
+	for _, ws := range workspaces {
+		if !isBilled(ws.Status) || ws.InstanceType == "" {
+			continue
+		}
+		spend.RunningCount++
+		price, ok := prices[ws.InstanceType]
+		if !ok {
+			unpriced[ws.InstanceType] = true
+		}
+		spend.RunningHourly += price
+	}
+	for _, p := range planned {
+		highest := 0.0
+		for _, instanceType := range p.InstanceTypes {
+			price, ok := prices[instanceType]
+			if !ok {
+				unpriced[instanceType] = true
+			}
+			highest = max(highest, price)
+		}
+		spend.PlannedHourly += highest * float64(p.Count)
+	}
+	for instanceType := range unpriced {
+		spend.Unpriced = append(spend.Unpriced, instanceType)
+	}
+	sort.Strings(spend.Unpriced)
+	return spend
+}
+
+// Options control what happens when a cap would be exceeded
+type Options struct {
+	AllowOverBudget bool
+	Confirmer       terminal.Confirmer // nil when not interactive
+	Logf            func(format string, a ...interface{})
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Caps       Caps
+	Spend      Spend
+	Violations []string
+	Overridden bool // the command continues over budget because of --allow-over-budget
+}
+
+// Check refuses, or asks before, creating or starting the planned instances
+// when that would exceed a spending cap. Stores without budget support and
+// setups without caps are not checked and return a nil result.
+func Check(s interface{}, orgID string, planned []Planned, opts Options) (*Result, error) {
+	budgetStore, ok := s.(Store)
+	if !ok {
+		return nil, nil
+	}
+	caps, err := LoadCaps(budgetStore)
+	if err != nil {
+		return nil, err
+	}
+	if caps.IsZero() {
+		return nil, nil
+	}
+
+	workspaces, err := budgetStore.GetWorkspaces(orgID, nil)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	prices, err := CatalogPrices(budgetStore)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Caps: caps, Spend: CurrentSpend(workspaces, prices, planned)}
+	result.Violations = caps.Violations(result.Spend)
+	if len(result.Spend.Unpriced) > 0 {
+		opts.Logf("Warning: no catalog price for %s; counted as $0/hr\n", strings.Join(result.Spend.Unpriced, ", "))
+	}
+	if len(result.Violations) == 0 {
+		return result, nil
+	}
+
+	for _, v := range result.Violations {
+		opts.Logf("Over budget: %s\n", v)
+	}
+	opts.Logf("Caps from %s\n", strings.Join(caps.Sources, " and "))
+
+	switch {
+	case opts.AllowOverBudget:
+		result.Overridden = true
+		opts.Logf("Continuing over budget because --allow-over-budget was set\n")
+		return result, nil
+	case caps.OnExceed == files.OnExceedRefuse:
+		return result, overBudgetError(result, "the policy refuses to exceed its caps")
+	case opts.Confirmer == nil:
+		return result, overBudgetError(result, "cannot ask for confirmation when not interactive")
+	case !opts.Confirmer.ConfirmYesNo("This exceeds your spending caps. Continue anyway?"):
+		return result, overBudgetError(result, "cancelled")
+	default:
+		return result, nil
+	}
+}
+
+func overBudgetError(result *Result, reason string) error {
+	return breverrors.NewValidationError(fmt.Sprintf("over budget (%s): %s; rerun with --allow-over-budget to proceed",
+		reason, strings.Join(result.Violations, "; ")))
+}
@@ -0,0 +1,151 @@
+package budget
+
+import (
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBudgetStore struct {
+	team       *files.BudgetPolicy
+	personal   *files.BudgetPolicy
+	workspaces []entity.Workspace
+}
+
+func (m *mockBudgetStore) GetTeamBudgetPolicy() (*files.BudgetPolicy, string, error) {
+	return m.team, "/repo/brev-policy.json", nil
+}
+
+func (m *mockBudgetStore) GetPersonalBudgetPolicy() (*files.BudgetPolicy, error) {
+	return m.personal, nil
+}
+
+func (m *mockBudgetStore) GetWorkspaces(_ string, _ *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	return m.workspaces, nil
+}
+
+func (m *mockBudgetStore) GetInstanceTypes(_ bool) (*gpusearch.InstanceTypesResponse, error) {
+	return &gpusearch.InstanceTypesResponse{Items: []gpusearch.InstanceType{
+		{Type: "g5.xlarge", BasePrice: gpusearch.BasePrice{Amount: "1.00"}},
+		{Type: "p4d.24xlarge", BasePrice: gpusearch.BasePrice{Amount: "32.00"}},
+	}}, nil
+}
+
+type mockConfirmer struct {
+	answer bool
+	asked  int
+}
+
+func (m *mockConfirmer) ConfirmYesNo(string) bool {
+	m.asked++
+	return m.answer
+}
+
+func nopLogf(string, ...interface{}) {}
+
+func TestLoadCapsStricterWins(t *testing.T) {
+	caps, err := LoadCaps(&mockBudgetStore{
+		team:     &files.BudgetPolicy{MaxHourly: 100, OnExceed: files.OnExceedRefuse},
+		personal: &files.BudgetPolicy{MaxHourly: 150, MaxPerCommand: 20},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, caps.MaxHourly)
+	assert.Equal(t, 20.0, caps.MaxPerCommand)
+	assert.Equal(t, files.OnExceedRefuse, caps.OnExceed)
+	assert.Equal(t, []string{"team policy /repo/brev-policy.json", "personal settings"}, caps.Sources)
+
+	caps, err = LoadCaps(&mockBudgetStore{})
+	require.NoError(t, err)
+	assert.True(t, caps.IsZero())
+}
+
+func TestCurrentSpend(t *testing.T) {
+	workspaces := []entity.Workspace{
+		{Status: entity.Running, InstanceType: "g5.xlarge"},
+		{Status: entity.Starting, InstanceType: "g5.xlarge"},
+		{Status: entity.Stopped, InstanceType: "p4d.24xlarge"},
+		{Status: entity.Running, InstanceType: "mystery"},
+		{Status: entity.Running}, // CPU instance without a type
+	}
+	prices := map[string]float64{"g5.xlarge": 1, "p4d.24xlarge": 32}
+
+	spend := CurrentSpend(workspaces, prices, []Planned{{InstanceTypes: []string{"g5.xlarge", "p4d.24xlarge"}, Count: 2}})
+	assert.Equal(t, 2.0, spend.RunningHourly)
+	assert.Equal(t, 3, spend.RunningCount)
+	assert.Equal(t, 64.0, spend.PlannedHourly)
+	assert.Equal(t, []string{"mystery"}, spend.Unpriced)
+}
+
+func TestViolations(t *testing.T) {
+	caps := Caps{MaxHourly: 50, MaxPerCommand: 10}
+	assert.Empty(t, caps.Violations(Spend{RunningHourly: 30, PlannedHourly: 10}))
+
+	violations := caps.Violations(Spend{RunningHourly: 30, RunningCount: 3, PlannedHourly: 32})
+	require.Len(t, violations, 2)
+	assert.Equal(t, "this command adds $32.00/hr, over the per-command cap of $10.00/hr", violations[0])
+	assert.Equal(t, "running instances would cost $62.00/hr ($30.00/hr for 3 running + $32.00/hr), over the hourly cap of $50.00/hr", violations[1])
+}
+
+func TestCheck(t *testing.T) {
+	running := []entity.Workspace{{Status: entity.Running, InstanceType: "p4d.24xlarge"}}
+	planned := []Planned{{InstanceTypes: []string{"g5.xlarge"}, Count: 4}}
+	confirmPolicy := &files.BudgetPolicy{MaxHourly: 34}
+
+	t.Run("unsupported store is not checked", func(t *testing.T) {
+		result, err := Check(struct{}{}, "org", planned, Options{Logf: nopLogf})
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("within budget", func(t *testing.T) {
+		s := &mockBudgetStore{personal: &files.BudgetPolicy{MaxHourly: 40}, workspaces: running}
+		result, err := Check(s, "org", planned, Options{Logf: nopLogf})
+		require.NoError(t, err)
+		assert.Empty(t, result.Violations)
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		confirmer := &mockConfirmer{answer: true}
+		s := &mockBudgetStore{personal: confirmPolicy, workspaces: running}
+		result, err := Check(s, "org", planned, Options{Confirmer: confirmer, Logf: nopLogf})
+		require.NoError(t, err)
+		assert.Len(t, result.Violations, 1)
+		assert.Equal(t, 1, confirmer.asked)
+		assert.False(t, result.Overridden)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		s := &mockBudgetStore{personal: confirmPolicy, workspaces: running}
+		_, err := Check(s, "org", planned, Options{Confirmer: &mockConfirmer{}, Logf: nopLogf})
+		assert.ErrorContains(t, err, "over budget (cancelled)")
+	})
+
+	t.Run("not interactive", func(t *testing.T) {
+		s := &mockBudgetStore{personal: confirmPolicy, workspaces: running}
+		_, err := Check(s, "org", planned, Options{Logf: nopLogf})
+		assert.ErrorContains(t, err, "--allow-over-budget")
+	})
+
+	t.Run("refused by policy", func(t *testing.T) {
+		confirmer := &mockConfirmer{answer: true}
+		s := &mockBudgetStore{team: &files.BudgetPolicy{MaxPerCommand: 2, OnExceed: files.OnExceedRefuse}}
+		_, err := Check(s, "org", planned, Options{Confirmer: confirmer, Logf: nopLogf})
+		assert.ErrorContains(t, err, "the policy refuses")
+		assert.Zero(t, confirmer.asked)
+	})
+
+	t.Run("allowed over budget", func(t *testing.T) {
+		var logged []string
+		logf := func(format string, a ...interface{}) { logged = append(logged, format) }
+		s := &mockBudgetStore{team: &files.BudgetPolicy{MaxPerCommand: 2, OnExceed: files.OnExceedRefuse}}
+		result, err := Check(s, "org", planned, Options{AllowOverBudget: true, Logf: logf})
+		require.NoError(t, err)
+		assert.True(t, result.Overridden)
+		assert.Contains(t, logged, "Continuing over budget because --allow-over-budget was set\n")
+	})
+}
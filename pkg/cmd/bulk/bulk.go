@@ -0,0 +1,365 @@
+// Package bulk runs start, stop, delete and reset on many instances at once
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/ls"
+	cmdutil "github.com/brevdev/brev-cli/pkg/cmd/util"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+const defaultParallel = 4
+
+// Flags are the flags the bulk commands share
+type Flags struct {
+	Selector ls.SelectorFlags
+	Parallel int
+	Yes      bool
+}
+
+// AddFlags registers the selector, --parallel and --yes flags
+func AddFlags(cmd *cobra.Command, f *Flags) {
+	ls.AddInstanceSelectorFlags(cmd, &f.Selector)
+	cmd.Flags().IntVar(&f.Parallel, "parallel", defaultParallel, "How many instances to work on at once")
+	cmd.Flags().BoolVarP(&f.Yes, "yes", "y", false, "Do not ask before acting on the instances the selectors match")
+}
+
+// Store lists the instances the selectors choose from
+type Store interface {
+	auth.CurrentUserAuthStore
+	GetActiveOrganizationOrDefault() (*entity.Organization, error)
+	GetWorkspaces(organizationID string, options *store.GetWorkspacesOptions) ([]entity.Workspace, error)
+}
+
+// Operation is what a command does to each instance
+type Operation struct {
+	Verb string // stop, as in "3 of 5 instances failed to stop"
+	Done string // stopped, shown in the results
+	// Resolve looks up an instance named on the command line or stdin
+	Resolve func(name string) (*entity.Workspace, error)
+	// Check returns why an instance cannot be acted on. Selected instances
+	// that fail it are skipped; named ones fail unless the error is a
+	// SkipError, e.g. the instance is already stopped. Optional.
+	Check func(w entity.Workspace) error
+	// Prepare runs once with every instance about to be acted on, after the
+	// confirmation, e.g. to check the budget. Optional.
+	Prepare func(workspaces []entity.Workspace) error
+	// Do acts on one instance. Calls run concurrently up to --parallel.
+	Do func(w entity.Workspace) error
+}
+
+// Options describe how the command was invoked
+type Options struct {
+	Names     []string           // instances named as arguments or on stdin
+	All       bool               // every instance in scope, as with brev stop --all
+	Confirmer terminal.Confirmer // nil when not interactive
+	Piped     bool               // stdout is piped: results go to stderr and succeeded names to stdout
+}
+
+// UseBulk returns true if the command should go through Run rather than its
+// single instance path
+func UseBulk(flags Flags, opts Options) bool {
+	return opts.All || !flags.Selector.IsZero() || len(opts.Names) > 1
+}
+
+// Prompter returns the terminal prompt, or nil when stdin is piped and cannot
+// answer it
+func Prompter(stdinPiped bool) terminal.Confirmer {
+	if stdinPiped {
+		return nil
+	}
+	return terminal.Prompter{}
+}
+
+// SkipError is returned by Check for an instance that needs nothing done, so
+// it is reported as skipped even when it was named
+type SkipError struct {
+	Reason string
+}
+
+func (e SkipError) Error() string { return e.Reason }
+
+const (
+	resultDone    = "done"
+	resultFailed  = "failed"
+	resultSkipped = "skipped"
+)
+
+// target is one instance and what happened to it
+type target struct {
+	name      string
+	workspace *entity.Workspace
+	result    string
+	err       error
+}
+
+// Run acts on the named or selected instances. Instances picked by selectors
+// or --all are previewed and confirmed unless --yes is set. Every instance is
+// attempted; the results are shown in a table and the returned error reports
+// how many failed.
+func Run(t *terminal.Terminal, s Store, op Operation, flags Flags, opts Options) error {
+	if flags.Parallel < 1 {
+		return breverrors.NewValidationError("--parallel must be at least 1")
+	}
+	selecting := opts.All || !flags.Selector.IsZero()
+	if !selecting && len(opts.Names) == 0 {
+		return breverrors.NewValidationError("instance name required: provide as argument, pipe from another command or use selectors such as --status")
+	}
+
+	targets, err := resolveTargets(s, op, flags, opts)
+	if err != nil {
+		return err
+	}
+	for i := range targets {
+		tg := &targets[i]
+		if tg.err != nil || op.Check == nil {
+			continue
+		}
+		if err := op.Check(*tg.workspace); err != nil {
+			tg.err = err
+			var skip SkipError
+			if len(opts.Names) == 0 || errors.As(err, &skip) {
+				tg.result = resultSkipped
+			} else {
+				tg.result = resultFailed
+			}
+		}
+	}
+
+	out := io.Writer(os.Stdout)
+	if opts.Piped {
+		out = os.Stderr
+	}
+	pending := pendingWorkspaces(targets)
+	if len(pending) == 0 {
+		if len(targets) == 0 {
+			_, _ = fmt.Fprintf(out, "No instances to %s\n", op.Verb)
+			return nil
+		}
+		return report(t, out, op, targets, opts.Piped)
+	}
+
+	if selecting && !flags.Yes && !opts.All {
+		_, _ = fmt.Fprintf(out, "This will %s %d instances:\n", op.Verb, len(pending))
+		renderPreview(out, targets)
+		if opts.Confirmer == nil {
+			return breverrors.NewValidationError(fmt.Sprintf("cannot ask for confirmation when not interactive; rerun with --yes to %s %d instances", op.Verb, len(pending)))
+		}
+		if !opts.Confirmer.ConfirmYesNo(fmt.Sprintf("%s %d instances?", capitalize(op.Verb), len(pending))) {
+			_, _ = fmt.Fprintln(out, "Cancelled")
+			return nil
+		}
+	}
+	if op.Prepare != nil {
+		if err := op.Prepare(pending); err != nil {
+			return err
+		}
+	}
+
+	runParallel(op, targets, flags.Parallel)
+	return report(t, out, op, targets, opts.Piped)
+}
+
+// resolveTargets looks up the named instances, or lists the instances in
+// scope, and narrows either by the selectors
+func resolveTargets(s Store, op Operation, flags Flags, opts Options) ([]target, error) {
+	cliAuth, err := auth.ResolveCLIAuth(s)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+
+	if len(opts.Names) == 0 {
+		workspaces, err := listInScope(s, cliAuth, flags)
+		if err != nil {
+			return nil, err
+		}
+		selected, err := flags.Selector.Select(cliAuth, s, workspaces, nil)
+		if err != nil {
+			return nil, err
+		}
+		targets := make([]target, 0, len(selected))
+		for i := range selected {
+			targets = append(targets, target{name: selected[i].Name, workspace: &selected[i]})
+		}
+		return targets, nil
+	}
+
+	var targets []target
+	var named []entity.Workspace
+	seen := map[string]bool{}
+	for _, name := range opts.Names {
+		w, err := op.Resolve(name)
+		switch {
+		case err != nil:
+			targets = append(targets, target{name: name, result: resultFailed, err: err})
+		case !seen[w.ID]:
+			seen[w.ID] = true
+			named = append(named, *w)
+			targets = append(targets, target{name: name, workspace: w})
+		}
+	}
+	selected, err := flags.Selector.Select(cliAuth, s, named, nil)
+	if err != nil {
+		return nil, err
+	}
+	matched := map[string]bool{}
+	for _, w := range selected {
+		matched[w.ID] = true
+	}
+	for i := range targets {
+		if tg := &targets[i]; tg.workspace != nil && !matched[tg.workspace.ID] {
+			tg.result, tg.err = resultSkipped, breverrors.NewValidationError("does not match the selectors")
+		}
+	}
+	return targets, nil
+}
+
+// listInScope returns the user's own instances, or every instance in the org
+// with API key auth or --created-by
+func listInScope(s Store, cliAuth auth.CLIAuth, flags Flags) ([]entity.Workspace, error) {
+	org, err := s.GetActiveOrganizationOrDefault()
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if org == nil {
+		return nil, breverrors.NewValidationError("no orgs exist")
+	}
+	workspaces, err := s.GetWorkspaces(org.ID, nil)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if cliAuth.IsAPIKey() || flags.Selector.CreatedBy != "" {
+		return workspaces, nil
+	}
+	if cliAuth.User() == nil {
+		return nil, breverrors.NewValidationError("user is required")
+	}
+	return store.FilterForUserWorkspaces(workspaces, cliAuth.User().ID), nil
+}
+
+func pendingWorkspaces(targets []target) []entity.Workspace {
+	var pending []entity.Workspace
+	for _, tg := range targets {
+		if tg.result == "" {
+			pending = append(pending, *tg.workspace)
+		}
+	}
+	return pending
+}
+
+// runParallel runs op.Do on every pending target with at most parallel
+// workers
+func runParallel(op Operation, targets []target, parallel int) {
+	indices := make(chan int, len(targets))
+	for i, tg := range targets {
+		if tg.result == "" {
+			indices <- i
+		}
+	}
+	close(indices)
+
+	workerCount := parallel
+	if workerCount > len(indices) {
+		workerCount = len(indices)
+	}
+	var wg sync.WaitGroup
+	for n := 0; n < workerCount; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				// each worker writes only its own targets
+				if err := op.Do(*targets[i].workspace); err != nil {
+					targets[i].result, targets[i].err = resultFailed, err
+				} else {
+					targets[i].result = resultDone
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// report prints the results and returns an error if any instance failed
+func report(t *terminal.Terminal, out io.Writer, op Operation, targets []target, piped bool) error {
+	ta := newTable(out)
+	ta.AppendHeader(table.Row{"Name", "Result", "Detail"})
+	done, failed := 0, 0
+	for _, tg := range targets {
+		result, detail := tg.result, ""
+		switch tg.result {
+		case resultDone:
+			done++
+			result = op.Done
+			if !piped {
+				result = t.Green(op.Done)
+			}
+		case resultFailed:
+			failed++
+			if !piped {
+				result = t.Red(resultFailed)
+			}
+		}
+		if tg.err != nil {
+			detail = tg.err.Error()
+		}
+		ta.AppendRow(table.Row{tg.name, result, detail})
+	}
+	ta.Render()
+	_, _ = fmt.Fprintf(out, "%s %d of %d instances\n", capitalize(op.Done), done, len(targets))
+
+	if piped {
+		for _, tg := range targets {
+			if tg.result == resultDone {
+				fmt.Println(tg.name)
+			}
+		}
+	}
+	if failed > 0 {
+		return breverrors.NewValidationError(fmt.Sprintf("%d of %d instances failed to %s", failed, len(targets), op.Verb))
+	}
+	return nil
+}
+
+// renderPreview lists the instances that will be acted on and the ones skipped
+func renderPreview(out io.Writer, targets []target) {
+	ta := newTable(out)
+	ta.AppendHeader(table.Row{"Name", "Status", "Note"})
+	for _, tg := range targets {
+		status, note := "", ""
+		if tg.workspace != nil {
+			status = tg.workspace.Status
+		}
+		if tg.err != nil {
+			note = fmt.Sprintf("%s: %s", tg.result, tg.err)
+		}
+		ta.AppendRow(table.Row{tg.name, status, note})
+	}
+	ta.Render()
+}
+
+func newTable(out io.Writer) table.Writer {
+	ta := table.NewWriter()
+	ta.SetOutputMirror(out)
+	ta.Style().Options = cmdutil.BrevTableOptions()
+	return ta
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
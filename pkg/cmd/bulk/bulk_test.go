@@ -0,0 +1,224 @@
+package bulk
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/ls"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBulkStore struct {
+	workspaces []entity.Workspace
+}
+
+func (m *mockBulkStore) GetAuthTokens() (*entity.AuthTokens, error) {
+	return &entity.AuthTokens{}, nil
+}
+
+func (m *mockBulkStore) GetCurrentUser() (*entity.User, error) {
+	return &entity.User{ID: "u1"}, nil
+}
+
+func (m *mockBulkStore) GetActiveOrganizationOrDefault() (*entity.Organization, error) {
+	return &entity.Organization{ID: "org1", Name: "org"}, nil
+}
+
+func (m *mockBulkStore) GetWorkspaces(_ string, _ *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	return m.workspaces, nil
+}
+
+type mockConfirmer struct {
+	answer bool
+	asked  int
+}
+
+func (m *mockConfirmer) ConfirmYesNo(string) bool {
+	m.asked++
+	return m.answer
+}
+
+func newTestStore() *mockBulkStore {
+	return &mockBulkStore{workspaces: []entity.Workspace{
+		{ID: "ws1", Name: "train-a", Status: entity.Running, CreatedByUserID: "u1"},
+		{ID: "ws2", Name: "train-b", Status: entity.Running, CreatedByUserID: "u1"},
+		{ID: "ws3", Name: "train-c", Status: entity.Stopped, CreatedByUserID: "u1"},
+		{ID: "ws4", Name: "train-d", Status: entity.Running, CreatedByUserID: "u2"},
+		{ID: "ws5", Name: "notebook", Status: entity.Running, CreatedByUserID: "u1"},
+	}}
+}
+
+// stopTestOperation records the instances it acts on and fails for failIDs
+func stopTestOperation(s *mockBulkStore, failIDs ...string) (Operation, *[]string) {
+	var mu sync.Mutex
+	var done []string
+	return Operation{
+		Verb: "stop",
+		Done: "stopped",
+		Resolve: func(name string) (*entity.Workspace, error) {
+			for _, w := range s.workspaces {
+				if w.Name == name {
+					return &w, nil
+				}
+			}
+			return nil, breverrors.NewValidationError("instance with id/name " + name + " not found")
+		},
+		Check: func(w entity.Workspace) error {
+			if w.Status != entity.Running {
+				return errors.New("not running")
+			}
+			return nil
+		},
+		Do: func(w entity.Workspace) error {
+			for _, id := range failIDs {
+				if w.ID == id {
+					return errors.New("api error")
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			done = append(done, w.Name)
+			return nil
+		},
+	}, &done
+}
+
+func selectorFlags(regex string) Flags {
+	return Flags{Selector: ls.SelectorFlags{NameRegex: regex}, Parallel: defaultParallel}
+}
+
+func TestRun_SelectorsConfirmAndPartialFailure(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s, "ws2")
+	confirmer := &mockConfirmer{answer: true}
+
+	err := Run(terminal.New(), s, op, selectorFlags("^train-"), Options{Confirmer: confirmer})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 3 instances failed to stop")
+	assert.Equal(t, 1, confirmer.asked)
+	// train-c is stopped and skipped, train-d belongs to someone else
+	assert.Equal(t, []string{"train-a"}, *done)
+}
+
+func TestRun_ConfirmDeclined(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s)
+
+	err := Run(terminal.New(), s, op, selectorFlags("^train-"), Options{Confirmer: &mockConfirmer{}})
+	require.NoError(t, err)
+	assert.Empty(t, *done)
+}
+
+func TestRun_NotInteractiveNeedsYes(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s)
+
+	err := Run(terminal.New(), s, op, selectorFlags("^train-"), Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--yes")
+	assert.Empty(t, *done)
+
+	flags := selectorFlags("^train-")
+	flags.Yes = true
+	require.NoError(t, Run(terminal.New(), s, op, flags, Options{}))
+	assert.ElementsMatch(t, []string{"train-a", "train-b"}, *done)
+}
+
+func TestRun_NamesAreNotConfirmed(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s)
+	confirmer := &mockConfirmer{}
+
+	err := Run(terminal.New(), s, op, Flags{Parallel: 1}, Options{Names: []string{"train-a", "nope", "train-c", "notebook"}, Confirmer: confirmer})
+	require.Error(t, err)
+	// nope is not found and train-c is named but not running
+	assert.Contains(t, err.Error(), "2 of 4 instances failed to stop")
+	assert.Equal(t, 0, confirmer.asked)
+	assert.Equal(t, []string{"train-a", "notebook"}, *done)
+}
+
+func TestRun_NamedSkipsAreNotFailures(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s)
+	op.Check = func(w entity.Workspace) error {
+		if w.Status != entity.Running {
+			return SkipError{Reason: "already stopped"}
+		}
+		return nil
+	}
+
+	err := Run(terminal.New(), s, op, Flags{Parallel: 1}, Options{Names: []string{"train-a", "train-c"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"train-a"}, *done)
+}
+
+func TestRun_NamesNarrowedBySelectors(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s)
+	flags := selectorFlags("^train-")
+	flags.Yes = true
+
+	require.NoError(t, Run(terminal.New(), s, op, flags, Options{Names: []string{"train-a", "notebook"}}))
+	assert.Equal(t, []string{"train-a"}, *done)
+}
+
+func TestRun_AllDoesNotAsk(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s)
+
+	require.NoError(t, Run(terminal.New(), s, op, Flags{Parallel: 2}, Options{All: true}))
+	assert.ElementsMatch(t, []string{"train-a", "train-b", "notebook"}, *done)
+}
+
+func TestRun_ParallelLimit(t *testing.T) {
+	s := &mockBulkStore{}
+	for i := 0; i < 8; i++ {
+		s.workspaces = append(s.workspaces, entity.Workspace{ID: string(rune('a' + i)), Name: "w" + string(rune('a'+i)), Status: entity.Running, CreatedByUserID: "u1"})
+	}
+	var running, maxRunning, calls int32
+	op := Operation{
+		Verb: "stop",
+		Done: "stopped",
+		Do: func(w entity.Workspace) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	}
+
+	require.NoError(t, Run(terminal.New(), s, op, Flags{Parallel: 3}, Options{All: true}))
+	assert.Equal(t, int32(8), calls)
+	assert.LessOrEqual(t, maxRunning, int32(3))
+
+	err := Run(terminal.New(), s, op, Flags{Parallel: 0}, Options{All: true})
+	assert.Error(t, err)
+}
+
+func TestRun_PrepareErrorStopsEverything(t *testing.T) {
+	s := newTestStore()
+	op, done := stopTestOperation(s)
+	op.Prepare = func(workspaces []entity.Workspace) error {
+		assert.Len(t, workspaces, 3)
+		return errors.New("over budget")
+	}
+
+	err := Run(terminal.New(), s, op, Flags{Parallel: 1}, Options{All: true})
+	require.Error(t, err)
+	assert.Empty(t, *done)
+}
@@ -0,0 +1,188 @@
+// Package clone creates a new instance with the configuration of an existing one
+package clone
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/budget"
+	"github.com/brevdev/brev-cli/pkg/cmd/completions"
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/cmd/util"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/names"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	cloneLong = `Create a new instance with the configuration of an existing one.
+
+The copy gets the instance type, workspace group, disk size, build mode (VM,
+k8s, container or compose), Jupyter setting, startup script, port mappings and
+firewall rules of the source, as far as the API returns them. Files and
+installed packages are not copied. Use --type to clone onto another instance
+type, and --print to see the derived configuration without creating anything;
+the printed YAML can be edited and passed to 'brev create --from-file'.
+Registry passwords are not printed: set the password-env variables the
+YAML names before creating from it.`
+
+	cloneExample = `
+  # Another instance just like my-tuned-box
+  brev clone my-tuned-box my-tuned-box-2
+
+  # Same setup on a bigger GPU
+  brev clone my-tuned-box big-box --type p4d.24xlarge
+
+  # Show what would be created
+  brev clone my-tuned-box my-tuned-box-2 --print
+
+  # Edit the configuration before creating
+  brev clone my-tuned-box my-tuned-box-2 --print > box.yaml
+  brev create --from-file box.yaml
+`
+)
+
+// CloneStore reads the source instance and creates the copy
+type CloneStore interface {
+	completions.CompletionStore
+	util.GetWorkspaceByNameOrIDErrStore
+	GetWorkspace(workspaceID string) (*entity.Workspace, error)
+	GetAllInstanceTypesWithWorkspaceGroups(orgID string) (*gpusearch.AllInstanceTypesResponse, error)
+	CreateWorkspace(organizationID string, options *store.CreateWorkspacesOptions) (*entity.Workspace, error)
+}
+
+// CloneOptions holds the options of 'brev clone'
+type CloneOptions struct {
+	Source          string
+	Name            string
+	InstanceType    string
+	Print           bool
+	AllowOverBudget bool
+}
+
+// NewCmdClone creates the clone command
+func NewCmdClone(t *terminal.Terminal, cloneStore CloneStore) *cobra.Command {
+	var opts CloneOptions
+
+	cmd := &cobra.Command{
+		Annotations:           map[string]string{"workspace": ""},
+		Use:                   "clone <instance> <new-name>",
+		DisableFlagsInUseLine: true,
+		Short:                 "Create a new instance with the configuration of an existing one",
+		Long:                  cloneLong,
+		Example:               cloneExample,
+		Args:                  cobra.ExactArgs(2),
+		ValidArgsFunction:     completions.GetAllWorkspaceNameCompletionHandler(cloneStore, t),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Source = args[0]
+			opts.Name = args[1]
+			var confirmer terminal.Confirmer
+			if !util.IsStdinPiped() {
+				confirmer = terminal.Prompter{}
+			}
+			return RunClone(t, cloneStore, confirmer, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.InstanceType, "type", "t", "", "Instance type for the copy (defaults to the type of the source)")
+	cmd.Flags().BoolVar(&opts.Print, "print", false, "Print the derived configuration as YAML instead of creating the instance")
+	budget.AddFlags(cmd, &opts.AllowOverBudget)
+
+	return cmd
+}
+
+// RunClone creates the copy, or prints its configuration with --print
+func RunClone(t *terminal.Terminal, cloneStore CloneStore, confirmer terminal.Confirmer, opts CloneOptions) error {
+	if err := names.ValidateNodeName(opts.Name); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	source, err := util.GetUserWorkspaceByNameOrIDErr(cloneStore, opts.Source)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	// the listing may leave out build details that the single instance has
+	if full, err := cloneStore.GetWorkspace(source.ID); err == nil && full != nil {
+		source = full
+	}
+
+	org, err := cloneStore.GetActiveOrganizationOrDefault()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	// Like create, a real clone needs current capacity, so skip the catalog
+	// cache; --print may use it as a dry run does
+	if !opts.Print {
+		gpusearch.ApplyCatalogCachePolicy(cloneStore, gpusearch.CatalogCachePolicy{Mode: gpusearch.CatalogCacheRefresh})
+	}
+
+	cfg := ConfigFromWorkspace(source, opts.Name)
+	if opts.InstanceType != "" {
+		cfg, err = withInstanceType(cloneStore, org.ID, cfg, opts.InstanceType)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Print {
+		out, err := yaml.Marshal(cfg)
+		if err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		fmt.Print(string(out))
+		if envs := cfg.PasswordEnvs(); len(envs) > 0 {
+			t.Eprintf("%s", t.Yellow("Registry passwords are not printed; set %s before 'brev create --from-file'\n", strings.Join(envs, ", ")))
+		}
+		return nil
+	}
+
+	if cfg.InstanceType == "" {
+		return breverrors.NewValidationError(fmt.Sprintf("the instance type of %s is unknown; pass --type", source.Name))
+	}
+	if len(cfg.Incomplete) > 0 {
+		t.Eprintf("%s", t.Yellow("Not available for %s: %s\n", source.Name, strings.Join(cfg.Incomplete, ", ")))
+	}
+
+	_, err = budget.Check(cloneStore, org.ID, []budget.Planned{{InstanceTypes: []string{cfg.InstanceType}, Count: 1}}, budget.Options{
+		AllowOverBudget: opts.AllowOverBudget,
+		Confirmer:       confirmer,
+		Logf:            t.Eprintf,
+	})
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	t.Vprintf("Cloning %s to %s (%s, %s)...\n", source.Name, t.Green(cfg.Name), cfg.InstanceType, cfg.Mode)
+	created, err := cloneStore.CreateWorkspace(org.ID, cfg.CreateOptions())
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	t.Vprintf("Created %s (ID: %s). Run 'brev ls' to check status.\n", t.Green(created.Name), created.ID)
+	if util.IsStdoutPiped() {
+		fmt.Fprintln(os.Stdout, created.Name)
+	}
+	return nil
+}
+
+// withInstanceType moves the copy to another instance type, which must have capacity
+func withInstanceType(cloneStore CloneStore, orgID string, cfg Config, instanceType string) (Config, error) {
+	types, err := cloneStore.GetAllInstanceTypesWithWorkspaceGroups(orgID)
+	if err != nil {
+		return cfg, breverrors.WrapAndTrace(err)
+	}
+	workspaceGroup := types.GetWorkspaceGroupID(instanceType)
+	if workspaceGroup == "" {
+		if !types.HasInstanceType(instanceType) {
+			return cfg, breverrors.NewValidationError(fmt.Sprintf("instance type %q is not a recognized type; run 'brev search' to see available types", instanceType))
+		}
+		return cfg, breverrors.NewValidationError(fmt.Sprintf("instance type %q is currently unavailable (no capacity)", instanceType))
+	}
+	return cfg.WithType(instanceType, workspaceGroup), nil
+}
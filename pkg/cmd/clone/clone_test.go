@@ -0,0 +1,226 @@
+package clone
+
+import (
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpucreate"
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type mockCloneStore struct {
+	CloneStore // unimplemented methods panic
+	listed     entity.Workspace
+	full       *entity.Workspace
+	types      *gpusearch.AllInstanceTypesResponse
+	created    *store.CreateWorkspacesOptions
+	policy     *gpusearch.CatalogCachePolicy
+}
+
+func (m *mockCloneStore) SetCatalogCachePolicy(policy gpusearch.CatalogCachePolicy) {
+	m.policy = &policy
+}
+
+func (m *mockCloneStore) GetAuthTokens() (*entity.AuthTokens, error) {
+	return &entity.AuthTokens{}, nil
+}
+
+func (m *mockCloneStore) GetActiveOrganizationOrDefault() (*entity.Organization, error) {
+	return &entity.Organization{ID: "org-1"}, nil
+}
+
+func (m *mockCloneStore) GetCurrentUser() (*entity.User, error) {
+	return &entity.User{ID: "user-1"}, nil
+}
+
+func (m *mockCloneStore) GetWorkspaceByNameOrID(_ string, nameOrID string) ([]entity.Workspace, error) {
+	if nameOrID != m.listed.Name && nameOrID != m.listed.ID {
+		return nil, nil
+	}
+	return []entity.Workspace{m.listed}, nil
+}
+
+func (m *mockCloneStore) GetWorkspace(_ string) (*entity.Workspace, error) {
+	return m.full, nil
+}
+
+func (m *mockCloneStore) GetAllInstanceTypesWithWorkspaceGroups(_ string) (*gpusearch.AllInstanceTypesResponse, error) {
+	return m.types, nil
+}
+
+func (m *mockCloneStore) CreateWorkspace(_ string, options *store.CreateWorkspacesOptions) (*entity.Workspace, error) {
+	m.created = options
+	return &entity.Workspace{ID: "ws-new", Name: options.Name}, nil
+}
+
+func vmWorkspace() *entity.Workspace {
+	return &entity.Workspace{
+		ID:               "ws-1",
+		Name:             "tuned",
+		CreatedByUserID:  "user-1",
+		InstanceType:     "g5.xlarge",
+		WorkspaceGroupID: "GCP",
+		WorkspaceClassID: "4x16",
+		DiskStorage:      "500Gi",
+		PortMappings:     map[string]string{"web": "8080"},
+		FirewallRules:    []entity.FirewallRule{{Port: "8080", AllowedIPs: "all"}},
+		VMBuild: &entity.VMBuild{
+			ForceJupyterInstall: false,
+			LifeCycleScriptAttr: &entity.LifeCycleScriptAttr{Script: "#!/bin/bash\necho hi\n"},
+		},
+	}
+}
+
+func TestConfigFromWorkspace(t *testing.T) {
+	cfg := ConfigFromWorkspace(vmWorkspace(), "tuned-2")
+	assert.Equal(t, "tuned", cfg.Source)
+	assert.Equal(t, "tuned-2", cfg.Name)
+	assert.Equal(t, ModeVM, cfg.Mode)
+	assert.Equal(t, "g5.xlarge", cfg.InstanceType)
+	assert.Equal(t, "500Gi", cfg.Disk)
+	assert.Equal(t, "#!/bin/bash\necho hi\n", cfg.StartupScript)
+	require.NotNil(t, cfg.Jupyter)
+	assert.False(t, *cfg.Jupyter)
+	assert.Empty(t, cfg.Incomplete)
+
+	ws := vmWorkspace()
+	ws.VMBuild.K8s = &entity.K8sConfig{}
+	assert.Equal(t, ModeK8s, ConfigFromWorkspace(ws, "k").Mode)
+
+	ws = vmWorkspace()
+	ws.VMBuild = nil
+	ws.CustomContainer = &entity.CustomContainer{ContainerURL: "nvcr.io/nvidia/pytorch:24.01-py3", EntryPoint: "bash"}
+	cfg = ConfigFromWorkspace(ws, "c")
+	assert.Equal(t, ModeContainer, cfg.Mode)
+	assert.Equal(t, "nvcr.io/nvidia/pytorch:24.01-py3", cfg.ContainerImage)
+
+	ws = vmWorkspace()
+	ws.VMBuild = nil
+	ws.DockerCompose = &entity.DockerCompose{FileURL: "https://example.com/compose.yaml", JupyterInstall: true}
+	cfg = ConfigFromWorkspace(ws, "d")
+	assert.Equal(t, ModeCompose, cfg.Mode)
+	assert.Equal(t, "https://example.com/compose.yaml", cfg.ComposeFile)
+
+	ws = vmWorkspace()
+	ws.VMBuild = nil
+	ws.DiskStorage = ""
+	cfg = ConfigFromWorkspace(ws, "e")
+	assert.Equal(t, ModeVM, cfg.Mode)
+	assert.Len(t, cfg.Incomplete, 2)
+}
+
+func TestCreateOptions(t *testing.T) {
+	options := ConfigFromWorkspace(vmWorkspace(), "tuned-2").CreateOptions()
+	assert.Equal(t, "tuned-2", options.Name)
+	assert.Equal(t, "g5.xlarge", options.InstanceType)
+	assert.Equal(t, "GCP", options.WorkspaceGroupID)
+	assert.Equal(t, "4x16", options.WorkspaceClassID)
+	assert.Equal(t, "500Gi", options.DiskStorage)
+	assert.Equal(t, map[string]string{"web": "8080"}, options.PortMappings)
+	require.Len(t, options.FirewallRules, 1)
+	require.NotNil(t, options.VMBuild)
+	assert.False(t, options.VMBuild.ForceJupyterInstall)
+	assert.Equal(t, "#!/bin/bash\necho hi\n", options.VMBuild.LifeCycleScriptAttr.Script)
+
+	ws := vmWorkspace()
+	ws.VMBuild = nil
+	ws.CustomContainer = &entity.CustomContainer{ContainerURL: "ubuntu:22.04"}
+	options = ConfigFromWorkspace(ws, "c").CreateOptions()
+	assert.Nil(t, options.VMBuild)
+	require.NotNil(t, options.CustomContainer)
+	assert.Equal(t, "ubuntu:22.04", options.CustomContainer.ContainerURL)
+}
+
+func TestConfigYAMLHidesRegistries(t *testing.T) {
+	ws := vmWorkspace()
+	ws.VMBuild = nil
+	ws.CustomContainer = &entity.CustomContainer{ContainerURL: "private/image", Registry: &entity.Registry{Username: "me", Password: "secret"}}
+	out, err := yaml.Marshal(ConfigFromWorkspace(ws, "c"))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "container-image: private/image")
+	assert.NotContains(t, string(out), "secret")
+
+	// the printed placeholder carries the credentials through create --from-file
+	t.Setenv(RegistryPasswordEnv, "secret")
+	spec, err := gpucreate.ParseCreateSpec(out)
+	require.NoError(t, err)
+	require.NotNil(t, spec.Registry)
+	assert.Equal(t, "me", spec.Registry.Username)
+	assert.Equal(t, "secret", spec.Registry.Password)
+}
+
+func TestRunClone(t *testing.T) {
+	term := terminal.New()
+	full := vmWorkspace()
+	s := &mockCloneStore{listed: entity.Workspace{ID: full.ID, Name: full.Name, CreatedByUserID: "user-1"}, full: full}
+
+	err := RunClone(term, s, nil, CloneOptions{Source: "tuned", Name: "tuned-2"})
+	require.NoError(t, err)
+	require.NotNil(t, s.created)
+	assert.Equal(t, "tuned-2", s.created.Name)
+	assert.Equal(t, "g5.xlarge", s.created.InstanceType)
+	assert.Equal(t, "500Gi", s.created.DiskStorage)
+}
+
+func TestRunCloneWithType(t *testing.T) {
+	term := terminal.New()
+	full := vmWorkspace()
+	s := &mockCloneStore{
+		listed: entity.Workspace{ID: full.ID, Name: full.Name, CreatedByUserID: "user-1"},
+		full:   full,
+		types: &gpusearch.AllInstanceTypesResponse{AllInstanceTypes: []gpusearch.InstanceType{
+			{Type: "p4d.24xlarge", WorkspaceGroups: []gpusearch.WorkspaceGroup{{ID: "aws-group"}}},
+			{Type: "h100.8x"},
+		}},
+	}
+
+	err := RunClone(term, s, nil, CloneOptions{Source: "tuned", Name: "big", InstanceType: "p4d.24xlarge"})
+	require.NoError(t, err)
+	assert.Equal(t, "p4d.24xlarge", s.created.InstanceType)
+	assert.Equal(t, "aws-group", s.created.WorkspaceGroupID)
+	// capacity is checked against a fresh catalog, as brev create does
+	require.NotNil(t, s.policy)
+	assert.Equal(t, gpusearch.CatalogCacheRefresh, s.policy.Mode)
+
+	s.created, s.policy = nil, nil
+	require.NoError(t, RunClone(term, s, nil, CloneOptions{Source: "tuned", Name: "big", InstanceType: "p4d.24xlarge", Print: true}))
+	assert.Nil(t, s.policy)
+
+	s.created = nil
+	err = RunClone(term, s, nil, CloneOptions{Source: "tuned", Name: "big", InstanceType: "h100.8x"})
+	assert.ErrorContains(t, err, "unavailable")
+	err = RunClone(term, s, nil, CloneOptions{Source: "tuned", Name: "big", InstanceType: "nope"})
+	assert.ErrorContains(t, err, "not a recognized type")
+	assert.Nil(t, s.created)
+}
+
+func TestRunCloneErrors(t *testing.T) {
+	term := terminal.New()
+	s := &mockCloneStore{listed: entity.Workspace{ID: "ws-1", Name: "tuned", CreatedByUserID: "user-1"}}
+
+	assert.Error(t, RunClone(term, s, nil, CloneOptions{Source: "tuned", Name: "Bad Name!"}))
+	assert.Error(t, RunClone(term, s, nil, CloneOptions{Source: "missing", Name: "copy"}))
+	// the listing has no instance type and GetWorkspace returns nothing more
+	assert.ErrorContains(t, RunClone(term, s, nil, CloneOptions{Source: "tuned", Name: "copy"}), "--type")
+	assert.Nil(t, s.created)
+}
+
+func TestConfigYAMLIsACreateSpec(t *testing.T) {
+	out, err := yaml.Marshal(ConfigFromWorkspace(vmWorkspace(), "tuned-2"))
+	require.NoError(t, err)
+
+	spec, err := gpucreate.ParseCreateSpec(out)
+	require.NoError(t, err)
+	assert.Equal(t, "tuned-2", spec.Name)
+	assert.Equal(t, "g5.xlarge", spec.Type)
+	assert.Equal(t, "500Gi", spec.Disk)
+	assert.Equal(t, ModeVM, spec.Mode)
+	assert.Equal(t, "#!/bin/bash\necho hi\n", spec.StartupScript)
+	require.Len(t, spec.FirewallRules, 1)
+	assert.Equal(t, "8080", spec.FirewallRules[0].Port)
+}
@@ -0,0 +1,215 @@
+package clone
+
+import (
+	"fmt"
+
+	"github.com/brevdev/brev-cli/pkg/config"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/store"
+)
+
+// Build modes, as accepted by brev create --mode
+const (
+	ModeVM        = "vm"
+	ModeK8s       = "k8s"
+	ModeContainer = "container"
+	ModeCompose   = "compose"
+)
+
+// Config is the configuration of an instance, derived from an existing one.
+// Keys match the brev create flags where there is one.
+type Config struct {
+	Source          string            `yaml:"source"`
+	Name            string            `yaml:"name"`
+	InstanceType    string            `yaml:"type"`
+	WorkspaceGroup  string            `yaml:"workspace-group,omitempty"`
+	Disk            string            `yaml:"disk,omitempty"`
+	Mode            string            `yaml:"mode"`
+	Jupyter         *bool             `yaml:"jupyter,omitempty"`
+	StartupScript   string            `yaml:"startup-script,omitempty"`
+	StartupScriptID string            `yaml:"startup-script-id,omitempty"` // a launchable lifecycle script referenced by ID
+	ContainerImage  string            `yaml:"container-image,omitempty"`
+	Entrypoint      string            `yaml:"entrypoint,omitempty"`
+	Registry        *Registry         `yaml:"registry,omitempty"`
+	ComposeFile     string            `yaml:"compose-file,omitempty"`
+	ComposeYAML     string            `yaml:"compose-yaml,omitempty"`
+	Env             map[string]string `yaml:"env,omitempty"` // compose environment variables
+	Registries      []Registry        `yaml:"registries,omitempty"`
+	Ports           map[string]string `yaml:"ports,omitempty"`
+	FirewallRules   []FirewallRule    `yaml:"firewall-rules,omitempty"`
+	JupyterOnStart  bool              `yaml:"jupyter-on-start,omitempty"`
+	// Incomplete lists what the API did not return for the source instance
+	Incomplete []string `yaml:"incomplete,omitempty"`
+
+	// copied to the create request but not printed; registries hold
+	// credentials, so only their placeholders above are printed
+	workspaceClassID    string
+	workspaceTemplateID string
+	registry            *entity.Registry
+	registries          []*entity.Registry
+	k8s                 *entity.K8sConfig
+	lifeCycleScript     *entity.LifeCycleScriptAttr
+}
+
+// Registry is a registry of the source printed without its password.
+// PasswordEnv names the environment variable brev create --from-file reads
+// the password from.
+type Registry struct {
+	URL         string `yaml:"url"`
+	Username    string `yaml:"username,omitempty"`
+	PasswordEnv string `yaml:"password-env,omitempty"`
+}
+
+// RegistryPasswordEnv is the variable the printed container registry reads
+// its password from; compose registries add _1, _2, ...
+const RegistryPasswordEnv = "REGISTRY_PASSWORD"
+
+func printedRegistry(r *entity.Registry, passwordEnv string) Registry {
+	printed := Registry{URL: r.Url, Username: r.Username}
+	if r.Password != "" {
+		printed.PasswordEnv = passwordEnv
+	}
+	return printed
+}
+
+// PasswordEnvs lists the environment variables the printed registries need
+func (c Config) PasswordEnvs() []string {
+	var envs []string
+	if c.Registry != nil && c.Registry.PasswordEnv != "" {
+		envs = append(envs, c.Registry.PasswordEnv)
+	}
+	for _, r := range c.Registries {
+		if r.PasswordEnv != "" {
+			envs = append(envs, r.PasswordEnv)
+		}
+	}
+	return envs
+}
+
+// FirewallRule is a firewall rule of the instance
+type FirewallRule struct {
+	Port       string   `yaml:"port"`
+	AllowedIPs string   `yaml:"allowed-ips"`
+	ClientIPs  []string `yaml:"client-ips,omitempty"`
+}
+
+// ConfigFromWorkspace derives the configuration for a copy of ws named name
+func ConfigFromWorkspace(ws *entity.Workspace, name string) Config {
+	c := Config{
+		Source:              ws.Name,
+		Name:                name,
+		InstanceType:        ws.InstanceType,
+		WorkspaceGroup:      ws.WorkspaceGroupID,
+		Disk:                ws.DiskStorage,
+		JupyterOnStart:      ws.LaunchJupyterOnStart,
+		workspaceClassID:    ws.WorkspaceClassID,
+		workspaceTemplateID: ws.WorkspaceTemplate.ID,
+	}
+	if len(ws.PortMappings) > 0 {
+		c.Ports = ws.PortMappings
+	}
+	for _, rule := range ws.FirewallRules {
+		c.FirewallRules = append(c.FirewallRules, FirewallRule{Port: rule.Port, AllowedIPs: rule.AllowedIPs, ClientIPs: rule.ClientIPs})
+	}
+
+	switch {
+	case ws.CustomContainer != nil:
+		c.Mode = ModeContainer
+		c.ContainerImage = ws.CustomContainer.ContainerURL
+		c.Entrypoint = ws.CustomContainer.EntryPoint
+		c.registry = ws.CustomContainer.Registry
+		if c.registry != nil {
+			printed := printedRegistry(c.registry, RegistryPasswordEnv)
+			c.Registry = &printed
+		}
+	case ws.DockerCompose != nil:
+		c.Mode = ModeCompose
+		c.ComposeFile = ws.DockerCompose.FileURL
+		c.ComposeYAML = ws.DockerCompose.YamlString
+		c.Env = ws.DockerCompose.EnvironmentVariables
+		c.registries = ws.DockerCompose.Registries
+		for i, r := range c.registries {
+			if r != nil {
+				c.Registries = append(c.Registries, printedRegistry(r, fmt.Sprintf("%s_%d", RegistryPasswordEnv, i+1)))
+			}
+		}
+		jupyter := ws.DockerCompose.JupyterInstall
+		c.Jupyter = &jupyter
+	case ws.VMBuild != nil:
+		c.Mode = ModeVM
+		if ws.VMBuild.K8s != nil && !ws.VMBuild.K8s.IsDisabled {
+			c.Mode = ModeK8s
+			c.k8s = ws.VMBuild.K8s
+		}
+		jupyter := ws.VMBuild.ForceJupyterInstall
+		c.Jupyter = &jupyter
+		if attr := ws.VMBuild.LifeCycleScriptAttr; attr != nil {
+			c.lifeCycleScript = attr
+			c.StartupScript = attr.Script
+			c.StartupScriptID = attr.ID
+		}
+	default:
+		c.Mode = ModeVM
+		c.Incomplete = append(c.Incomplete, "build configuration (using a default VM build)")
+	}
+
+	if c.InstanceType == "" {
+		c.Incomplete = append(c.Incomplete, "instance type")
+	}
+	if c.Disk == "" {
+		c.Incomplete = append(c.Incomplete, "disk size (using the default)")
+	}
+	return c
+}
+
+// WithType switches the copy to another instance type in workspaceGroup
+func (c Config) WithType(instanceType, workspaceGroup string) Config {
+	c.InstanceType = instanceType
+	c.WorkspaceGroup = workspaceGroup
+	return c
+}
+
+// CreateOptions builds the create request for the copy
+func (c Config) CreateOptions() *store.CreateWorkspacesOptions {
+	options := store.NewCreateWorkspacesOptions(config.GlobalConfig.GetDefaultClusterID(), c.Name)
+	options.WithInstanceType(c.InstanceType)
+	options.WorkspaceGroupID = c.WorkspaceGroup
+	if c.workspaceClassID != "" {
+		options.WorkspaceClassID = c.workspaceClassID
+	}
+	if c.workspaceTemplateID != "" {
+		options.WorkspaceTemplateID = c.workspaceTemplateID
+	}
+	if c.Disk != "" {
+		options.DiskStorage = c.Disk
+	}
+	options.LaunchJupyterOnStart = c.JupyterOnStart
+	if len(c.Ports) > 0 {
+		options.PortMappings = c.Ports
+	}
+	for _, rule := range c.FirewallRules {
+		options.FirewallRules = append(options.FirewallRules, store.CreateFirewallRule{Port: rule.Port, AllowedIPs: rule.AllowedIPs, ClientIPs: rule.ClientIPs})
+	}
+
+	jupyter := c.Jupyter != nil && *c.Jupyter
+	switch c.Mode {
+	case ModeContainer:
+		options.VMBuild = nil
+		options.CustomContainer = &store.CustomContainer{ContainerURL: c.ContainerImage, EntryPoint: c.Entrypoint, Registry: c.registry}
+	case ModeCompose:
+		options.VMBuild = nil
+		options.DockerCompose = &store.DockerCompose{
+			FileURL:              c.ComposeFile,
+			YamlString:           c.ComposeYAML,
+			JupyterInstall:       jupyter,
+			EnvironmentVariables: c.Env,
+			Registries:           c.registries,
+		}
+	default:
+		if c.Jupyter == nil {
+			jupyter = true
+		}
+		options.VMBuild = &store.VMBuild{ForceJupyterInstall: jupyter, K8s: c.k8s, LifeCycleScriptAttr: c.lifeCycleScript}
+	}
+	return options
+}
@@ -8,11 +8,14 @@ import (
 	"github.com/brevdev/brev-cli/pkg/auth"
 	"github.com/brevdev/brev-cli/pkg/cmd/agentskill"
 	analyticscmd "github.com/brevdev/brev-cli/pkg/cmd/analytics"
+	"github.com/brevdev/brev-cli/pkg/cmd/apply"
 	"github.com/brevdev/brev-cli/pkg/cmd/background"
 	"github.com/brevdev/brev-cli/pkg/cmd/clipboard"
+	"github.com/brevdev/brev-cli/pkg/cmd/clone"
 	"github.com/brevdev/brev-cli/pkg/cmd/configureenvvars"
 	"github.com/brevdev/brev-cli/pkg/cmd/connect"
 	"github.com/brevdev/brev-cli/pkg/cmd/copy"
+	"github.com/brevdev/brev-cli/pkg/cmd/cost"
 	"github.com/brevdev/brev-cli/pkg/cmd/delete"
 	"github.com/brevdev/brev-cli/pkg/cmd/deregister"
 	"github.com/brevdev/brev-cli/pkg/cmd/enablessh"
@@ -45,6 +48,7 @@ import (
 	"github.com/brevdev/brev-cli/pkg/cmd/revokessh"
 	"github.com/brevdev/brev-cli/pkg/cmd/runtasks"
 	"github.com/brevdev/brev-cli/pkg/cmd/scale"
+	"github.com/brevdev/brev-cli/pkg/cmd/schedule"
 	"github.com/brevdev/brev-cli/pkg/cmd/set"
 	"github.com/brevdev/brev-cli/pkg/cmd/setupworkspace"
 	"github.com/brevdev/brev-cli/pkg/cmd/shell"
@@ -57,6 +61,7 @@ import (
 	"github.com/brevdev/brev-cli/pkg/cmd/updatemodel"
 	"github.com/brevdev/brev-cli/pkg/cmd/upgrade"
 	"github.com/brevdev/brev-cli/pkg/cmd/version"
+	"github.com/brevdev/brev-cli/pkg/cmd/wait"
 	"github.com/brevdev/brev-cli/pkg/cmd/writeconnectionevent"
 	"github.com/brevdev/brev-cli/pkg/config"
 	"github.com/brevdev/brev-cli/pkg/entity"
@@ -308,8 +313,11 @@ func createCmdTree(cmd *cobra.Command, t *terminal.Terminal, loginCmdStore *stor
 		_ = 0 // noop
 	}
 	cmd.AddCommand(scale.NewCmdScale(t, noLoginCmdStore))
-	cmd.AddCommand(gpusearch.NewCmdGPUSearch(t, noLoginCmdStore))
+	cmd.AddCommand(gpusearch.NewCmdGPUSearch(t, noLoginCmdStore, gpucreate.NewSearchPickHandler(t, loginCmdStore)))
 	cmd.AddCommand(gpucreate.NewCmdGPUCreate(t, loginCmdStore))
+	cmd.AddCommand(cost.NewCmdCost(t, noLoginCmdStore))
+	cmd.AddCommand(apply.NewCmdApply(t, loginCmdStore))
+	cmd.AddCommand(clone.NewCmdClone(t, loginCmdStore))
 	cmd.AddCommand(configureenvvars.NewCmdConfigureEnvVars(t, loginCmdStore))
 	cmd.AddCommand(importideconfig.NewCmdImportIDEConfig(t, noLoginCmdStore))
 	cmd.AddCommand(shell.NewCmdShell(t, loginCmdStore, noLoginCmdStore))
@@ -326,6 +334,8 @@ func createCmdTree(cmd *cobra.Command, t *terminal.Terminal, loginCmdStore *stor
 	cmd.AddCommand(stop.NewCmdStop(t, loginCmdStore, noLoginCmdStore))
 	cmd.AddCommand(delete.NewCmdDelete(t, loginCmdStore, noLoginCmdStore))
 	cmd.AddCommand(reset.NewCmdReset(t, loginCmdStore, noLoginCmdStore))
+	cmd.AddCommand(wait.NewCmdWait(t, loginCmdStore, noLoginCmdStore))
+	cmd.AddCommand(schedule.NewCmdSchedule(t, loginCmdStore, noLoginCmdStore))
 	cmd.AddCommand(profile.NewCmdProfile(t, loginCmdStore, noLoginCmdStore))
 	cmd.AddCommand(refresh.NewCmdRefresh(t, loginCmdStore))
 	cmd.AddCommand(register.NewCmdRegister(t, externalNodeCmdStore))
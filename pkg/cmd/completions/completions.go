@@ -1,7 +1,11 @@
 package completions
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
 	"github.com/brevdev/brev-cli/pkg/entity"
 	"github.com/brevdev/brev-cli/pkg/store"
 	"github.com/brevdev/brev-cli/pkg/terminal"
@@ -74,3 +78,36 @@ func GetOrgsNameCompletionHandler(completionStore CompletionStore, t *terminal.T
 		return orgNames, cobra.ShellCompDirectiveDefault
 	}
 }
+
+// InstanceTypeCompletionStore lists instance types; listings are served from the
+// local catalog cache when it is fresh
+type InstanceTypeCompletionStore interface {
+	GetInstanceTypes(includeCPU bool) (*gpusearch.InstanceTypesResponse, error)
+}
+
+// GetInstanceTypeCompletionHandler completes comma-separated instance type names
+func GetInstanceTypeCompletionHandler(instanceTypeStore InstanceTypeCompletionStore) CompletionHandler {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		response, err := instanceTypeStore.GetInstanceTypes(true)
+		if err != nil || response == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		prefix := ""
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix = toComplete[:i+1]
+		}
+
+		seen := map[string]bool{}
+		var types []string
+		for _, it := range response.Items {
+			if seen[it.Type] {
+				continue
+			}
+			seen[it.Type] = true
+			types = append(types, prefix+it.Type)
+		}
+		sort.Strings(types)
+		return types, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
@@ -1,6 +1,7 @@
 package copy
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -320,21 +321,15 @@ func copyExternalNode(t *terminal.Terminal, cstore CopyStore, node *nodev1.Exter
 }
 
 func pollUntil(s *spinner.Spinner, wsid string, state string, copyStore CopyStore, waitMsg string) error {
-	isReady := false
 	s.Suffix = waitMsg
 	s.Start()
-	for !isReady {
-		time.Sleep(5 * time.Second)
+	defer s.Stop()
+	err := util.Poll(context.Background(), util.DefaultPollOptions, func() (bool, error) {
 		ws, err := copyStore.GetWorkspace(wsid)
 		if err != nil {
-			s.Stop()
-			return breverrors.WrapAndTrace(err)
-		}
-		s.Suffix = waitMsg
-		if ws.Status == state {
-			isReady = true
+			return false, breverrors.WrapAndTrace(err)
 		}
-	}
-	s.Stop()
-	return nil
+		return ws.Status == state, nil
+	})
+	return breverrors.WrapAndTrace(err)
 }
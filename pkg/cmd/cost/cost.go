@@ -0,0 +1,374 @@
+// Package cost provides commands to project spend from the instance-type catalog
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var (
+	costLong = `Project spend for planned workloads using catalog prices.`
+
+	estimateLong = `Estimate the cost of running instances for a number of hours.
+
+Prices come from the instance-type catalog (the same data as 'brev search').
+Each instance is billed from creation, so the estimated boot time of the type
+is added to the requested hours as overhead. Storage is not included.
+
+Pick the instance type with --type, or describe what you need with search
+filters (--gpu-name, --min-vram, --where, ...) to estimate the cheapest match.
+The cheapest matching alternatives are listed for comparison; with --type they
+are types with at least as much total VRAM and compute capability.`
+
+	estimateExample = `
+  # 4x g5.xlarge for 36 hours
+  brev cost estimate --type g5.xlarge --count 4 --hours 36
+
+  # Cheapest type with 80GB GPUs for a 2-node, 12 hour run
+  brev cost estimate --min-vram 80 --count 2 --hours 12
+
+  # Compare more alternatives, as JSON
+  brev cost estimate --type p4d.24xlarge --hours 24 --alternatives 10 --json
+`
+)
+
+// CostStore fetches the instance-type catalog
+type CostStore interface {
+	gpusearch.GPUSearchStore
+}
+
+// estimateFlags holds the flag values of 'brev cost estimate'
+type estimateFlags struct {
+	instanceType  string
+	count         int
+	hours         float64
+	alternatives  int
+	gpuName       string
+	provider      string
+	minVRAM       float64
+	minTotalVRAM  float64
+	minCapability float64
+	minDisk       float64
+	maxBootTime   int
+	stoppable     bool
+	where         string
+	jsonOutput    bool
+	cache         gpusearch.CatalogCacheFlags
+}
+
+// hasFilters returns true if any search filter was set
+func (f *estimateFlags) hasFilters() bool {
+	return f.gpuName != "" || f.provider != "" || f.minVRAM > 0 || f.minTotalVRAM > 0 ||
+		f.minCapability > 0 || f.minDisk > 0 || f.maxBootTime > 0 || f.stoppable || f.where != ""
+}
+
+// NewCmdCost creates the cost command
+func NewCmdCost(t *terminal.Terminal, costStore CostStore) *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations:           map[string]string{"workspace": ""},
+		Use:                   "cost",
+		DisableFlagsInUseLine: true,
+		Short:                 "Estimate instance costs",
+		Long:                  costLong,
+	}
+	cmd.AddCommand(newCmdEstimate(t, costStore))
+	return cmd
+}
+
+// newCmdEstimate creates the 'cost estimate' subcommand
+func newCmdEstimate(t *terminal.Terminal, costStore CostStore) *cobra.Command {
+	var flags estimateFlags
+
+	cmd := &cobra.Command{
+		Use:                   "estimate",
+		DisableFlagsInUseLine: true,
+		Short:                 "Estimate the cost of running instances for a number of hours",
+		Long:                  estimateLong,
+		Example:               estimateExample,
+		Args:                  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := flags.cache.Policy()
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			gpusearch.ApplyCatalogCachePolicy(costStore, policy)
+			return RunEstimate(t, costStore, &flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.instanceType, "type", "", "Instance type to estimate (e.g., g5.xlarge)")
+	cmd.Flags().IntVar(&flags.count, "count", 1, "Number of instances")
+	cmd.Flags().Float64Var(&flags.hours, "hours", 0, "How many hours each instance runs (required)")
+	cmd.Flags().IntVar(&flags.alternatives, "alternatives", 5, "Number of cheaper or comparable alternatives to show (0 to hide)")
+	cmd.Flags().StringVarP(&flags.gpuName, "gpu-name", "g", "", "Filter by GPU name (case-insensitive, partial match)")
+	cmd.Flags().StringVarP(&flags.provider, "provider", "p", "", "Filter by provider/cloud (case-insensitive, partial match)")
+	cmd.Flags().Float64VarP(&flags.minVRAM, "min-vram", "v", 0, "Minimum VRAM per GPU in GB")
+	cmd.Flags().Float64Var(&flags.minTotalVRAM, "min-total-vram", 0, "Minimum total VRAM (GPU count * VRAM) in GB")
+	cmd.Flags().Float64Var(&flags.minCapability, "min-capability", 0, "Minimum GPU compute capability (e.g., 8.0 for Ampere)")
+	cmd.Flags().Float64Var(&flags.minDisk, "min-disk", 0, "Minimum disk size in GB")
+	cmd.Flags().IntVar(&flags.maxBootTime, "max-boot-time", 0, "Maximum boot time in minutes")
+	cmd.Flags().BoolVar(&flags.stoppable, "stoppable", false, "Only consider instances that can be stopped and restarted")
+	cmd.Flags().StringVar(&flags.where, "where", "", `Filter expression, e.g. 'vram>=80 && provider in ("aws","gcp")'`)
+	cmd.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output the estimate as JSON")
+	gpusearch.AddCatalogCacheFlags(cmd, &flags.cache)
+
+	return cmd
+}
+
+// Estimate is the projected cost of running count instances of one type for a number of hours
+type Estimate struct {
+	Type              string  `json:"type"`
+	Provider          string  `json:"provider"`
+	GPUName           string  `json:"gpu_name"`
+	GPUCount          int     `json:"gpu_count"`
+	TotalVRAM         float64 `json:"total_vram_gb"`
+	PricePerHour      float64 `json:"price_per_hour"`
+	Count             int     `json:"count"`
+	Hours             float64 `json:"hours"`
+	BootOverheadHours float64 `json:"boot_overhead_hours"`
+	ComputeCost       float64 `json:"compute_cost"`
+	BootOverheadCost  float64 `json:"boot_overhead_cost"`
+	TotalCost         float64 `json:"total_cost"`
+	HourlyBurn        float64 `json:"hourly_burn"`
+	DeltaVsSelected   float64 `json:"delta_vs_selected,omitempty"`
+}
+
+// EstimateReport is the result of 'brev cost estimate'
+type EstimateReport struct {
+	Selected     Estimate   `json:"selected"`
+	Alternatives []Estimate `json:"alternatives"`
+}
+
+// EstimateCost projects the spend of count instances of inst running for hours,
+// including the boot-time overhead billed before each instance is usable.
+func EstimateCost(inst gpusearch.GPUInstanceInfo, count int, hours float64) Estimate {
+	boot := float64(inst.BootTime) / 3600
+	n := float64(count)
+	return Estimate{
+		Type:              inst.Type,
+		Provider:          inst.Provider,
+		GPUName:           inst.GPUName,
+		GPUCount:          inst.GPUCount,
+		TotalVRAM:         inst.TotalVRAM,
+		PricePerHour:      inst.PricePerHour,
+		Count:             count,
+		Hours:             hours,
+		BootOverheadHours: boot,
+		ComputeCost:       inst.PricePerHour * n * hours,
+		BootOverheadCost:  inst.PricePerHour * n * boot,
+		TotalCost:         inst.PricePerHour * n * (hours + boot),
+		HourlyBurn:        inst.PricePerHour * n,
+	}
+}
+
+// RunEstimate resolves the instance type (or cheapest match) and prints the estimate
+func RunEstimate(t *terminal.Terminal, costStore CostStore, flags *estimateFlags) error {
+	if flags.hours <= 0 {
+		return breverrors.NewValidationError("--hours must be greater than 0")
+	}
+	if flags.count < 1 {
+		return breverrors.NewValidationError("--count must be at least 1")
+	}
+	if flags.instanceType == "" && !flags.hasFilters() {
+		return breverrors.NewValidationError("specify --type or at least one search filter (e.g. --gpu-name, --min-vram, --where)")
+	}
+	whereExpr, err := gpusearch.ParseWhereFlag(flags.where)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	response, err := costStore.GetInstanceTypes(true)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	if response == nil || len(response.Items) == 0 {
+		return breverrors.NewValidationError("no instance types found")
+	}
+	instances := uniqueByType(gpusearch.ProcessInstances(response.Items))
+
+	report, err := buildReport(instances, flags, whereExpr)
+	if err != nil {
+		return err
+	}
+
+	if flags.jsonOutput {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+	displayReport(t, report)
+	return nil
+}
+
+// buildReport selects the estimated type and its cheapest alternatives
+func buildReport(instances []gpusearch.GPUInstanceInfo, flags *estimateFlags, whereExpr *gpusearch.WhereExpr) (*EstimateReport, error) {
+	gpuOnly := flags.gpuName != "" || flags.minVRAM > 0 || flags.minTotalVRAM > 0 || flags.minCapability > 0
+	candidates := gpusearch.FilterInstances(instances, flags.gpuName, flags.provider, "", flags.minVRAM, flags.minTotalVRAM, flags.minCapability, 0, flags.minDisk, 0, flags.maxBootTime, flags.stoppable, false, false, gpuOnly)
+	candidates = gpusearch.FilterWhere(candidates, whereExpr)
+	candidates = pricedOnly(candidates)
+
+	var selected gpusearch.GPUInstanceInfo
+	if flags.instanceType != "" {
+		inst, ok := findType(instances, flags.instanceType)
+		if !ok {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("instance type %q not found in the catalog (see 'brev search')", flags.instanceType))
+		}
+		selected = inst
+		if !flags.hasFilters() {
+			candidates = comparableTo(pricedOnly(instances), selected)
+		}
+	} else {
+		if len(candidates) == 0 {
+			return nil, breverrors.NewValidationError("no instance types match the specified filters")
+		}
+		sortByTotal(candidates, flags.count, flags.hours)
+		selected = candidates[0]
+	}
+
+	report := &EstimateReport{Selected: EstimateCost(selected, flags.count, flags.hours), Alternatives: []Estimate{}}
+	sortByTotal(candidates, flags.count, flags.hours)
+	for _, inst := range candidates {
+		if len(report.Alternatives) >= flags.alternatives {
+			break
+		}
+		if inst.Type == selected.Type {
+			continue
+		}
+		alt := EstimateCost(inst, flags.count, flags.hours)
+		alt.DeltaVsSelected = alt.TotalCost - report.Selected.TotalCost
+		report.Alternatives = append(report.Alternatives, alt)
+	}
+	return report, nil
+}
+
+// uniqueByType keeps the first entry of each instance type
+func uniqueByType(instances []gpusearch.GPUInstanceInfo) []gpusearch.GPUInstanceInfo {
+	seen := map[string]bool{}
+	var out []gpusearch.GPUInstanceInfo
+	for _, inst := range instances {
+		if seen[inst.Type] {
+			continue
+		}
+		seen[inst.Type] = true
+		out = append(out, inst)
+	}
+	return out
+}
+
+// pricedOnly drops instances without a known price
+func pricedOnly(instances []gpusearch.GPUInstanceInfo) []gpusearch.GPUInstanceInfo {
+	var out []gpusearch.GPUInstanceInfo
+	for _, inst := range instances {
+		if inst.PricePerHour > 0 {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
+
+// findType looks up an instance type by name (case-insensitive)
+func findType(instances []gpusearch.GPUInstanceInfo, instanceType string) (gpusearch.GPUInstanceInfo, bool) {
+	for _, inst := range instances {
+		if strings.EqualFold(inst.Type, instanceType) {
+			return inst, true
+		}
+	}
+	return gpusearch.GPUInstanceInfo{}, false
+}
+
+// comparableTo returns instances that can stand in for selected: for GPU types at
+// least the same total VRAM and capability, for CPU types at least the same vCPUs and RAM
+func comparableTo(instances []gpusearch.GPUInstanceInfo, selected gpusearch.GPUInstanceInfo) []gpusearch.GPUInstanceInfo {
+	var out []gpusearch.GPUInstanceInfo
+	for _, inst := range instances {
+		if selected.GPUCount > 0 {
+			if inst.GPUCount > 0 && inst.TotalVRAM >= selected.TotalVRAM && inst.Capability >= selected.Capability {
+				out = append(out, inst)
+			}
+			continue
+		}
+		if inst.GPUCount == 0 && inst.VCPUs >= selected.VCPUs && inst.RAMInGB >= selected.RAMInGB {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
+
+// sortByTotal orders instances by projected total cost, cheapest first
+func sortByTotal(instances []gpusearch.GPUInstanceInfo, count int, hours float64) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		return EstimateCost(instances[i], count, hours).TotalCost < EstimateCost(instances[j], count, hours).TotalCost
+	})
+}
+
+// formatGPU describes the GPUs of an estimate
+func formatGPU(e Estimate) string {
+	if e.GPUCount == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%dx %s", e.GPUCount, e.GPUName)
+}
+
+// formatDelta formats a signed dollar amount
+func formatDelta(delta float64) string {
+	if delta < 0 {
+		return fmt.Sprintf("-$%.2f", -delta)
+	}
+	return fmt.Sprintf("+$%.2f", delta)
+}
+
+// displayReport prints the estimate and the alternatives table
+func displayReport(t *terminal.Terminal, report *EstimateReport) {
+	s := report.Selected
+	t.Vprintf("Estimate for %s (%s, %s)\n", t.Green(s.Type), s.Provider, formatGPU(s))
+	t.Vprintf("  %d x $%.2f/hr x %s hrs      = $%.2f\n", s.Count, s.PricePerHour, formatHours(s.Hours), s.ComputeCost)
+	t.Vprintf("  boot overhead (%s hrs each) = $%.2f\n", formatHours(s.BootOverheadHours), s.BootOverheadCost)
+	t.Vprintf("  %s\n", t.Green(fmt.Sprintf("Total: $%.2f  (burn rate $%.2f/hr)", s.TotalCost, s.HourlyBurn)))
+
+	if len(report.Alternatives) == 0 {
+		return
+	}
+
+	t.Vprint("\nCheapest alternatives:")
+	ta := table.NewWriter()
+	ta.SetOutputMirror(os.Stdout)
+	ta.Style().Options = table.OptionsDefault
+	ta.Style().Options.DrawBorder = false
+	ta.Style().Options.SeparateColumns = false
+	ta.Style().Options.SeparateRows = false
+	ta.Style().Options.SeparateHeader = false
+	ta.AppendHeader(table.Row{"TYPE", "PROVIDER", "GPU", "TOTAL VRAM", "$/HR", "BOOT HRS", "TOTAL", "VS SELECTED"})
+	for _, alt := range report.Alternatives {
+		vram := "-"
+		if alt.TotalVRAM > 0 {
+			vram = fmt.Sprintf("%.0f GB", alt.TotalVRAM)
+		}
+		ta.AppendRow(table.Row{
+			alt.Type, alt.Provider, formatGPU(alt), vram,
+			fmt.Sprintf("$%.2f", alt.PricePerHour),
+			formatHours(alt.BootOverheadHours),
+			fmt.Sprintf("$%.2f", alt.TotalCost),
+			formatDelta(alt.DeltaVsSelected),
+		})
+	}
+	ta.Render()
+}
+
+// formatHours formats hours with up to two decimals
+func formatHours(h float64) string {
+	s := fmt.Sprintf("%.2f", h)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s
+}
@@ -0,0 +1,103 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCostStore struct {
+	response *gpusearch.InstanceTypesResponse
+}
+
+func (m *mockCostStore) GetInstanceTypes(_ bool) (*gpusearch.InstanceTypesResponse, error) {
+	return m.response, nil
+}
+
+func testItem(instanceType, gpuName string, count int, memory, price, deploy string) gpusearch.InstanceType {
+	item := gpusearch.InstanceType{
+		Type:                instanceType,
+		Provider:            "aws",
+		VCPU:                8,
+		Memory:              "32GiB",
+		BasePrice:           gpusearch.BasePrice{Currency: "USD", Amount: price},
+		EstimatedDeployTime: deploy,
+	}
+	if gpuName != "" {
+		item.SupportedGPUs = []gpusearch.GPU{{Count: count, Name: gpuName, Manufacturer: "NVIDIA", Memory: memory}}
+	}
+	return item
+}
+
+func testInstances() []gpusearch.GPUInstanceInfo {
+	return uniqueByType(gpusearch.ProcessInstances([]gpusearch.InstanceType{
+		testItem("g5.xlarge", "A10G", 1, "24GiB", "1.00", "6m0s"),
+		testItem("g6.xlarge", "L4", 1, "24GiB", "0.80", "12m0s"),
+		testItem("g5.12xlarge", "A10G", 4, "24GiB", "5.00", "6m0s"),
+		testItem("g4dn.xlarge", "T4", 1, "16GiB", "0.50", "6m0s"),
+		testItem("m5.2xlarge", "", 0, "", "0.40", "3m0s"),
+	}))
+}
+
+func TestEstimateCostIncludesBootOverhead(t *testing.T) {
+	inst, ok := findType(testInstances(), "g5.xlarge")
+	require.True(t, ok)
+
+	e := EstimateCost(inst, 4, 36)
+	assert.InDelta(t, 0.1, e.BootOverheadHours, 1e-9)
+	assert.InDelta(t, 144.0, e.ComputeCost, 1e-9)
+	assert.InDelta(t, 0.4, e.BootOverheadCost, 1e-9)
+	assert.InDelta(t, 144.4, e.TotalCost, 1e-9)
+	assert.InDelta(t, 4.0, e.HourlyBurn, 1e-9)
+}
+
+func TestBuildReportWithTypeComparesComparableAlternatives(t *testing.T) {
+	flags := &estimateFlags{instanceType: "G5.XLARGE", count: 2, hours: 10, alternatives: 5}
+	report, err := buildReport(testInstances(), flags, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "g5.xlarge", report.Selected.Type)
+	var types []string
+	for _, alt := range report.Alternatives {
+		types = append(types, alt.Type)
+	}
+	// T4 has less VRAM and m5 has no GPU, so neither is comparable
+	assert.Equal(t, []string{"g6.xlarge", "g5.12xlarge"}, types)
+	assert.Less(t, report.Alternatives[0].DeltaVsSelected, 0.0)
+	assert.Greater(t, report.Alternatives[1].DeltaVsSelected, 0.0)
+}
+
+func TestBuildReportWithFiltersPicksCheapest(t *testing.T) {
+	flags := &estimateFlags{gpuName: "a10g", count: 1, hours: 1, alternatives: 1}
+	report, err := buildReport(testInstances(), flags, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "g5.xlarge", report.Selected.Type)
+	require.Len(t, report.Alternatives, 1)
+	assert.Equal(t, "g5.12xlarge", report.Alternatives[0].Type)
+}
+
+func TestBuildReportUnknownType(t *testing.T) {
+	flags := &estimateFlags{instanceType: "nope", count: 1, hours: 1}
+	_, err := buildReport(testInstances(), flags, nil)
+	assert.Error(t, err)
+}
+
+func TestRunEstimateValidation(t *testing.T) {
+	store := &mockCostStore{response: &gpusearch.InstanceTypesResponse{}}
+	term := terminal.New()
+
+	assert.Error(t, RunEstimate(term, store, &estimateFlags{instanceType: "g5.xlarge", count: 1}))
+	assert.Error(t, RunEstimate(term, store, &estimateFlags{instanceType: "g5.xlarge", count: 0, hours: 1}))
+	assert.Error(t, RunEstimate(term, store, &estimateFlags{count: 1, hours: 1}))
+	assert.Error(t, RunEstimate(term, store, &estimateFlags{where: "vram >", count: 1, hours: 1}))
+}
+
+func TestFormatHours(t *testing.T) {
+	assert.Equal(t, "36", formatHours(36))
+	assert.Equal(t, "0.1", formatHours(0.1))
+	assert.Equal(t, "0", formatHours(0))
+}
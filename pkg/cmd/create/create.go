@@ -4,9 +4,9 @@
 package create
 
 import (
+	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/brevdev/brev-cli/pkg/cmd/util"
 	"github.com/brevdev/brev-cli/pkg/config"
@@ -186,24 +186,23 @@ func displayConnectBreadCrumb(t *terminal.Terminal, workspace *entity.Workspace)
 
 func pollUntil(t *terminal.Terminal, wsid string, state string, createStore CreateStore, canSafelyExit bool) error {
 	s := t.NewSpinner()
-	isReady := false
 	if canSafelyExit {
 		t.Vprintf("You can safely ctrl+c to exit\n")
 	}
 	s.Suffix = " hang tight 🤙"
 	s.Start()
-	for !isReady {
-		time.Sleep(5 * time.Second)
+	err := util.Poll(context.Background(), util.DefaultPollOptions, func() (bool, error) {
 		ws, err := createStore.GetWorkspace(wsid)
 		if err != nil {
-			return breverrors.WrapAndTrace(err)
+			return false, breverrors.WrapAndTrace(err)
 		}
 		s.Suffix = "  instance is " + strings.ToLower(ws.Status)
-		if ws.Status == state {
-			s.Suffix = "Instance is ready!"
-			s.Stop()
-			isReady = true
+		if ws.Status != state {
+			return false, nil
 		}
-	}
-	return nil
+		s.Suffix = "Instance is ready!"
+		s.Stop()
+		return true, nil
+	})
+	return breverrors.WrapAndTrace(err)
 }
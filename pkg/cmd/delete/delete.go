@@ -7,12 +7,12 @@ import (
 	"strings"
 
 	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/bulk"
 	"github.com/brevdev/brev-cli/pkg/cmd/completions"
 	"github.com/brevdev/brev-cli/pkg/cmd/util"
 	"github.com/brevdev/brev-cli/pkg/entity"
 	breverrors "github.com/brevdev/brev-cli/pkg/errors"
 	"github.com/brevdev/brev-cli/pkg/terminal"
-	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 	stripmd "github.com/writeas/go-strip-markdown"
 )
@@ -20,7 +20,7 @@ import (
 var (
 	//go:embed doc.md
 	deleteLong    string
-	deleteExample = "brev delete <ws_name>...\necho instance-name | brev delete"
+	deleteExample = "brev delete <ws_name>...\necho instance-name | brev delete\nbrev delete --status STOPPED --older-than 7d --yes"
 )
 
 type DeleteStore interface {
@@ -30,6 +30,8 @@ type DeleteStore interface {
 }
 
 func NewCmdDelete(t *terminal.Terminal, loginDeleteStore DeleteStore, noLoginDeleteStore DeleteStore) *cobra.Command {
+	var flags bulk.Flags
+
 	cmd := &cobra.Command{
 		Annotations:           map[string]string{"workspace": ""},
 		Use:                   "delete",
@@ -40,36 +42,65 @@ func NewCmdDelete(t *terminal.Terminal, loginDeleteStore DeleteStore, noLoginDel
 		ValidArgsFunction:     completions.GetAllWorkspaceNameCompletionHandler(noLoginDeleteStore, t),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			piped := util.IsStdoutPiped()
-			names, err := util.GetInstanceNames(args)
-			if err != nil {
-				return breverrors.WrapAndTrace(err)
+			names, stdinPiped := util.GetInstanceNamesWithPipeInfo(args)
+			opts := bulk.Options{Names: names, Confirmer: bulk.Prompter(stdinPiped), Piped: piped}
+			if bulk.UseBulk(flags, opts) {
+				return bulk.Run(t, loginDeleteStore, deleteOperation(loginDeleteStore), flags, opts)
 			}
-			var allError error
-			var deletedNames []string
-			for _, workspace := range names {
-				err := deleteWorkspace(workspace, t, loginDeleteStore, piped)
-				if err != nil {
-					allError = multierror.Append(allError, err)
-				} else {
-					deletedNames = append(deletedNames, workspace)
-				}
+			if len(names) == 0 {
+				return breverrors.NewValidationError("instance name required: provide as argument or pipe from another command")
 			}
-			if allError != nil {
-				return breverrors.WrapAndTrace(allError)
+			err := deleteWorkspace(names[0], t, loginDeleteStore, piped)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
 			}
-			// Only output names for piping if all succeeded
+			// Output name for piping to next command
 			if piped {
-				for _, name := range deletedNames {
-					fmt.Println(name)
-				}
+				fmt.Println(names[0])
 			}
 			return nil
 		},
 	}
+	bulk.AddFlags(cmd, &flags)
 
 	return cmd
 }
 
+// deleteOperation deletes instances that are not already being deleted
+func deleteOperation(deleteStore DeleteStore) bulk.Operation {
+	return bulk.Operation{
+		Verb: "delete",
+		Done: "deleted",
+		Resolve: func(name string) (*entity.Workspace, error) {
+			workspace, err := util.GetUserWorkspaceByNameOrIDErr(deleteStore, name)
+			if err == nil {
+				return workspace, nil
+			}
+			if err := handleAdminUser(err, deleteStore, true); err != nil {
+				return nil, breverrors.WrapAndTrace(err)
+			}
+			workspace, err = util.GetAnyWorkspaceByIDOrNameInActiveOrgErr(deleteStore, name)
+			if err != nil {
+				return nil, breverrors.WrapAndTrace(err)
+			}
+			return workspace, nil
+		},
+		Check: func(w entity.Workspace) error {
+			if w.Status == entity.Deleting {
+				return breverrors.NewValidationError("instance is already being deleted")
+			}
+			return nil
+		},
+		Do: func(w entity.Workspace) error {
+			_, err := deleteStore.DeleteWorkspace(w.ID)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			return nil
+		},
+	}
+}
+
 func deleteWorkspace(workspaceName string, t *terminal.Terminal, deleteStore DeleteStore, piped bool) error {
 	workspace, err := util.GetUserWorkspaceByNameOrIDErr(deleteStore, workspaceName)
 	if err != nil {
@@ -0,0 +1,150 @@
+package gpucreate
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+)
+
+// EventSchemaVersion is bumped whenever a field of CreateEvent changes meaning or is removed.
+// Adding fields or event types does not change the version.
+const EventSchemaVersion = 1
+
+// EventsFormatJSONL is the only supported --events format: one JSON object per line
+const EventsFormatJSONL = "jsonl"
+
+// Event types written with --events
+const (
+	EventAttemptStarted   = "attempt_started"
+	EventAttemptFailed    = "attempt_failed"
+	EventTypeUnavailable  = "type_unavailable"
+	EventInstanceCreated  = "instance_created"
+	EventInstanceReady    = "instance_ready"
+	EventInstanceNotReady = "instance_not_ready"
+	EventCleanupDeleted   = "cleanup_deleted"
+	EventCleanupFailed    = "cleanup_failed"
+	EventBudgetOverride   = "budget_override" // --allow-over-budget let the run exceed a spending cap
+	EventSummary          = "summary"
+)
+
+// Reasons for cleanup events
+const (
+	CleanupReasonExtra  = "extra"  // more instances were created than requested
+	CleanupReasonAtomic = "atomic" // --atomic rolled back a partial fleet
+)
+
+// CreateEvent is one line of the --events jsonl stream
+type CreateEvent struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Event         string         `json:"event"`
+	Time          time.Time      `json:"time"`
+	WorkerID      int            `json:"workerId,omitempty"` // 1-based, as in the log output
+	InstanceType  string         `json:"instanceType,omitempty"`
+	Name          string         `json:"name,omitempty"`
+	WorkspaceID   string         `json:"workspaceId,omitempty"`
+	StartedAt     *time.Time     `json:"startedAt,omitempty"` // when the create attempt started
+	Reason        string         `json:"reason,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	Summary       *CreateSummary `json:"summary,omitempty"`
+}
+
+// CreateSummary is the payload of the final summary event
+type CreateSummary struct {
+	Requested int               `json:"requested"`
+	Created   int               `json:"created"`
+	Ready     int               `json:"ready"`
+	Succeeded bool              `json:"succeeded"`
+	Instances []SummaryInstance `json:"instances"`
+}
+
+// SummaryInstance is an instance that exists at the end of the run
+type SummaryInstance struct {
+	Name         string `json:"name"`
+	WorkspaceID  string `json:"workspaceId"`
+	InstanceType string `json:"instanceType"`
+	Ready        bool   `json:"ready"`
+}
+
+// validateEventsFormat checks the --events value
+func validateEventsFormat(format string) error {
+	if format != "" && format != EventsFormatJSONL {
+		return breverrors.NewValidationError("--events only supports jsonl")
+	}
+	return nil
+}
+
+// eventEmitter writes events as JSON lines. A nil emitter discards events.
+type eventEmitter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+func newEventEmitter(w io.Writer) *eventEmitter {
+	if w == nil {
+		return nil
+	}
+	return &eventEmitter{enc: json.NewEncoder(w), now: time.Now}
+}
+
+func (e *eventEmitter) emit(ev CreateEvent) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ev.SchemaVersion = EventSchemaVersion
+	ev.Time = e.now().UTC()
+	_ = e.enc.Encode(ev)
+}
+
+// workspaceEvent builds an event about an existing workspace
+func workspaceEvent(event string, ws *entity.Workspace) CreateEvent {
+	return CreateEvent{Event: event, InstanceType: ws.InstanceType, Name: ws.Name, WorkspaceID: ws.ID}
+}
+
+// emitSummary writes the summary event for the instances that still exist
+func (c *createContext) emitSummary(err error) {
+	if c.events == nil {
+		return
+	}
+	summary := &CreateSummary{Requested: c.opts.Count, Succeeded: err == nil, Instances: []SummaryInstance{}}
+	for _, ws := range c.created {
+		if c.deleted[ws.ID] {
+			continue
+		}
+		summary.Created++
+		ready := c.ready[ws.ID]
+		if ready {
+			summary.Ready++
+		}
+		summary.Instances = append(summary.Instances, SummaryInstance{
+			Name: ws.Name, WorkspaceID: ws.ID, InstanceType: ws.InstanceType, Ready: ready,
+		})
+	}
+	ev := CreateEvent{Event: EventSummary, Summary: summary}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	c.events.emit(ev)
+}
+
+// emitCleanup records the deletion of an instance created in this run
+func (c *createContext) emitCleanup(ws *entity.Workspace, reason string, err error) {
+	ev := workspaceEvent(EventCleanupDeleted, ws)
+	ev.Reason = reason
+	if err != nil {
+		ev.Event = EventCleanupFailed
+		ev.Error = err.Error()
+	} else {
+		if c.deleted == nil {
+			c.deleted = map[string]bool{}
+		}
+		c.deleted[ws.ID] = true
+	}
+	c.events.emit(ev)
+}
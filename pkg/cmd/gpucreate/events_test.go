@@ -0,0 +1,110 @@
+package gpucreate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeEvents(t *testing.T, buf *bytes.Buffer) []CreateEvent {
+	t.Helper()
+	var events []CreateEvent
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var ev CreateEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev), scanner.Text())
+		assert.Equal(t, EventSchemaVersion, ev.SchemaVersion)
+		assert.False(t, ev.Time.IsZero())
+		events = append(events, ev)
+	}
+	return events
+}
+
+func eventNames(events []CreateEvent) []string {
+	names := make([]string, 0, len(events))
+	for _, ev := range events {
+		names = append(names, ev.Event)
+	}
+	return names
+}
+
+func TestRunGPUCreateEmitsEvents(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+	var buf bytes.Buffer
+	opts := GPUCreateOptions{
+		Name: "node", Count: 1, Parallel: 1, Timeout: time.Second, Mode: "vm", Events: &buf,
+		InstanceTypes: []InstanceSpec{{Type: "h100.gone"}, {Type: "g5.xlarge"}},
+	}
+
+	require.NoError(t, RunGPUCreate(terminal.New(), mock, opts))
+
+	events := decodeEvents(t, &buf)
+	assert.Equal(t, []string{EventTypeUnavailable, EventAttemptStarted, EventInstanceCreated, EventInstanceReady, EventSummary}, eventNames(events))
+
+	created := events[2]
+	assert.Equal(t, 1, created.WorkerID)
+	assert.Equal(t, "g5.xlarge", created.InstanceType)
+	assert.Equal(t, "node", created.Name)
+	assert.Equal(t, "ws-node", created.WorkspaceID)
+	require.NotNil(t, created.StartedAt)
+
+	summary := events[4].Summary
+	require.NotNil(t, summary)
+	assert.True(t, summary.Succeeded)
+	assert.Equal(t, 1, summary.Ready)
+	assert.Equal(t, []SummaryInstance{{Name: "node", WorkspaceID: "ws-node", InstanceType: "g5.xlarge", Ready: true}}, summary.Instances)
+}
+
+func TestRunGPUCreateEventsOnAtomicRollback(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+	calls := 0
+	limited := &limitedCreateStore{MockGPUCreateStore: mock, limit: 1, calls: &calls}
+	var buf bytes.Buffer
+	opts := atomicCreateOptions(2, "g5.xlarge")
+	opts.Events = &buf
+
+	require.Error(t, RunGPUCreate(terminal.New(), limited, opts))
+
+	events := decodeEvents(t, &buf)
+	names := eventNames(events)
+	assert.Contains(t, names, EventAttemptFailed)
+	assert.Contains(t, names, EventCleanupDeleted)
+
+	last := events[len(events)-1]
+	assert.Equal(t, EventSummary, last.Event)
+	assert.Contains(t, last.Error, "atomic create failed")
+	assert.False(t, last.Summary.Succeeded)
+	assert.Empty(t, last.Summary.Instances)
+}
+
+func TestEmitCleanupFailure(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := &createContext{events: newEventEmitter(&buf), deleted: map[string]bool{}}
+
+	ctx.emitCleanup(&entity.Workspace{ID: "ws-1", Name: "extra"}, CleanupReasonExtra, errors.New("boom"))
+
+	events := decodeEvents(t, &buf)
+	require.Len(t, events, 1)
+	assert.Equal(t, EventCleanupFailed, events[0].Event)
+	assert.Equal(t, CleanupReasonExtra, events[0].Reason)
+	assert.Equal(t, "boom", events[0].Error)
+	assert.False(t, ctx.deleted["ws-1"])
+}
+
+func TestEventsFlagValidation(t *testing.T) {
+	cmd := NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	cmd.SetArgs([]string{"my-instance", "--type", "g5.xlarge", "--events", "json"})
+	assert.ErrorContains(t, cmd.Execute(), "--events only supports jsonl")
+
+	cmd = NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	cmd.SetArgs([]string{"my-instance", "--type", "g5.xlarge", "--events", "jsonl", "--dry-run"})
+	assert.ErrorContains(t, cmd.Execute(), "--dry-run")
+}
@@ -2,7 +2,9 @@
 package gpucreate
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand/v2"
@@ -13,21 +15,27 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 	"unicode"
 
 	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/budget"
+	"github.com/brevdev/brev-cli/pkg/cmd/completions"
 	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/cmd/readiness"
 	"github.com/brevdev/brev-cli/pkg/cmd/util"
 	"github.com/brevdev/brev-cli/pkg/config"
 	"github.com/brevdev/brev-cli/pkg/entity"
 	breverrors "github.com/brevdev/brev-cli/pkg/errors"
 	"github.com/brevdev/brev-cli/pkg/featureflag"
+	"github.com/brevdev/brev-cli/pkg/files"
 	"github.com/brevdev/brev-cli/pkg/names"
 	"github.com/brevdev/brev-cli/pkg/ssh"
 	"github.com/brevdev/brev-cli/pkg/store"
 	"github.com/brevdev/brev-cli/pkg/terminal"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -45,7 +53,11 @@ the command automatically searches for GPUs matching either your filters or defa
   - Minimum 500GB disk (--min-disk)
   - Compute capability 8.0+ (--min-capability)
   - Boot time under 7 minutes (--max-boot-time, only when no filters set)
+Use --where for an expression filter (see 'brev search --help' for its syntax).
+Use --preset <name> to apply filters saved with --save-preset (see 'brev search presets').
 Results are sorted by price (cheapest first) unless --sort is specified.
+Creating always refetches the instance-type catalog; --dry-run uses the local
+cache like 'brev search' and accepts --offline.
 
 Retry and Fallback Logic:
 When multiple instance types are provided (via --type or piped input), the command
@@ -67,12 +79,81 @@ If type A fails for instance-2:
   - Try B for instance-2 → success
   - Done! (instance-1 uses A, instance-2 uses B)
 
+Instance Names:
+With --count N the instances are named <name>-1 ... <name>-N. Use --name-template
+for other schemes, e.g. '{{.Base}}-{{.GPU | lower}}-{{.Index | printf "%02d"}}'.
+Templated names are checked against existing instances first; a name that is
+taken gets the first free -2, -3, ... suffix.
+
+All-or-nothing Creation:
+With --atomic, a partial fleet is never left behind. If fewer than --count
+instances are created, or any of them is not ready within --timeout (one
+deadline for the whole group), every instance created in the run is deleted
+and the command exits non-zero with a summary of the failures per type.
+
+Readiness Probes:
+An instance is ready once it is RUNNING. Add --ready-when to also wait until it
+is usable, e.g. the startup script has finished or a service answers. Probes run
+in order over SSH, each with its own timeout and interval, and their results are
+shown in the summary. The command exits non-zero if a probe does not pass.
+
+Spending Caps:
+Before creating, the hourly price of the org's running instances plus the new
+ones (at the most expensive candidate type) is checked against the caps in a
+team policy file (brev-policy.json in this or a parent directory, or
+$BREV_POLICY_FILE) and in ~/.brev/personal_settings.json:
+  {"budget": {"max_hourly": 100, "max_per_command": 40, "on_exceed": "confirm"}}
+Over a cap the command asks for confirmation, or refuses with on_exceed "refuse".
+--allow-over-budget proceeds anyway and says so in the output.
+
+Event Stream:
+With --events jsonl, stdout carries one JSON object per line for each step:
+attempt_started, attempt_failed, type_unavailable, instance_created,
+instance_ready, instance_not_ready, cleanup_deleted, cleanup_failed and a final
+summary. Events include schemaVersion, time, workerId, instanceType, name and
+workspaceId where they apply. Logs go to stderr.
+
+Spec Files:
+With --from-file, the configuration comes from a YAML (or JSON) file with the
+keys name, count, type or types, disk, mode, jupyter, jupyter-on-start,
+startup-script, container-image, entrypoint, registry, compose-file,
+compose-yaml, env, registries, ports, firewall-rules and labels. Flags given on
+the command line override the file, unknown keys are reported with their line
+numbers, and 'brev clone <instance> <name> --print' writes this format:
+  name: trainer
+  types: [g5.xlarge, g5.2xlarge]
+  disk: 500Gi
+  mode: container
+  container-image: registry.example.com/team/trainer:latest
+  registry: {url: registry.example.com, username: ci, password-env: REGISTRY_TOKEN}
+  ports: {tensorboard: "6006"}
+  firewall-rules:
+    - {port: "6006", allowed-ips: user-ip}
+
+Labels:
+--label key=value (repeatable) labels the instances so they can be selected
+later, e.g. 'brev ls -l team=ml'. Keys use letters, digits, '-', '_' and '.',
+with an optional prefix like example.com/.
+
+Build Validation:
+Compose files and container images are checked before anything is created.
+Compose files must have a services section with valid service names, must not
+publish port 22 (SSH) or, with --jupyter, 8888, and every ${VAR} without a
+default must be set under env in the --from-file spec. Container images must be
+valid references like nvcr.io/nvidia/pytorch:24.01-py3. Use --skip-validation
+to send them to the API unchecked.
+
 Startup Scripts:
 You can attach a startup script that runs when the instance boots using the
 --startup-script flag. The script can be provided as:
   - An inline string: --startup-script 'pip install torch'
   - A file path (prefix with @): --startup-script @setup.sh
-  - An absolute file path: --startup-script @/path/to/setup.sh`
+  - An absolute file path: --startup-script @/path/to/setup.sh
+  - A directory (prefix with @): --startup-script @setup.d runs its files in name order
+Repeat the flag to run several scripts in order; they are joined into one script
+with a marked section for each. A line '# brev:include path' is replaced by that file,
+relative to the script that includes it. --var KEY=VALUE replaces ${KEY} before
+upload (other ${...} are left to the shell). --dry-run prints the final script.`
 
 	example = `
   # Create an instance using smart defaults (sorted by price)
@@ -90,8 +171,36 @@ You can attach a startup script that runs when the instance boots using the
   # Create multiple instances in parallel
   brev create my-cluster --count 3 --type g5.xlarge --parallel 3
 
+  # Name instances after their GPU, e.g. exp-a100-01, exp-a100-02
+  brev create exp --count 2 -g a100 --name-template '{{.Base}}-{{.GPU | lower}}-{{.Index | printf "%02d"}}'
+
+  # Wait until the startup script has finished and Jupyter answers
+  brev create my-instance --startup-script @setup.sh \
+    --ready-when 'file[timeout=20m]:/tmp/setup-done' --ready-when http:8888/api
+
+  # All 4 nodes or none, e.g. for distributed training
+  brev create my-cluster --count 4 --type p4d.24xlarge,p4de.24xlarge --parallel 4 --atomic
+
+  # Stream progress events for a script or CI job
+  brev create my-cluster --count 2 --type g5.xlarge --events jsonl | jq -c 'select(.event == "instance_ready")'
+
+  # Create from a spec file, overriding its instance count
+  brev create --from-file trainer.yaml --count 2
+
+  # Compose the startup script from several parts and check the result
+  brev create my-instance -s @common.d -s @train.sh --var MODEL=llama3 --dry-run
+
+  # Label instances to select them later with 'brev ls -l team=ml'
+  brev create my-instance --label team=ml --label owner=alice
+
   # Use search filters directly and attach a startup script
   brev create my-instance -g a100 --startup-script @setup.sh
+
+  # Reuse a saved filter preset
+  brev create my-instance --preset h100-fast
+
+  # Select instance types with a filter expression
+  brev create my-instance --where 'vram>=80 && provider in ("aws","gcp") && stoppable'
 `
 )
 
@@ -138,32 +247,44 @@ type searchFilterFlags struct {
 	stoppable     bool
 	rebootable    bool
 	flexPorts     bool
+	where         string
 	sortBy        string
 	descending    bool
+	cache         gpusearch.CatalogCacheFlags
+	presets       gpusearch.PresetFlags
 }
 
 // hasUserFilters returns true if the user specified any search filter flags
 func (f *searchFilterFlags) hasUserFilters() bool {
 	return f.gpuName != "" || f.provider != "" || f.minVRAM > 0 || f.minTotalVRAM > 0 ||
 		f.minCapability > 0 || f.minDisk > 0 || f.maxBootTime > 0 ||
-		f.stoppable || f.rebootable || f.flexPorts
+		f.stoppable || f.rebootable || f.flexPorts || f.where != ""
 }
 
 // NewCmdGPUCreate creates the gpu-create command
 func NewCmdGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore) *cobra.Command { //nolint:gocognit,gocyclo,funlen // easier to read as one function
 	var name string
+	var nameTemplate string
 	var instanceTypes string
 	var count int
 	var parallel int
 	var detached bool
+	var atomic bool
+	var readyWhen []string
 	var timeout int
-	var startupScript string
+	var startupScripts []string
+	var scriptVars []string
 	var dryRun bool
 	var mode string
 	var jupyter bool
 	var containerImage string
 	var composeFile string
 	var launchable string
+	var events string
+	var fromFile string
+	var skipValidation bool
+	var allowOverBudget bool
+	var labelFlags []string
 	var filters searchFilterFlags
 
 	cmd := &cobra.Command{
@@ -175,25 +296,44 @@ func NewCmdGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore) *cobra
 		Long:                  long,
 		Example:               example,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			term := t
+			if err := validateEventsFormat(events); err != nil {
+				return err
+			}
+			if events != "" {
+				if dryRun {
+					return breverrors.NewValidationError("--events cannot be used with --dry-run")
+				}
+				// keep stdout for the event stream
+				term = t.ToStderr()
+			}
+			spec, err := loadCreateSpec(cmd.Flags(), fromFile, len(args) > 0)
+			if err != nil {
+				return err
+			}
+			if err := filters.presets.Apply(term, cmd, gpuCreateStore); err != nil {
+				return err
+			}
+
 			// Accept name as positional arg or --name flag
 			if len(args) > 0 && name == "" {
 				name = args[0]
 			}
 
-			launchableID, err := parseLaunchableID(launchable)
+			launchableID, err := ParseLaunchableID(launchable)
 			if err != nil {
 				return err
 			}
 
-			warnLaunchableFlagConflicts(cmd, t, launchableID)
+			warnLaunchableFlagConflicts(cmd, term, launchableID)
 
 			if launchableID == "" {
-				if err := validateBuildMode(mode, containerImage, composeFile); err != nil {
+				if err := ValidateBuildMode(mode, containerImage, spec.composeSource(composeFile)); err != nil {
 					return err
 				}
 			}
 
-			launchableInfo, err := fetchAndDisplayLaunchable(gpuCreateStore, t, launchableID)
+			launchableInfo, err := FetchAndDisplayLaunchable(gpuCreateStore, term, launchableID)
 			if err != nil {
 				return err
 			}
@@ -207,23 +347,51 @@ func NewCmdGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore) *cobra
 			if err != nil {
 				return err
 			}
+			var tmpl *template.Template
+			if nameTemplate != "" {
+				tmpl, err = ParseNameTemplate(nameTemplate, name)
+				if err != nil {
+					return err
+				}
+			}
+			if atomic && detached {
+				return breverrors.NewValidationError("--atomic waits for the instances to be ready and cannot be used with --detached")
+			}
+			probes, err := readiness.ParseProbes(readyWhen)
+			if err != nil {
+				return err
+			}
+			if len(probes) > 0 && detached {
+				return breverrors.NewValidationError("--ready-when cannot be used with --detached")
+			}
 
 			types, err := parseInstanceTypes(instanceTypes)
 			if err != nil {
 				return breverrors.WrapAndTrace(err)
 			}
 
-			scriptContent, err := parseStartupScript(startupScript)
+			vars, err := ParseScriptVars(scriptVars)
 			if err != nil {
-				return breverrors.WrapAndTrace(err)
+				return err
+			}
+			scriptContent, err := RenderStartupScript(startupScripts, vars)
+			if err != nil {
+				return err
+			}
+			labels, err := parseCreateLabels(spec, labelFlags)
+			if err != nil {
+				return err
 			}
 
 			opts := GPUCreateOptions{
 				Name:           name,
+				NameTemplate:   tmpl,
 				InstanceTypes:  types,
 				Count:          count,
 				Parallel:       max(1, parallel),
 				Detached:       detached,
+				Atomic:         atomic,
+				ReadyWhen:      probes,
 				Timeout:        time.Duration(timeout) * time.Second,
 				StartupScript:  scriptContent,
 				Mode:           mode,
@@ -233,6 +401,24 @@ func NewCmdGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore) *cobra
 				ComposeFile:    composeFile,
 				LaunchableID:   launchableID,
 				LaunchableInfo: launchableInfo,
+				Spec:           spec,
+				Labels:         labels,
+			}
+			if events != "" {
+				opts.Events = os.Stdout
+			} else if !util.IsStdinPiped() {
+				opts.Confirmer = terminal.Prompter{}
+			}
+			opts.AllowOverBudget = allowOverBudget
+
+			if !skipValidation {
+				if err := ValidateBuild(opts); err != nil {
+					return err
+				}
+			}
+
+			if err := applyCatalogCachePolicy(gpuCreateStore, filters.cache, dryRun); err != nil {
+				return err
 			}
 
 			opts.InstanceTypes, err = resolveInstanceTypes(cmd, gpuCreateStore, opts, types, &filters)
@@ -241,18 +427,69 @@ func NewCmdGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore) *cobra
 			}
 
 			if dryRun {
-				return runDryRun(t, gpuCreateStore, opts.InstanceTypes, &filters)
+				if err := runDryRun(term, gpuCreateStore, opts.InstanceTypes, &filters); err != nil {
+					return err
+				}
+				if scriptContent != "" {
+					term.Print("\nStartup script:\n" + scriptContent)
+				}
+				return nil
 			}
 
-			return RunGPUCreate(t, gpuCreateStore, opts)
+			return RunGPUCreate(term, gpuCreateStore, opts)
 		},
 	}
 
-	registerCreateFlags(cmd, &name, &instanceTypes, &count, &parallel, &detached, &timeout, &startupScript, &dryRun, &mode, &jupyter, &containerImage, &composeFile, &launchable, &filters)
+	registerCreateFlags(cmd, &name, &nameTemplate, &instanceTypes, &count, &parallel, &detached, &atomic, &readyWhen, &timeout, &startupScripts, &scriptVars, &dryRun, &mode, &jupyter, &containerImage, &composeFile, &launchable, &filters)
+	budget.AddFlags(cmd, &allowOverBudget)
+	cmd.Flags().BoolVar(&skipValidation, "skip-validation", false, SkipValidationHelp)
+	cmd.Flags().StringVarP(&fromFile, "from-file", "f", "", "Read the instance configuration from a YAML or JSON spec file; flags override its values")
+	cmd.Flags().StringArrayVar(&labelFlags, "label", nil, "Label the instances with key=value (repeatable); select them with 'brev ls -l key=value'")
+	cmd.Flags().StringVar(&events, "events", "", "Write progress events to stdout, one JSON object per line (jsonl); logs go to stderr")
+	_ = cmd.RegisterFlagCompletionFunc("type", completions.GetInstanceTypeCompletionHandler(gpuCreateStore))
 
 	return cmd
 }
 
+// applyCatalogCachePolicy configures the instance-type catalog cache for create.
+// Real creates always refetch so capacity is current; --offline is only
+// allowed with --dry-run.
+func applyCatalogCachePolicy(gpuCreateStore GPUCreateStore, flags gpusearch.CatalogCacheFlags, dryRun bool) error {
+	policy, err := flags.Policy()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	if !dryRun {
+		if policy.Mode == gpusearch.CatalogCacheOffline {
+			return breverrors.NewValidationError("--offline can only be used with --dry-run")
+		}
+		policy.Mode = gpusearch.CatalogCacheRefresh
+	}
+	gpusearch.ApplyCatalogCachePolicy(gpuCreateStore, policy)
+	return nil
+}
+
+// parseCreateLabels merges the spec file's labels with --label, which wins per key
+func parseCreateLabels(spec *CreateSpec, values []string) (map[string]string, error) {
+	flagLabels, err := entity.ParseLabels(values)
+	if err != nil {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("--label: %v", err))
+	}
+	labels := map[string]string{}
+	if spec != nil {
+		for k, v := range spec.Labels {
+			labels[k] = v
+		}
+	}
+	for k, v := range flagLabels {
+		labels[k] = v
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return labels, nil
+}
+
 func validateArgs(name string, count int) error {
 	if err := names.ValidateNodeName(name); err != nil {
 		return breverrors.WrapAndTrace(err)
@@ -265,14 +502,18 @@ func validateArgs(name string, count int) error {
 }
 
 // registerCreateFlags registers all flags for the create command
-func registerCreateFlags(cmd *cobra.Command, name, instanceTypes *string, count, parallel *int, detached *bool, timeout *int, startupScript *string, dryRun *bool, mode *string, jupyter *bool, containerImage, composeFile, launchable *string, filters *searchFilterFlags) {
+func registerCreateFlags(cmd *cobra.Command, name, nameTemplate, instanceTypes *string, count, parallel *int, detached, atomic *bool, readyWhen *[]string, timeout *int, startupScripts, scriptVars *[]string, dryRun *bool, mode *string, jupyter *bool, containerImage, composeFile, launchable *string, filters *searchFilterFlags) {
 	cmd.Flags().StringVarP(name, "name", "n", "", "Base name for the instances (or pass as first argument)")
+	cmd.Flags().StringVar(nameTemplate, "name-template", "", NameTemplateHelp)
 	cmd.Flags().StringVarP(instanceTypes, "type", "t", "", "Comma-separated list of instance types to try")
 	cmd.Flags().IntVarP(count, "count", "c", 1, "Number of instances to create")
 	cmd.Flags().IntVarP(parallel, "parallel", "p", 1, "Number of parallel creation attempts")
 	cmd.Flags().BoolVarP(detached, "detached", "d", false, "Don't wait for instances to be ready")
+	cmd.Flags().BoolVar(atomic, "atomic", false, "Delete every instance created in this run unless all --count instances are created and ready")
 	cmd.Flags().IntVar(timeout, "timeout", 300, "Timeout in seconds for each instance to become ready")
-	cmd.Flags().StringVarP(startupScript, "startup-script", "s", "", "Startup script to run on instance (string or @filepath)")
+	readiness.AddFlags(cmd, readyWhen)
+	cmd.Flags().StringArrayVarP(startupScripts, "startup-script", "s", nil, "Startup script to run on instance (string, @file or @directory); repeat to run several in order")
+	cmd.Flags().StringArrayVar(scriptVars, "var", nil, "Replace ${KEY} in the startup script with VALUE (KEY=VALUE, repeatable)")
 	cmd.Flags().BoolVar(dryRun, "dry-run", false, "Show matching instance types without creating anything")
 
 	// Build mode flags
@@ -282,18 +523,26 @@ func registerCreateFlags(cmd *cobra.Command, name, instanceTypes *string, count,
 	cmd.Flags().StringVar(composeFile, "compose-file", "", "Docker compose file path or URL (required for compose mode)")
 	cmd.Flags().StringVarP(launchable, "launchable", "l", "", "Launchable ID or URL to deploy (e.g., env-XXX or console URL)")
 
-	cmd.Flags().StringVarP(&filters.gpuName, "gpu-name", "g", "", "Filter by GPU name (e.g., A100, H100)")
-	cmd.Flags().StringVar(&filters.provider, "provider", "", "Filter by provider/cloud (e.g., aws, gcp)")
-	cmd.Flags().Float64VarP(&filters.minVRAM, "min-vram", "v", 0, "Minimum VRAM per GPU in GB")
-	cmd.Flags().Float64Var(&filters.minTotalVRAM, "min-total-vram", 0, "Minimum total VRAM in GB")
-	cmd.Flags().Float64Var(&filters.minCapability, "min-capability", 0, "Minimum GPU compute capability (e.g., 8.0)")
-	cmd.Flags().Float64Var(&filters.minDisk, "min-disk", 0, "Minimum disk size in GB")
-	cmd.Flags().IntVar(&filters.maxBootTime, "max-boot-time", 0, "Maximum boot time in minutes")
-	cmd.Flags().BoolVar(&filters.stoppable, "stoppable", false, "Only use instances that can be stopped/restarted")
-	cmd.Flags().BoolVar(&filters.rebootable, "rebootable", false, "Only use instances that can be rebooted")
-	cmd.Flags().BoolVar(&filters.flexPorts, "flex-ports", false, "Only use instances with configurable firewall rules")
-	cmd.Flags().StringVar(&filters.sortBy, "sort", "price", "Sort instance preference by: price, vram, boot-time, etc.")
-	cmd.Flags().BoolVar(&filters.descending, "desc", false, "Sort in descending order")
+	registerFilterFlags(cmd.Flags(), filters)
+	gpusearch.AddCatalogCacheFlags(cmd, &filters.cache)
+	gpusearch.AddPresetFlags(cmd, &filters.presets)
+}
+
+// registerFilterFlags registers the search filter and sort flags used to pick instance types
+func registerFilterFlags(flags *pflag.FlagSet, filters *searchFilterFlags) {
+	flags.StringVarP(&filters.gpuName, "gpu-name", "g", "", "Filter by GPU name (e.g., A100, H100)")
+	flags.StringVar(&filters.provider, "provider", "", "Filter by provider/cloud (e.g., aws, gcp)")
+	flags.Float64VarP(&filters.minVRAM, "min-vram", "v", 0, "Minimum VRAM per GPU in GB")
+	flags.Float64Var(&filters.minTotalVRAM, "min-total-vram", 0, "Minimum total VRAM in GB")
+	flags.Float64Var(&filters.minCapability, "min-capability", 0, "Minimum GPU compute capability (e.g., 8.0)")
+	flags.Float64Var(&filters.minDisk, "min-disk", 0, "Minimum disk size in GB")
+	flags.IntVar(&filters.maxBootTime, "max-boot-time", 0, "Maximum boot time in minutes")
+	flags.BoolVar(&filters.stoppable, "stoppable", false, "Only use instances that can be stopped/restarted")
+	flags.BoolVar(&filters.rebootable, "rebootable", false, "Only use instances that can be rebooted")
+	flags.BoolVar(&filters.flexPorts, "flex-ports", false, "Only use instances with configurable firewall rules")
+	flags.StringVar(&filters.where, "where", "", `Filter expression, e.g. 'vram>=80 && provider in ("aws","gcp") && stoppable'`)
+	flags.StringVar(&filters.sortBy, "sort", "price", "Sort instance preference by: price, vram, boot-time, etc.")
+	flags.BoolVar(&filters.descending, "desc", false, "Sort in descending order")
 }
 
 // InstanceSpec holds an instance type and its target disk size
@@ -304,25 +553,33 @@ type InstanceSpec struct {
 
 // GPUCreateOptions holds the options for GPU instance creation
 type GPUCreateOptions struct {
-	Name           string
-	InstanceTypes  []InstanceSpec
-	Count          int
-	Parallel       int
-	Detached       bool
-	Timeout        time.Duration
-	StartupScript  string
-	Mode           string
-	Jupyter        bool
-	JupyterSet     bool // whether --jupyter was explicitly set
-	ContainerImage string
-	ComposeFile    string
-	LaunchableID   string
-	LaunchableInfo *store.LaunchableResponse // populated when LaunchableID is set
+	Name            string
+	NameTemplate    *template.Template // renders instance names from NameData; nil uses Name and the index
+	InstanceTypes   []InstanceSpec
+	Count           int
+	Parallel        int
+	Detached        bool
+	Atomic          bool // delete everything created unless all Count instances become ready
+	Timeout         time.Duration
+	ReadyWhen       []readiness.Probe // probes that must pass after the instance is RUNNING
+	StartupScript   string
+	Mode            string
+	Jupyter         bool
+	JupyterSet      bool // whether --jupyter was explicitly set
+	ContainerImage  string
+	ComposeFile     string
+	LaunchableID    string
+	LaunchableInfo  *store.LaunchableResponse // populated when LaunchableID is set
+	Spec            *CreateSpec               // --from-file settings that have no flag; nil without --from-file
+	Labels          map[string]string         // --label and spec labels
+	Events          io.Writer                 // receives the --events jsonl stream; nil disables it
+	AllowOverBudget bool
+	Confirmer       terminal.Confirmer // asks before exceeding a spending cap; nil refuses
 }
 
-// parseLaunchableID extracts a launchable ID from either a raw ID (env-XXX) or
+// ParseLaunchableID extracts a launchable ID from either a raw ID (env-XXX) or
 // a console URL (https://console.brev.dev/launchable/deploy?launchableID=env-XXX)
-func parseLaunchableID(input string) (string, error) {
+func ParseLaunchableID(input string) (string, error) {
 	if input == "" {
 		return "", nil
 	}
@@ -386,8 +643,8 @@ func warnLaunchableFlagConflicts(cmd *cobra.Command, t *terminal.Terminal, launc
 	}
 }
 
-// fetchAndDisplayLaunchable fetches launchable info and displays it to the user
-func fetchAndDisplayLaunchable(gpuCreateStore GPUCreateStore, t *terminal.Terminal, launchableID string) (*store.LaunchableResponse, error) {
+// FetchAndDisplayLaunchable fetches launchable info and displays it to the user
+func FetchAndDisplayLaunchable(gpuCreateStore GPUCreateStore, t *terminal.Terminal, launchableID string) (*store.LaunchableResponse, error) {
 	if launchableID == "" {
 		return nil, nil
 	}
@@ -474,29 +731,13 @@ func resolveInstanceTypes(cmd *cobra.Command, gpuCreateStore GPUCreateStore, opt
 	return types, nil
 }
 
-// parseStartupScript parses the startup script from a string or file path
-// If the value starts with @, it's treated as a file path
-func parseStartupScript(value string) (string, error) {
-	if value == "" {
-		return "", nil
-	}
-
-	// Check if it's a file path (prefixed with @)
-	if strings.HasPrefix(value, "@") {
-		filePath := strings.TrimPrefix(value, "@")
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			return "", breverrors.WrapAndTrace(err)
-		}
-		return string(content), nil
-	}
-
-	// Otherwise, treat it as the script content directly
-	return value, nil
-}
-
 // searchInstances fetches and filters GPU instances using user-provided filters merged with defaults
 func searchInstances(s GPUCreateStore, filters *searchFilterFlags) ([]gpusearch.GPUInstanceInfo, float64, error) {
+	whereExpr, err := gpusearch.ParseWhereFlag(filters.where)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	response, err := s.GetInstanceTypes(false)
 	if err != nil {
 		return nil, 0, breverrors.WrapAndTrace(err)
@@ -521,6 +762,7 @@ func searchInstances(s GPUCreateStore, filters *searchFilterFlags) ([]gpusearch.
 	instances := gpusearch.ProcessInstances(response.Items)
 	filtered := gpusearch.FilterInstances(instances, filters.gpuName, filters.provider, "", filters.minVRAM,
 		minTotalVRAM, minCapability, 0, minDisk, 0, maxBootTime, filters.stoppable, filters.rebootable, filters.flexPorts, true)
+	filtered = gpusearch.FilterWhere(filtered, whereExpr)
 	gpusearch.SortInstances(filtered, sortBy, filters.descending)
 
 	return filtered, minDisk, nil
@@ -546,6 +788,23 @@ func getFilteredInstanceTypes(s GPUCreateStore, filters *searchFilterFlags) ([]I
 	return specs, nil
 }
 
+// InstanceTypesForFilters resolves instance types from search filters keyed by
+// flag name (e.g. "min-vram": "80"), using the same defaults as 'brev create'
+func InstanceTypesForFilters(s GPUCreateStore, filterValues files.SearchPreset) ([]InstanceSpec, error) {
+	var filters searchFilterFlags
+	flags := pflag.NewFlagSet("filters", pflag.ContinueOnError)
+	registerFilterFlags(flags, &filters)
+
+	unknown, err := gpusearch.ApplyPreset(flags, filterValues)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if len(unknown) > 0 {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("unknown filter(s): %s", strings.Join(unknown, ", ")))
+	}
+	return getFilteredInstanceTypes(s, &filters)
+}
+
 // runDryRun shows the instance types that would be used without creating anything
 func runDryRun(t *terminal.Terminal, s GPUCreateStore, specs []InstanceSpec, filters *searchFilterFlags) error {
 	if len(specs) > 0 {
@@ -726,17 +985,55 @@ type createContext struct {
 	allInstanceTypes *gpusearch.AllInstanceTypesResponse
 	piped            bool
 	logf             func(format string, a ...interface{})
+	attempts         map[string]*typeAttempt
+	attemptOrder     []string
+	checker          readiness.Checker
+	readyResults     map[string][]readiness.Result // by workspace ID
+	events           *eventEmitter                 // nil unless --events is set
+	created          []*entity.Workspace           // every instance created in this run
+	deleted          map[string]bool               // by workspace ID
+	ready            map[string]bool               // by workspace ID
+	startedAt        time.Time
+	namesMu          sync.Mutex
+	reservedNames    map[string]bool // --name-template names taken by this run
+}
+
+// typeAttempt tallies what happened with one instance type, for the --atomic failure summary
+type typeAttempt struct {
+	created  int
+	failures []string
+}
+
+// attempt returns the tally for an instance type, in the order types were first tried
+func (c *createContext) attempt(instanceType string) *typeAttempt {
+	if c.attempts == nil {
+		c.attempts = map[string]*typeAttempt{}
+	}
+	a, ok := c.attempts[instanceType]
+	if !ok {
+		a = &typeAttempt{}
+		c.attempts[instanceType] = a
+		c.attemptOrder = append(c.attemptOrder, instanceType)
+	}
+	return a
 }
 
 // newCreateContext initializes the context for instance creation
 func newCreateContext(t *terminal.Terminal, store GPUCreateStore, opts GPUCreateOptions) (*createContext, error) {
-	piped := gpusearch.IsStdoutPiped()
+	// With --events stdout carries only the event stream, so log as if piped
+	piped := gpusearch.IsStdoutPiped() || opts.Events != nil
 
 	ctx := &createContext{
-		t:     t,
-		store: store,
-		opts:  opts,
-		piped: piped,
+		t:            t,
+		store:        store,
+		opts:         opts,
+		piped:        piped,
+		checker:      readiness.SSHChecker{},
+		readyResults: map[string][]readiness.Result{},
+		events:       newEventEmitter(opts.Events),
+		startedAt:    time.Now(),
+		deleted:      map[string]bool{},
+		ready:        map[string]bool{},
 	}
 
 	// Set up logging function
@@ -813,11 +1110,20 @@ func (c *createContext) createInstancesWithType(spec InstanceSpec, startIdx, cou
 	if c.opts.LaunchableID == "" {
 		if err := c.validateInstanceTypeAvailability(spec.Type); err != nil {
 			c.logf("Skipping: %s\n", err.Error())
+			c.attempt(spec.Type).failures = append(c.attempt(spec.Type).failures, err.Error())
+			c.events.emit(CreateEvent{Event: EventTypeUnavailable, InstanceType: spec.Type, Error: err.Error()})
 			result.hadFailure = true
 			return result
 		}
 	}
 
+	instanceNames, err := c.reserveNames(startIdx, count, spec.Type)
+	if err != nil {
+		result.hadFailure = true
+		result.fatalError = err
+		return result
+	}
+
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
@@ -838,41 +1144,51 @@ func (c *createContext) createInstancesWithType(spec InstanceSpec, startIdx, cou
 		wg.Add(1)
 		go func(workerID int) {
 			defer wg.Done()
-			c.runWorker(workerID, spec, indicesToCreate, &result, &mu)
+			c.runWorker(workerID, spec, indicesToCreate, instanceNames, &result, &mu)
 		}(i)
 	}
 
 	wg.Wait()
+
+	// free the names of instances workers stopped before trying
+	for idx := range indicesToCreate {
+		c.releaseName(instanceNames[idx])
+	}
 	return result
 }
 
 // runWorker processes instance creation requests from the channel
-func (c *createContext) runWorker(workerID int, spec InstanceSpec, indices <-chan int, result *typeCreateResult, mu *sync.Mutex) {
+func (c *createContext) runWorker(workerID int, spec InstanceSpec, indices <-chan int, instanceNames map[int]string, result *typeCreateResult, mu *sync.Mutex) {
 	for idx := range indices {
 		// Check if we've already created enough
 		mu.Lock()
 		if len(result.successes) >= c.opts.Count {
 			mu.Unlock()
+			c.releaseName(instanceNames[idx])
 			return
 		}
 		mu.Unlock()
 
-		// Determine instance name
-		instanceName := c.opts.Name
-		if c.opts.Count > 1 {
-			instanceName = fmt.Sprintf("%s-%d", c.opts.Name, idx+1)
-		}
-
+		instanceName := instanceNames[idx]
 		c.logf("[Worker %d] Trying %s for instance '%s'...\n", workerID+1, spec.Type, instanceName)
+		startedAt := time.Now().UTC()
+		c.events.emit(CreateEvent{Event: EventAttemptStarted, WorkerID: workerID + 1, InstanceType: spec.Type, Name: instanceName, StartedAt: &startedAt})
 
 		workspace, err := c.createWorkspace(instanceName, spec)
 
 		mu.Lock()
+		attemptEvent := CreateEvent{WorkerID: workerID + 1, InstanceType: spec.Type, Name: instanceName, StartedAt: &startedAt}
 		if err != nil {
+			c.releaseName(instanceName)
 			c.handleCreateError(workerID, spec.Type, instanceName, err, result)
+			attemptEvent.Event = EventAttemptFailed
+			attemptEvent.Error = err.Error()
 		} else {
 			c.handleCreateSuccess(workerID, spec.Type, instanceName, workspace, result)
+			attemptEvent.Event = EventInstanceCreated
+			attemptEvent.WorkspaceID = workspace.ID
 		}
+		c.events.emit(attemptEvent)
 		mu.Unlock()
 	}
 }
@@ -891,6 +1207,7 @@ func (c *createContext) handleCreateError(workerID int, instanceType, instanceNa
 	c.logf("[Worker %d] %s Failed: %s\n", workerID+1, c.colorize(instanceType, c.t.Yellow), errStr)
 
 	result.hadFailure = true
+	c.attempt(instanceType).failures = append(c.attempt(instanceType).failures, errStr)
 	if strings.Contains(errStr, "duplicate workspace") {
 		result.fatalError = fmt.Errorf("workspace '%s' already exists. Use a different name or delete the existing workspace", instanceName)
 	}
@@ -900,6 +1217,8 @@ func (c *createContext) handleCreateError(workerID int, instanceType, instanceNa
 func (c *createContext) handleCreateSuccess(workerID int, instanceType, instanceName string, workspace *entity.Workspace, result *typeCreateResult) {
 	c.logf("[Worker %d] %s Success! Created instance '%s'\n", workerID+1, c.colorize(instanceType, c.t.Green), instanceName)
 	result.successes = append(result.successes, workspace)
+	c.created = append(c.created, workspace)
+	c.attempt(instanceType).created++
 }
 
 // cleanupExtraInstances deletes instances beyond the requested count
@@ -919,34 +1238,151 @@ func (c *createContext) cleanupExtraInstances(workspaces []*entity.Workspace) []
 		} else {
 			c.logf(" Done\n")
 		}
+		c.emitCleanup(ws, CleanupReasonExtra, err)
 	}
 
 	return workspaces[:c.opts.Count]
 }
 
-// waitForInstances waits for all instances to be ready
-func (c *createContext) waitForInstances(workspaces []*entity.Workspace) {
+// waitForInstances waits for all instances to be ready and returns how many were not.
+// With --atomic the timeout is one deadline for the whole group.
+func (c *createContext) waitForInstances(workspaces []*entity.Workspace) int {
 	if c.opts.Detached {
-		return
+		return 0
 	}
 
 	c.logf("\nWaiting for instance(s) to be ready...\n")
-	c.logf("You can safely ctrl+c to exit\n")
+	if !c.opts.Atomic {
+		c.logf("You can safely ctrl+c to exit\n")
+	}
 
+	groupDeadline := time.Now().Add(c.opts.Timeout)
+	notReady := 0
 	for _, ws := range workspaces {
-		err := c.pollUntilReady(ws.ID)
-		if err != nil {
+		deadline := time.Now().Add(c.opts.Timeout)
+		if c.opts.Atomic {
+			deadline = groupDeadline
+		}
+		err := c.pollUntilReady(ws.ID, deadline)
+		if err == nil {
+			err = c.runReadyProbes(ws)
+		}
+		if err == nil {
+			if c.ready == nil {
+				c.ready = map[string]bool{}
+			}
+			c.ready[ws.ID] = true
+			c.events.emit(workspaceEvent(EventInstanceReady, ws))
+		} else {
+			ev := workspaceEvent(EventInstanceNotReady, ws)
+			ev.Error = err.Error()
+			c.events.emit(ev)
+			notReady++
 			if strings.Contains(err.Error(), "timeout waiting") {
 				c.logf("  %s: Timeout waiting for ready state\n", ws.Name)
 			} else {
 				c.logf("  %s: %s\n", ws.Name, c.colorize(err.Error(), c.t.Red))
 			}
+			c.attempt(ws.InstanceType).failures = append(c.attempt(ws.InstanceType).failures, fmt.Sprintf("%s not ready: %s", ws.Name, err.Error()))
 		}
 	}
+	return notReady
 }
 
-// printSummary outputs the final creation summary
+// runReadyProbes waits for the --ready-when probes of a running instance
+func (c *createContext) runReadyProbes(ws *entity.Workspace) error {
+	if len(c.opts.ReadyWhen) == 0 {
+		return nil
+	}
+	if err := readiness.RefreshSSHConfig(c.store); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	results := readiness.Wait(c.checker, string(ws.GetLocalIdentifier()), c.opts.ReadyWhen, c.logf)
+	if c.readyResults == nil {
+		c.readyResults = map[string][]readiness.Result{}
+	}
+	c.readyResults[ws.ID] = results
+	if failure := readiness.FirstFailure(results); failure != nil {
+		return breverrors.NewValidationError("ready check " + failure.String())
+	}
+	c.logf("  %s: %s\n", ws.Name, c.colorize("Ready checks passed", c.t.Green))
+	return nil
+}
+
+// rollbackAtomic deletes every instance created in an --atomic run and returns
+// an error with the reason. A per-type summary of the attempts is logged first.
+func (c *createContext) rollbackAtomic(workspaces []*entity.Workspace, reason string) error {
+	c.logf("\n%s\n", c.colorize("Atomic create failed: "+reason, c.t.Red))
+	c.printAttemptSummary()
+
+	var notDeleted []string
+	if len(workspaces) > 0 {
+		c.logf("\nRolling back %d instance(s)...\n", len(workspaces))
+	}
+	for _, ws := range workspaces {
+		c.logf("  Deleting %s...", ws.Name)
+		_, err := c.store.DeleteWorkspace(ws.ID)
+		if err != nil {
+			c.logf(" Failed: %s\n", err.Error())
+			notDeleted = append(notDeleted, ws.Name)
+		} else {
+			c.logf(" Done\n")
+		}
+		c.emitCleanup(ws, CleanupReasonAtomic, err)
+	}
+
+	msg := "atomic create failed: " + reason
+	if len(notDeleted) > 0 {
+		msg += fmt.Sprintf("; could not delete %s, run 'brev delete %s'", strings.Join(notDeleted, ", "), strings.Join(notDeleted, " "))
+	} else if len(workspaces) > 0 {
+		msg += "; all instances created in this run were deleted"
+	}
+	return breverrors.NewValidationError(msg)
+}
+
+// printAttemptSummary logs, per instance type, how many instances were created and why attempts failed
+func (c *createContext) printAttemptSummary() {
+	c.logf("\nAttempts by instance type:\n")
+	for _, instanceType := range c.attemptOrder {
+		a := c.attempts[instanceType]
+		c.logf("  %s: %d created, %d failed\n", instanceType, a.created, len(a.failures))
+
+		counts := map[string]int{}
+		var messages []string
+		for _, f := range a.failures {
+			if counts[f] == 0 {
+				messages = append(messages, f)
+			}
+			counts[f]++
+		}
+		for _, m := range messages {
+			if counts[m] > 1 {
+				c.logf("    - %s (x%d)\n", m, counts[m])
+			} else {
+				c.logf("    - %s\n", m)
+			}
+		}
+	}
+}
+
+// failedReadyChecks counts the instances whose --ready-when probes did not all pass
+func (c *createContext) failedReadyChecks() int {
+	failed := 0
+	for _, results := range c.readyResults {
+		if !readiness.AllPassed(results) {
+			failed++
+		}
+	}
+	return failed
+}
+
+// printSummary outputs the final creation summary. With --events the summary
+// event is written instead.
 func (c *createContext) printSummary(workspaces []*entity.Workspace) {
+	if c.events != nil {
+		return
+	}
 	if c.piped {
 		for _, ws := range workspaces {
 			fmt.Println(ws.Name)
@@ -961,17 +1397,28 @@ func (c *createContext) printSummary(workspaces []*entity.Workspace) {
 		c.t.Vprintf("Instance: %s\n", c.t.Green(ws.Name))
 		c.t.Vprintf("  ID: %s\n", ws.ID)
 		c.t.Vprintf("  Type: %s\n", ws.InstanceType)
+		if results, ok := c.readyResults[ws.ID]; ok {
+			c.t.Vprintf("  Ready checks:\n")
+			for _, r := range results {
+				c.t.Vprintf("    %s %s\n", r.Mark(), r)
+			}
+		}
 		displayConnectBreadCrumb(c.t, ws)
 		fmt.Print("\n")
 	}
 }
 
 // RunGPUCreate executes the GPU create with retry logic
-func RunGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore, opts GPUCreateOptions) error {
+func RunGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore, opts GPUCreateOptions) (err error) {
 	ctx, err := newCreateContext(t, gpuCreateStore, opts)
 	if err != nil {
 		return err
 	}
+	defer func() { ctx.emitSummary(err) }()
+
+	if err := ctx.checkBudget(); err != nil {
+		return err
+	}
 
 	// Auto-redeem coupon code if the launchable has one attached.
 	// This is silent — the UI doesn't surface coupon redemption to the user either.
@@ -1012,6 +1459,9 @@ func RunGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore, opts GPUC
 	}
 
 	// Check if we created enough instances
+	if len(successfulWorkspaces) < opts.Count && opts.Atomic {
+		return ctx.rollbackAtomic(successfulWorkspaces, fmt.Sprintf("only %d/%d instances were created", len(successfulWorkspaces), opts.Count))
+	}
 	if len(successfulWorkspaces) < opts.Count {
 		ctx.logf("\nWarning: Only created %d/%d instances\n", len(successfulWorkspaces), opts.Count)
 		if len(successfulWorkspaces) > 0 {
@@ -1024,9 +1474,37 @@ func RunGPUCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore, opts GPUC
 	}
 
 	successfulWorkspaces = ctx.cleanupExtraInstances(successfulWorkspaces)
-	ctx.waitForInstances(successfulWorkspaces)
+	notReady := ctx.waitForInstances(successfulWorkspaces)
+	if notReady > 0 && opts.Atomic {
+		return ctx.rollbackAtomic(successfulWorkspaces, fmt.Sprintf("%d/%d instances were not ready within %s", notReady, opts.Count, opts.Timeout))
+	}
 	ctx.printSummary(successfulWorkspaces)
 
+	if failed := ctx.failedReadyChecks(); failed > 0 {
+		return breverrors.NewValidationError(fmt.Sprintf("%d/%d instance(s) did not pass the --ready-when checks", failed, len(successfulWorkspaces)))
+	}
+
+	return nil
+}
+
+// checkBudget applies the spending caps before anything is created. Any of the
+// instance types may be used, so the most expensive one is assumed.
+func (c *createContext) checkBudget() error {
+	types := make([]string, 0, len(c.opts.InstanceTypes))
+	for _, spec := range c.opts.InstanceTypes {
+		types = append(types, spec.Type)
+	}
+	result, err := budget.Check(c.store, c.org.ID, []budget.Planned{{InstanceTypes: types, Count: c.opts.Count}}, budget.Options{
+		AllowOverBudget: c.opts.AllowOverBudget,
+		Confirmer:       c.opts.Confirmer,
+		Logf:            c.logf,
+	})
+	if err != nil {
+		return err
+	}
+	if result != nil && result.Overridden {
+		c.events.emit(CreateEvent{Event: EventBudgetOverride, Reason: strings.Join(result.Violations, "; ")})
+	}
 	return nil
 }
 
@@ -1036,6 +1514,13 @@ func (c *createContext) createWorkspace(name string, spec InstanceSpec) (*entity
 	cwOptions := store.NewCreateWorkspacesOptions(clusterID, name)
 	cwOptions.WithInstanceType(spec.Type)
 	cwOptions = resolveWorkspaceUserOptions(cwOptions, c.user)
+	if len(c.opts.Labels) > 0 {
+		labels := make(map[string]string, len(c.opts.Labels))
+		for k, v := range c.opts.Labels {
+			labels[k] = v
+		}
+		cwOptions.Labels = labels
+	}
 
 	if spec.DiskGB > 0 {
 		cwOptions.DiskStorage = fmt.Sprintf("%.0fGi", spec.DiskGB)
@@ -1055,6 +1540,9 @@ func (c *createContext) createWorkspace(name string, spec InstanceSpec) (*entity
 		if err != nil {
 			return nil, breverrors.WrapAndTrace(err)
 		}
+		if c.opts.Spec != nil {
+			c.opts.Spec.applyTo(cwOptions)
+		}
 	}
 
 	if cwOptions.WorkspaceGroupID == "" {
@@ -1078,7 +1566,8 @@ func (c *createContext) createWorkspace(name string, spec InstanceSpec) (*entity
 	return workspace, nil
 }
 
-func validateBuildMode(mode, containerImage, composeFile string) error {
+// ValidateBuildMode checks the build mode and the image or compose file it requires
+func ValidateBuildMode(mode, containerImage, composeFile string) error {
 	switch mode {
 	case "vm", "k8s", "container", "compose":
 		// valid
@@ -1146,9 +1635,12 @@ func applyBuildMode(cwOptions *store.CreateWorkspacesOptions, opts GPUCreateOpti
 		cwOptions.VMBuild = nil
 		composeConfig := &store.DockerCompose{}
 
-		if strings.HasPrefix(opts.ComposeFile, "http://") || strings.HasPrefix(opts.ComposeFile, "https://") {
+		switch {
+		case opts.ComposeFile == "":
+			// compose YAML inlined in a --from-file spec, set by CreateSpec.applyTo
+		case isURL(opts.ComposeFile):
 			composeConfig.FileURL = opts.ComposeFile
-		} else {
+		default:
 			content, err := os.ReadFile(opts.ComposeFile)
 			if err != nil {
 				return breverrors.WrapAndTrace(fmt.Errorf("could not read compose file %s: %w", opts.ComposeFile, err))
@@ -1379,32 +1871,33 @@ func resolveWorkspaceUserOptions(options *store.CreateWorkspacesOptions, user *e
 	return options
 }
 
-// pollUntilReady waits for a workspace to reach the running state
-func (c *createContext) pollUntilReady(wsID string) error {
-	deadline := time.Now().Add(c.opts.Timeout)
-
-	for time.Now().Before(deadline) {
+// pollUntilReady waits until the deadline for a workspace to reach the running state
+func (c *createContext) pollUntilReady(wsID string, deadline time.Time) error {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	err := util.Poll(ctx, util.DefaultPollOptions, func() (bool, error) {
 		ws, err := c.store.GetWorkspace(wsID)
 		if err != nil {
-			return breverrors.WrapAndTrace(err)
+			return false, breverrors.WrapAndTrace(err)
 		}
 
 		if ws.Status == entity.Running {
 			c.logf("  %s: %s\n", ws.Name, c.colorize("Ready", c.t.Green))
-			return nil
+			return true, nil
 		}
 
 		if ws.Status == entity.Failure {
 			if ws.StatusMessage != "" {
-				return breverrors.NewValidationError(fmt.Sprintf("instance %s failed: %s", ws.Name, ws.StatusMessage))
+				return false, breverrors.NewValidationError(fmt.Sprintf("instance %s failed: %s", ws.Name, ws.StatusMessage))
 			}
-			return breverrors.NewValidationError(fmt.Sprintf("instance %s failed", ws.Name))
+			return false, breverrors.NewValidationError(fmt.Sprintf("instance %s failed", ws.Name))
 		}
-
-		time.Sleep(5 * time.Second)
+		return false, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return breverrors.NewValidationError("timeout waiting for instance to be ready")
 	}
-
-	return breverrors.NewValidationError("timeout waiting for instance to be ready")
+	return breverrors.WrapAndTrace(err)
 }
 
 // displayConnectBreadCrumb shows connection instructions
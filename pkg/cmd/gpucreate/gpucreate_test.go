@@ -1,7 +1,9 @@
 package gpucreate
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,11 +11,14 @@ import (
 	"time"
 
 	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/cmd/readiness"
 	"github.com/brevdev/brev-cli/pkg/entity"
 	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
 	"github.com/brevdev/brev-cli/pkg/store"
 	"github.com/brevdev/brev-cli/pkg/terminal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockGPUCreateStore is a mock implementation of GPUCreateStore for testing
@@ -27,6 +32,9 @@ type MockGPUCreateStore struct {
 	CreatedWorkspaces         []*entity.Workspace
 	DeletedWorkspaceIDs       []string
 	FetchedLifeCycleScriptIDs []string
+	AllInstanceTypes          *gpusearch.AllInstanceTypesResponse
+	CatalogCachePolicy        *gpusearch.CatalogCachePolicy
+	RedeemedCoupons           []string
 }
 
 func NewMockGPUCreateStore() *MockGPUCreateStore {
@@ -46,6 +54,10 @@ func NewMockGPUCreateStore() *MockGPUCreateStore {
 	}
 }
 
+func (m *MockGPUCreateStore) SetCatalogCachePolicy(policy gpusearch.CatalogCachePolicy) {
+	m.CatalogCachePolicy = &policy
+}
+
 func (m *MockGPUCreateStore) GetCurrentUser() (*entity.User, error) {
 	return m.User, nil
 }
@@ -105,7 +117,7 @@ func (m *MockGPUCreateStore) GetWorkspaceByNameOrID(orgID string, nameOrID strin
 }
 
 func (m *MockGPUCreateStore) GetAllInstanceTypesWithWorkspaceGroups(orgID string) (*gpusearch.AllInstanceTypesResponse, error) {
-	return nil, nil
+	return m.AllInstanceTypes, nil
 }
 
 func (m *MockGPUCreateStore) GetLaunchable(launchableID string) (*store.LaunchableResponse, error) {
@@ -123,6 +135,7 @@ func (m *MockGPUCreateStore) GetLaunchableLifeCycleScript(launchableID, scriptID
 }
 
 func (m *MockGPUCreateStore) RedeemCouponCode(organizationID string, code string) (*store.RedeemCouponCodeResponse, error) {
+	m.RedeemedCoupons = append(m.RedeemedCoupons, code)
 	return &store.RedeemCouponCodeResponse{}, nil
 }
 
@@ -193,7 +206,7 @@ func TestParseLaunchableID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseLaunchableID(tt.input)
+			result, err := ParseLaunchableID(tt.input)
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -810,6 +823,47 @@ func TestGetFilteredInstanceTypesNoMatch(t *testing.T) {
 	assert.Len(t, specs, 0)
 }
 
+func TestGetFilteredInstanceTypesWithWhere(t *testing.T) {
+	mock := NewMockGPUCreateStore()
+
+	specs, err := getFilteredInstanceTypes(mock, &searchFilterFlags{where: `gpu ~ a10 && vram>=24`})
+	assert.NoError(t, err)
+	assert.Len(t, specs, 1)
+	assert.Equal(t, "g5.xlarge", specs[0].Type)
+
+	specs, err = getFilteredInstanceTypes(mock, &searchFilterFlags{where: `provider in ("nowhere")`})
+	assert.NoError(t, err)
+	assert.Len(t, specs, 0)
+}
+
+func TestGetFilteredInstanceTypesInvalidWhere(t *testing.T) {
+	mock := NewMockGPUCreateStore()
+
+	_, err := getFilteredInstanceTypes(mock, &searchFilterFlags{where: "vram >= "})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "position 9")
+}
+
+func TestInstanceTypesForFilters(t *testing.T) {
+	mock := NewMockGPUCreateStore()
+
+	specs, err := InstanceTypesForFilters(mock, files.SearchPreset{"gpu-name": "A10G", "sort": "price"})
+	assert.NoError(t, err)
+	assert.Len(t, specs, 1)
+	assert.Equal(t, "g5.xlarge", specs[0].Type)
+
+	specs, err = InstanceTypesForFilters(mock, files.SearchPreset{"min-vram": "80"})
+	assert.NoError(t, err)
+	assert.Len(t, specs, 0)
+
+	_, err = InstanceTypesForFilters(mock, files.SearchPreset{"min-vram": "lots"})
+	assert.Error(t, err)
+
+	_, err = InstanceTypesForFilters(mock, files.SearchPreset{"count": "2"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown filter(s): count")
+}
+
 func TestParseTableInput(t *testing.T) {
 	tableInput := strings.Join([]string{
 		"TYPE           TARGET_DISK  GPU    COUNT  VRAM/GPU  TOTAL VRAM  CAPABILITY  VCPUs  $/HR",
@@ -917,11 +971,157 @@ func TestPollUntilReadyReportsWorkspaceFailureMessage(t *testing.T) {
 		opts:  GPUCreateOptions{Timeout: time.Second},
 	}
 
-	err := ctx.pollUntilReady("ws-failed")
+	err := ctx.pollUntilReady("ws-failed", time.Now().Add(time.Second))
 
 	assert.ErrorContains(t, err, "instance test failed: unexpected end of JSON input")
 }
 
+// withWorkspaceGroups makes the given instance types creatable in the mock
+func withWorkspaceGroups(m *MockGPUCreateStore, types ...string) *MockGPUCreateStore {
+	m.AllInstanceTypes = &gpusearch.AllInstanceTypesResponse{}
+	for _, instanceType := range types {
+		m.AllInstanceTypes.AllInstanceTypes = append(m.AllInstanceTypes.AllInstanceTypes, gpusearch.InstanceType{
+			Type:            instanceType,
+			WorkspaceGroups: []gpusearch.WorkspaceGroup{{ID: "wg-" + instanceType}},
+		})
+	}
+	return m
+}
+
+func atomicCreateOptions(count int, types ...string) GPUCreateOptions {
+	opts := GPUCreateOptions{Name: "cluster", Count: count, Parallel: 1, Timeout: time.Second, Mode: "vm", Atomic: true}
+	for _, instanceType := range types {
+		opts.InstanceTypes = append(opts.InstanceTypes, InstanceSpec{Type: instanceType})
+	}
+	return opts
+}
+
+func TestRunGPUCreateAtomicRollsBackPartialFleet(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge", "g5.2xlarge")
+	mock.CreateErrorTypes["g5.2xlarge"] = errors.New("no capacity")
+	calls := 0
+
+	// Only the first g5.xlarge create succeeds
+	limited := &limitedCreateStore{MockGPUCreateStore: mock, limit: 1, calls: &calls}
+
+	err := RunGPUCreate(terminal.New(), limited, atomicCreateOptions(3, "g5.xlarge", "g5.2xlarge"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only 1/3 instances were created")
+	assert.Contains(t, err.Error(), "all instances created in this run were deleted")
+	assert.Equal(t, []string{"ws-cluster-1"}, mock.DeletedWorkspaceIDs)
+}
+
+func TestRunGPUCreateAtomicRollsBackWhenNotReady(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+	failing := &failingReadyStore{MockGPUCreateStore: mock, failID: "ws-cluster-2"}
+
+	err := RunGPUCreate(terminal.New(), failing, atomicCreateOptions(2, "g5.xlarge"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1/2 instances were not ready")
+	assert.ElementsMatch(t, []string{"ws-cluster-1", "ws-cluster-2"}, mock.DeletedWorkspaceIDs)
+}
+
+func TestRunGPUCreateAtomicReportsFailedDeletes(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+	calls := 0
+	limited := &limitedCreateStore{MockGPUCreateStore: mock, limit: 1, calls: &calls}
+	mock.DeleteError = errors.New("boom")
+
+	err := RunGPUCreate(terminal.New(), limited, atomicCreateOptions(2, "g5.xlarge"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not delete cluster-1")
+}
+
+func TestRunGPUCreateAtomicSucceeds(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+
+	err := RunGPUCreate(terminal.New(), mock, atomicCreateOptions(2, "g5.xlarge"))
+	require.NoError(t, err)
+	assert.Len(t, mock.CreatedWorkspaces, 2)
+	assert.Empty(t, mock.DeletedWorkspaceIDs)
+}
+
+func TestReadyWhenValidation(t *testing.T) {
+	cmd := NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	cmd.SetArgs([]string{"my-instance", "--type", "g5.xlarge", "--ready-when", "tcp:8888", "--detached"})
+	assert.ErrorContains(t, cmd.Execute(), "--ready-when cannot be used with --detached")
+
+	cmd = NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	cmd.SetArgs([]string{"my-instance", "--type", "g5.xlarge", "--ready-when", "tcp:nope"})
+	assert.ErrorContains(t, cmd.Execute(), "invalid port")
+}
+
+func TestAtomicRejectsDetached(t *testing.T) {
+	cmd := NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	cmd.SetArgs([]string{"my-cluster", "--type", "g5.xlarge", "--atomic", "--detached"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--atomic")
+}
+
+// probeChecker passes probes for the aliases in ready
+type probeChecker struct {
+	ready map[string]bool
+}
+
+func (p probeChecker) Check(_ context.Context, sshAlias string, _ readiness.Probe) error {
+	if p.ready[sshAlias] {
+		return nil
+	}
+	return errors.New("not yet")
+}
+
+func TestWaitForInstancesRunsReadyProbes(t *testing.T) {
+	mock := NewMockGPUCreateStore()
+	probe := readiness.Probe{Kind: readiness.KindFile, Target: "/tmp/done", Timeout: time.Millisecond, Interval: time.Millisecond}
+	ctx := &createContext{
+		t:       terminal.New(),
+		store:   mock,
+		opts:    GPUCreateOptions{Timeout: time.Second, ReadyWhen: []readiness.Probe{probe}},
+		logf:    func(string, ...interface{}) {},
+		checker: probeChecker{ready: map[string]bool{"node-1": true}},
+	}
+	workspaces := []*entity.Workspace{
+		{ID: "ws-1", Name: "node-1", InstanceType: "g5.xlarge"},
+		{ID: "ws-2", Name: "node-2", InstanceType: "g5.xlarge"},
+	}
+
+	notReady := ctx.waitForInstances(workspaces)
+	assert.Equal(t, 1, notReady)
+	assert.True(t, readiness.AllPassed(ctx.readyResults["ws-1"]))
+	assert.False(t, readiness.AllPassed(ctx.readyResults["ws-2"]))
+	assert.Equal(t, 1, ctx.failedReadyChecks())
+	assert.Contains(t, ctx.attempts["g5.xlarge"].failures[0], "node-2 not ready: ready check failed file:/tmp/done")
+}
+
+// limitedCreateStore fails every create after the first limit calls
+type limitedCreateStore struct {
+	*MockGPUCreateStore
+	limit int
+	calls *int
+}
+
+func (s *limitedCreateStore) CreateWorkspace(organizationID string, options *store.CreateWorkspacesOptions) (*entity.Workspace, error) {
+	*s.calls++
+	if *s.calls > s.limit {
+		return nil, errors.New("no capacity")
+	}
+	return s.MockGPUCreateStore.CreateWorkspace(organizationID, options)
+}
+
+// failingReadyStore reports one created workspace as failed while waiting for it to be ready
+type failingReadyStore struct {
+	*MockGPUCreateStore
+	failID string
+}
+
+func (s *failingReadyStore) GetWorkspace(workspaceID string) (*entity.Workspace, error) {
+	if workspaceID == s.failID {
+		return &entity.Workspace{ID: workspaceID, Name: "cluster-2", Status: entity.Failure}, nil
+	}
+	return s.MockGPUCreateStore.GetWorkspace(workspaceID)
+}
+
 func TestInlineLaunchableLifeCycleScript(t *testing.T) {
 	t.Run("fetches and inlines lifecycle script body", func(t *testing.T) {
 		mockStore := NewMockGPUCreateStore()
@@ -1150,3 +1350,70 @@ func TestCreateInstancesWithTypeBypassesValidationForLaunchable(t *testing.T) {
 	assert.Len(t, result.successes, 1, "expected the launchable instance to be created")
 	assert.Len(t, mock.CreatedWorkspaces, 1)
 }
+
+// budgetCreateStore adds spending caps and running workspaces to the mock
+type budgetCreateStore struct {
+	*MockGPUCreateStore
+	policy  *files.BudgetPolicy
+	running []entity.Workspace
+}
+
+func (s *budgetCreateStore) GetTeamBudgetPolicy() (*files.BudgetPolicy, string, error) {
+	return nil, "", nil
+}
+
+func (s *budgetCreateStore) GetPersonalBudgetPolicy() (*files.BudgetPolicy, error) {
+	return s.policy, nil
+}
+
+func (s *budgetCreateStore) GetWorkspaces(_ string, _ *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	return s.running, nil
+}
+
+func TestRunGPUCreateBudget(t *testing.T) {
+	newStore := func() (*MockGPUCreateStore, *budgetCreateStore) {
+		mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+		return mock, &budgetCreateStore{
+			MockGPUCreateStore: mock,
+			policy:             &files.BudgetPolicy{MaxHourly: 3},
+			running:            []entity.Workspace{{Status: entity.Running, InstanceType: "g5.xlarge"}},
+		}
+	}
+	opts := GPUCreateOptions{Name: "node", Count: 2, Parallel: 1, Timeout: time.Second, Mode: "vm", Detached: true,
+		InstanceTypes: []InstanceSpec{{Type: "g5.xlarge"}}}
+
+	t.Run("refuses without confirmation", func(t *testing.T) {
+		mock, s := newStore()
+		launchableOpts := opts
+		launchableOpts.LaunchableInfo = &store.LaunchableResponse{CouponCode: "GPU-CREDITS"}
+		err := RunGPUCreate(terminal.New(), s, launchableOpts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "over the hourly cap of $3.00/hr")
+		assert.Empty(t, mock.CreatedWorkspaces)
+		// the coupon is only redeemed once the budget allows the create
+		assert.Empty(t, mock.RedeemedCoupons)
+	})
+
+	t.Run("within budget", func(t *testing.T) {
+		mock, s := newStore()
+		s.policy.MaxHourly = 5
+		require.NoError(t, RunGPUCreate(terminal.New(), s, opts))
+		assert.Len(t, mock.CreatedWorkspaces, 2)
+	})
+
+	t.Run("override is recorded", func(t *testing.T) {
+		mock, s := newStore()
+		var buf strings.Builder
+		overOpts := opts
+		overOpts.AllowOverBudget = true
+		overOpts.Events = &buf
+		require.NoError(t, RunGPUCreate(terminal.New(), s, overOpts))
+		assert.Len(t, mock.CreatedWorkspaces, 2)
+
+		first := strings.SplitN(buf.String(), "\n", 2)[0]
+		var ev CreateEvent
+		require.NoError(t, json.Unmarshal([]byte(first), &ev))
+		assert.Equal(t, EventBudgetOverride, ev.Event)
+		assert.Contains(t, ev.Reason, "hourly cap")
+	})
+}
@@ -0,0 +1,171 @@
+package gpucreate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/names"
+)
+
+// maxNameSuffix bounds the search for a free name when a rendered name collides
+const maxNameSuffix = 100
+
+// NameTemplateHelp describes the --name-template fields
+const NameTemplateHelp = `Go template for instance names, e.g. '{{.Base}}-{{.GPU | lower}}-{{.Index | printf "%02d"}}'.
+Fields: .Base, .Index (1-based), .Type, .GPU, .Provider, .Date (YYYYMMDD). Functions: lower, upper`
+
+// NameData holds the fields available to --name-template
+type NameData struct {
+	Base     string
+	Index    int // 1-based
+	Type     string
+	GPU      string
+	Provider string
+	Date     string // YYYYMMDD, UTC
+}
+
+var nameTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// ParseNameTemplate parses a --name-template value and checks that it renders
+// a valid name for a sample instance
+func ParseNameTemplate(text, base string) (*template.Template, error) {
+	tmpl, err := template.New("name").Funcs(nameTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --name-template: %s", err.Error()))
+	}
+	sample := NameData{Base: base, Index: 1, Type: "g5.xlarge", GPU: "A10G", Provider: "aws", Date: "20250101"}
+	if _, err := renderName(tmpl, sample); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+func renderName(tmpl *template.Template, data NameData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", breverrors.NewValidationError(fmt.Sprintf("invalid --name-template: %s", err.Error()))
+	}
+	name := strings.TrimSpace(buf.String())
+	if err := names.ValidateNodeName(name); err != nil {
+		return "", breverrors.NewValidationError(fmt.Sprintf("--name-template rendered %q: %s", name, err.Error()))
+	}
+	return name, nil
+}
+
+// nameData returns the template fields for the instance at idx created with instanceType
+func (c *createContext) nameData(idx int, instanceType string) NameData {
+	data := NameData{Base: c.opts.Name, Index: idx + 1, Type: instanceType, Date: c.startedAt.UTC().Format("20060102")}
+	if info := c.instanceTypeInfo(instanceType); info != nil {
+		data.Provider = info.Provider
+		if len(info.SupportedGPUs) > 0 {
+			data.GPU = info.SupportedGPUs[0].Name
+		}
+	}
+	return data
+}
+
+func (c *createContext) instanceTypeInfo(instanceType string) *gpusearch.InstanceType {
+	if c.allInstanceTypes == nil {
+		return nil
+	}
+	for i := range c.allInstanceTypes.AllInstanceTypes {
+		if c.allInstanceTypes.AllInstanceTypes[i].Type == instanceType {
+			return &c.allInstanceTypes.AllInstanceTypes[i]
+		}
+	}
+	return nil
+}
+
+// instanceName returns the name for the instance at idx. Without --name-template
+// this is the base name, with the index when creating more than one. Templated
+// names are checked against existing workspaces and names reserved by this run;
+// a collision takes the first free -2, -3, ... suffix. The name stays reserved
+// until releaseName is called.
+func (c *createContext) instanceName(idx int, instanceType string) (string, error) {
+	if c.opts.NameTemplate == nil {
+		if c.opts.Count > 1 {
+			return fmt.Sprintf("%s-%d", c.opts.Name, idx+1), nil
+		}
+		return c.opts.Name, nil
+	}
+
+	rendered, err := renderName(c.opts.NameTemplate, c.nameData(idx, instanceType))
+	if err != nil {
+		return "", err
+	}
+
+	c.namesMu.Lock()
+	defer c.namesMu.Unlock()
+	if c.reservedNames == nil {
+		c.reservedNames = map[string]bool{}
+	}
+	for n := 1; n <= maxNameSuffix; n++ {
+		candidate := rendered
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", rendered, n)
+		}
+		if c.reservedNames[candidate] {
+			continue
+		}
+		taken, err := c.nameTaken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			continue
+		}
+		if candidate != rendered {
+			c.logf("Name %s is taken, using %s\n", rendered, candidate)
+		}
+		c.reservedNames[candidate] = true
+		return candidate, nil
+	}
+	return "", breverrors.NewValidationError(fmt.Sprintf("could not find a free name for %q", rendered))
+}
+
+// reserveNames renders and reserves the names for the instances at startIdx up
+// to startIdx+count in index order, so collision suffixes follow the index and
+// not the order parallel workers happen to run in. On error nothing stays
+// reserved.
+func (c *createContext) reserveNames(startIdx, count int, instanceType string) (map[int]string, error) {
+	reserved := make(map[int]string, count)
+	for idx := startIdx; idx < startIdx+count; idx++ {
+		name, err := c.instanceName(idx, instanceType)
+		if err != nil {
+			for _, n := range reserved {
+				c.releaseName(n)
+			}
+			return nil, err
+		}
+		reserved[idx] = name
+	}
+	return reserved, nil
+}
+
+// releaseName frees a name reserved by instanceName after a failed create
+func (c *createContext) releaseName(name string) {
+	c.namesMu.Lock()
+	defer c.namesMu.Unlock()
+	delete(c.reservedNames, name)
+}
+
+// nameTaken checks whether a workspace with this name already exists
+func (c *createContext) nameTaken(name string) (bool, error) {
+	workspaces, err := c.store.GetWorkspaceByNameOrID(c.org.ID, name)
+	if err != nil {
+		return false, breverrors.WrapAndTrace(err)
+	}
+	for _, ws := range workspaces {
+		if ws.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
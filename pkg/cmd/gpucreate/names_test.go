@@ -0,0 +1,139 @@
+package gpucreate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// existingNamesStore reports workspaces with the given names as existing
+type existingNamesStore struct {
+	*MockGPUCreateStore
+	existing map[string]bool
+}
+
+func (s *existingNamesStore) GetWorkspaceByNameOrID(_ string, nameOrID string) ([]entity.Workspace, error) {
+	if s.existing[nameOrID] {
+		return []entity.Workspace{{ID: "old-" + nameOrID, Name: nameOrID}}, nil
+	}
+	return nil, nil
+}
+
+func TestParseNameTemplate(t *testing.T) {
+	_, err := ParseNameTemplate(`{{.Base}}-{{.GPU | lower}}-{{.Index | printf "%02d"}}`, "train")
+	require.NoError(t, err)
+
+	for input, message := range map[string]string{
+		`{{.Base`:                 "invalid --name-template",
+		`{{.Region}}`:             "invalid --name-template",
+		`{{.Base}} {{.Index}}`:    "letters, digits",
+		`{{.Base | shout}}`:       "function \"shout\" not defined",
+		`-{{.Base}}-{{.Index}}`:   "start with",
+		`{{.Base}}/{{.Provider}}`: "letters, digits",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseNameTemplate(input, "train")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), message)
+		})
+	}
+}
+
+func TestInstanceNameFromTemplate(t *testing.T) {
+	tmpl, err := ParseNameTemplate(`{{.Base}}-{{.GPU | lower}}-{{.Provider}}-{{.Index | printf "%02d"}}-{{.Date}}`, "train")
+	require.NoError(t, err)
+	ctx := &createContext{
+		store:     NewMockGPUCreateStore(),
+		org:       &entity.Organization{ID: "org-123"},
+		opts:      GPUCreateOptions{Name: "train", Count: 2, NameTemplate: tmpl},
+		startedAt: time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC),
+		logf:      func(string, ...interface{}) {},
+		allInstanceTypes: &gpusearch.AllInstanceTypesResponse{AllInstanceTypes: []gpusearch.InstanceType{
+			{Type: "g5.xlarge", Provider: "aws", SupportedGPUs: []gpusearch.GPU{{Name: "A10G"}}},
+		}},
+	}
+
+	name, err := ctx.instanceName(1, "g5.xlarge")
+	require.NoError(t, err)
+	assert.Equal(t, "train-a10g-aws-02-20250304", name)
+}
+
+func TestInstanceNameResolvesCollisions(t *testing.T) {
+	tmpl, err := ParseNameTemplate(`{{.Base}}-{{.Type}}`, "box")
+	require.NoError(t, err)
+	ctx := &createContext{
+		store: &existingNamesStore{MockGPUCreateStore: NewMockGPUCreateStore(), existing: map[string]bool{"box-g5.xlarge": true}},
+		org:   &entity.Organization{ID: "org-123"},
+		opts:  GPUCreateOptions{Name: "box", Count: 3, NameTemplate: tmpl},
+		logf:  func(string, ...interface{}) {},
+	}
+
+	var got []string
+	for idx := 0; idx < 3; idx++ {
+		name, err := ctx.instanceName(idx, "g5.xlarge")
+		require.NoError(t, err)
+		got = append(got, name)
+	}
+	assert.Equal(t, []string{"box-g5.xlarge-2", "box-g5.xlarge-3", "box-g5.xlarge-4"}, got)
+
+	ctx.releaseName("box-g5.xlarge-3")
+	name, err := ctx.instanceName(3, "g5.xlarge")
+	require.NoError(t, err)
+	assert.Equal(t, "box-g5.xlarge-3", name)
+}
+
+func TestInstanceNameWithoutTemplate(t *testing.T) {
+	ctx := &createContext{opts: GPUCreateOptions{Name: "node", Count: 2}}
+	name, err := ctx.instanceName(0, "g5.xlarge")
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", name)
+
+	ctx.opts.Count = 1
+	name, err = ctx.instanceName(0, "g5.xlarge")
+	require.NoError(t, err)
+	assert.Equal(t, "node", name)
+}
+
+func TestRunGPUCreateWithNameTemplate(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+	tmpl, err := ParseNameTemplate(`{{.Base}}-{{.Index | printf "%02d"}}`, "exp")
+	require.NoError(t, err)
+	opts := GPUCreateOptions{Name: "exp", NameTemplate: tmpl, Count: 2, Parallel: 1, Timeout: time.Second, Mode: "vm", Detached: true,
+		InstanceTypes: []InstanceSpec{{Type: "g5.xlarge"}}}
+
+	require.NoError(t, RunGPUCreate(terminal.New(), mock, opts))
+	require.Len(t, mock.CreatedWorkspaces, 2)
+	assert.Equal(t, "exp-01", mock.CreatedWorkspaces[0].Name)
+	assert.Equal(t, "exp-02", mock.CreatedWorkspaces[1].Name)
+}
+
+func TestRunGPUCreateNamesFollowIndexInParallel(t *testing.T) {
+	// the third instance renders the name the second takes on collision, so
+	// the names depend on the order they are reserved in
+	tmpl, err := ParseNameTemplate(`{{.Base}}{{if eq .Index 3}}-2{{end}}`, "box")
+	require.NoError(t, err)
+
+	for i := 0; i < 20; i++ {
+		mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge")
+		opts := GPUCreateOptions{Name: "box", NameTemplate: tmpl, Count: 3, Parallel: 3, Timeout: time.Second, Mode: "vm", Detached: true,
+			InstanceTypes: []InstanceSpec{{Type: "g5.xlarge"}}}
+		ctx, err := newCreateContext(terminal.New(), mock, opts)
+		require.NoError(t, err)
+
+		names, err := ctx.reserveNames(0, 3, "g5.xlarge")
+		require.NoError(t, err)
+		assert.Equal(t, map[int]string{0: "box", 1: "box-2", 2: "box-2-2"}, names)
+
+		require.NoError(t, RunGPUCreate(terminal.New(), mock, opts))
+		var created []string
+		for _, ws := range mock.CreatedWorkspaces {
+			created = append(created, ws.Name)
+		}
+		assert.ElementsMatch(t, []string{"box", "box-2", "box-2-2"}, created)
+	}
+}
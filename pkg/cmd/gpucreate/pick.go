@@ -0,0 +1,129 @@
+package gpucreate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/spf13/cobra"
+)
+
+// NewSearchPickHandler returns the handler used by 'brev search --pick'. It asks
+// for an instance name, confirms the estimated hourly cost and then creates one
+// instance, trying the picked types in order.
+func NewSearchPickHandler(t *terminal.Terminal, gpuCreateStore GPUCreateStore) gpusearch.PickHandler {
+	return func(picked []gpusearch.GPUInstanceInfo) error {
+		if len(picked) == 0 {
+			t.Vprint(t.Yellow("No instance types picked, nothing to create"))
+			return nil
+		}
+
+		name := terminal.PromptGetInput(terminal.PromptContent{
+			Label:    "Instance name:",
+			ErrorMsg: "an instance name is required",
+		})
+		return runPickedCreate(t, gpuCreateStore, terminal.Prompter{}, name, picked)
+	}
+}
+
+// runPickedCreate confirms the cost of the picked types and creates the instance
+func runPickedCreate(t *terminal.Terminal, gpuCreateStore GPUCreateStore, confirmer terminal.Confirmer, name string, picked []gpusearch.GPUInstanceInfo) error {
+	if err := validateArgs(name, 1); err != nil {
+		return err
+	}
+
+	opts := pickedCreateOptions(name, picked)
+	opts.Confirmer = confirmer
+	displayPickedCost(t, opts.InstanceTypes, picked)
+	if !confirmer.ConfirmYesNo(fmt.Sprintf("Create %s?", name)) {
+		t.Vprint("Cancelled, nothing was created")
+		return nil
+	}
+
+	// search may have read a cached catalog; refetch it like create does
+	if err := applyCatalogCachePolicy(gpuCreateStore, gpusearch.CatalogCacheFlags{}, false); err != nil {
+		return err
+	}
+	return RunGPUCreate(t, gpuCreateStore, opts)
+}
+
+// pickedCreateOptions builds create options for one instance from the picked
+// types, using the same defaults as 'brev create'. Duplicate types are dropped.
+func pickedCreateOptions(name string, picked []gpusearch.GPUInstanceInfo) GPUCreateOptions {
+	var specs []InstanceSpec
+	seen := map[string]bool{}
+	for _, inst := range picked {
+		if seen[inst.Type] {
+			continue
+		}
+		seen[inst.Type] = true
+		specs = append(specs, InstanceSpec{Type: inst.Type, DiskGB: inst.TargetDisk})
+	}
+
+	opts := defaultCreateOptions()
+	opts.Name = name
+	opts.InstanceTypes = specs
+	return opts
+}
+
+// defaultCreateOptions returns the options 'brev create' uses when no flags are
+// given, read from its registered flag defaults so the two cannot drift apart.
+func defaultCreateOptions() GPUCreateOptions {
+	var name, nameTemplate, instanceTypes, mode, containerImage, composeFile, launchable string
+	var count, parallel, timeout int
+	var detached, atomic, dryRun, jupyter bool
+	var readyWhen, startupScripts, scriptVars []string
+	var filters searchFilterFlags
+	registerCreateFlags(&cobra.Command{}, &name, &nameTemplate, &instanceTypes, &count, &parallel, &detached, &atomic, &readyWhen, &timeout, &startupScripts, &scriptVars, &dryRun, &mode, &jupyter, &containerImage, &composeFile, &launchable, &filters)
+
+	return GPUCreateOptions{
+		Count:    count,
+		Parallel: max(1, parallel),
+		Detached: detached,
+		Atomic:   atomic,
+		Timeout:  time.Duration(timeout) * time.Second,
+		Mode:     mode,
+		Jupyter:  jupyter,
+	}
+}
+
+// pickedPrices returns the hourly price of each spec, looked up from the picked results
+func pickedPrices(specs []InstanceSpec, picked []gpusearch.GPUInstanceInfo) []float64 {
+	byType := map[string]float64{}
+	for _, inst := range picked {
+		if _, ok := byType[inst.Type]; !ok {
+			byType[inst.Type] = inst.PricePerHour
+		}
+	}
+	prices := make([]float64, len(specs))
+	for i, spec := range specs {
+		prices[i] = byType[spec.Type]
+	}
+	return prices
+}
+
+// displayPickedCost shows the picked types in fallback order with the estimated hourly cost
+func displayPickedCost(t *terminal.Terminal, specs []InstanceSpec, picked []gpusearch.GPUInstanceInfo) {
+	prices := pickedPrices(specs, picked)
+
+	t.Vprint("\nInstance types to try, in order:")
+	for i, spec := range specs {
+		t.Vprintf("  %d. %-28s $%.2f/hr\n", i+1, spec.Type, prices[i])
+	}
+	t.Vprint("")
+	t.Vprintf("Estimated cost: %s (%s/day) with %s\n",
+		t.Green(fmt.Sprintf("$%.2f/hr", prices[0])), fmt.Sprintf("$%.2f", prices[0]*24), specs[0].Type)
+	if maxPrice := maxOf(prices); maxPrice > prices[0] {
+		t.Vprintf("Falling back to a later type can cost up to %s\n", t.Yellow(fmt.Sprintf("$%.2f/hr", maxPrice)))
+	}
+}
+
+// maxOf returns the largest value
+func maxOf(values []float64) float64 {
+	m := 0.0
+	for _, v := range values {
+		m = max(m, v)
+	}
+	return m
+}
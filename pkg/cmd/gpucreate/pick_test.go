@@ -0,0 +1,64 @@
+package gpucreate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockConfirmer struct{ confirm bool }
+
+func (m mockConfirmer) ConfirmYesNo(_ string) bool { return m.confirm }
+
+func pickedTestInstances() []gpusearch.GPUInstanceInfo {
+	return []gpusearch.GPUInstanceInfo{
+		{Type: "g5.xlarge", PricePerHour: 1.0, TargetDisk: 500},
+		{Type: "g5.xlarge", PricePerHour: 1.0, TargetDisk: 500},
+		{Type: "p4d.24xlarge", PricePerHour: 32.77},
+	}
+}
+
+func TestPickedCreateOptions(t *testing.T) {
+	opts := pickedCreateOptions("my-box", pickedTestInstances())
+
+	assert.Equal(t, "my-box", opts.Name)
+	assert.Equal(t, []InstanceSpec{{Type: "g5.xlarge", DiskGB: 500}, {Type: "p4d.24xlarge"}}, opts.InstanceTypes)
+	assert.Equal(t, 1, opts.Count)
+	assert.Equal(t, 1, opts.Parallel)
+	assert.Equal(t, 300*time.Second, opts.Timeout)
+	assert.Equal(t, "vm", opts.Mode)
+	assert.True(t, opts.Jupyter)
+}
+
+func TestPickedPrices(t *testing.T) {
+	picked := pickedTestInstances()
+	opts := pickedCreateOptions("my-box", picked)
+	assert.Equal(t, []float64{1.0, 32.77}, pickedPrices(opts.InstanceTypes, picked))
+}
+
+func TestRunPickedCreateCancelled(t *testing.T) {
+	mock := NewMockGPUCreateStore()
+	err := runPickedCreate(terminal.New(), mock, mockConfirmer{confirm: false}, "my-box", pickedTestInstances())
+	assert.NoError(t, err)
+	assert.Empty(t, mock.CreatedWorkspaces)
+}
+
+func TestRunPickedCreateRefreshesCatalog(t *testing.T) {
+	mock := withWorkspaceGroups(NewMockGPUCreateStore(), "g5.xlarge", "p4d.24xlarge")
+	err := runPickedCreate(terminal.New(), mock, mockConfirmer{confirm: true}, "my-box", pickedTestInstances())
+	require.NoError(t, err)
+	require.NotNil(t, mock.CatalogCachePolicy)
+	assert.Equal(t, gpusearch.CatalogCacheRefresh, mock.CatalogCachePolicy.Mode)
+	require.Len(t, mock.CreatedWorkspaces, 1)
+}
+
+func TestRunPickedCreateInvalidName(t *testing.T) {
+	mock := NewMockGPUCreateStore()
+	err := runPickedCreate(terminal.New(), mock, mockConfirmer{confirm: true}, "", pickedTestInstances())
+	assert.Error(t, err)
+	assert.Empty(t, mock.CreatedWorkspaces)
+}
@@ -0,0 +1,286 @@
+package gpucreate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// CreateSpec is a create request read with --from-file. Keys match the flags of
+// 'brev create' where there is one, and 'brev clone --print' writes this format.
+type CreateSpec struct {
+	Name  string `yaml:"name"`
+	Count *int   `yaml:"count"`
+	// Type is one type or a comma-separated list, as in --type. Types are tried in order.
+	Type  string   `yaml:"type"`
+	Types []string `yaml:"types"`
+	// Disk is the disk size, e.g. 500Gi; a bare number is taken as Gi
+	Disk string `yaml:"disk"`
+	// WorkspaceGroup is used when the instance-type catalog does not name one
+	WorkspaceGroup string `yaml:"workspace-group"`
+
+	Mode            string            `yaml:"mode"`
+	Jupyter         *bool             `yaml:"jupyter"`
+	JupyterOnStart  bool              `yaml:"jupyter-on-start"`
+	StartupScript   string            `yaml:"startup-script"`    // inline, or @file relative to the spec
+	StartupScriptID string            `yaml:"startup-script-id"` // a launchable lifecycle script
+	ContainerImage  string            `yaml:"container-image"`
+	Entrypoint      string            `yaml:"entrypoint"`
+	Registry        *RegistrySpec     `yaml:"registry"` // credentials for container-image
+	ComposeFile     string            `yaml:"compose-file"`
+	ComposeYAML     string            `yaml:"compose-yaml"` // inline compose file, used without compose-file
+	Env             map[string]string `yaml:"env"`          // compose environment variables
+	Registries      []RegistrySpec    `yaml:"registries"`   // credentials for compose images
+
+	Ports         map[string]string  `yaml:"ports"`
+	FirewallRules []FirewallRuleSpec `yaml:"firewall-rules"`
+	Labels        map[string]string  `yaml:"labels"` // merged with --label, which wins per key
+
+	// Source and Incomplete are written by 'brev clone --print' and ignored
+	Source     string   `yaml:"source"`
+	Incomplete []string `yaml:"incomplete"`
+}
+
+// RegistrySpec holds container registry credentials. PasswordEnv names an
+// environment variable to read the password from, to keep it out of the file.
+type RegistrySpec struct {
+	URL         string `yaml:"url"`
+	Username    string `yaml:"username"`
+	Password    string `yaml:"password"`
+	PasswordEnv string `yaml:"password-env"`
+}
+
+// FirewallRuleSpec opens a port. AllowedIPs "user-ip" is resolved to this machine's public IP.
+type FirewallRuleSpec struct {
+	Port       string   `yaml:"port"`
+	AllowedIPs string   `yaml:"allowed-ips"`
+	ClientIPs  []string `yaml:"client-ips"`
+}
+
+// ReadCreateSpec reads a spec file. Relative @file startup scripts and local
+// compose files are resolved against the spec's directory.
+func ReadCreateSpec(path string) (*CreateSpec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // user-provided spec path
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	spec, err := ParseCreateSpec(data)
+	if err != nil {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("%s: %v", path, err))
+	}
+	baseDir := filepath.Dir(path)
+	if strings.HasPrefix(spec.StartupScript, "@") {
+		spec.StartupScript = "@" + resolveSpecPath(strings.TrimPrefix(spec.StartupScript, "@"), baseDir)
+	}
+	if spec.ComposeFile != "" && !isURL(spec.ComposeFile) {
+		spec.ComposeFile = resolveSpecPath(spec.ComposeFile, baseDir)
+	}
+	return spec, nil
+}
+
+// unknownFieldRe matches the yaml.v3 message for a key missing from the struct
+var unknownFieldRe = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// ParseCreateSpec parses and validates a spec. JSON is accepted as a subset of YAML.
+// Unknown keys are rejected with their line numbers so typos do not go unnoticed.
+func ParseCreateSpec(data []byte) (*CreateSpec, error) {
+	var spec CreateSpec
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, breverrors.NewValidationError("spec file is empty")
+		}
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			problems := make([]string, 0, len(typeErr.Errors))
+			for _, problem := range typeErr.Errors {
+				problems = append(problems, unknownFieldRe.ReplaceAllString(problem, `unknown key "$1"`))
+			}
+			return nil, breverrors.NewValidationError("invalid spec file:\n  " + strings.Join(problems, "\n  "))
+		}
+		return nil, breverrors.NewValidationError(fmt.Sprintf("invalid spec file: %v", err))
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+func (s *CreateSpec) validate() error {
+	if s.Type != "" && len(s.Types) > 0 {
+		return breverrors.NewValidationError("use either type or types, not both")
+	}
+	if s.Count != nil && *s.Count < 1 {
+		return breverrors.NewValidationError("count must be at least 1")
+	}
+	if s.Registry != nil {
+		if err := s.Registry.resolvePassword(); err != nil {
+			return err
+		}
+	}
+	for i := range s.Registries {
+		if err := s.Registries[i].resolvePassword(); err != nil {
+			return err
+		}
+	}
+	for _, rule := range s.FirewallRules {
+		if rule.Port == "" {
+			return breverrors.NewValidationError("every firewall rule needs a port")
+		}
+	}
+	for key := range s.Labels {
+		if err := entity.ValidateLabelKey(key); err != nil {
+			return breverrors.NewValidationError(err.Error())
+		}
+	}
+	return nil
+}
+
+func (r *RegistrySpec) resolvePassword() error {
+	if r.PasswordEnv == "" {
+		return nil
+	}
+	if r.Password != "" {
+		return breverrors.NewValidationError("registry: use either password or password-env, not both")
+	}
+	r.Password = os.Getenv(r.PasswordEnv)
+	if r.Password == "" {
+		return breverrors.NewValidationError(fmt.Sprintf("registry: environment variable %s is not set", r.PasswordEnv))
+	}
+	return nil
+}
+
+func (r *RegistrySpec) registry() *store.Registry {
+	if r == nil {
+		return nil
+	}
+	return &store.Registry{Url: r.URL, Username: r.Username, Password: r.Password}
+}
+
+func resolveSpecPath(path, baseDir string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// applyToFlags copies the spec values that have a flag into flags that were not
+// set on the command line, so flags override the file. hasNameArg is true when
+// the name was passed as an argument.
+func (s *CreateSpec) applyToFlags(flags *pflag.FlagSet, hasNameArg bool) error {
+	types := s.Type
+	if len(s.Types) > 0 {
+		types = strings.Join(s.Types, ",")
+	}
+	values := map[string]string{
+		"type":            types,
+		"mode":            s.Mode,
+		"startup-script":  s.StartupScript,
+		"container-image": s.ContainerImage,
+		"compose-file":    s.ComposeFile,
+	}
+	if !hasNameArg {
+		values["name"] = s.Name
+	}
+	if s.Count != nil {
+		values["count"] = strconv.Itoa(*s.Count)
+	}
+	if s.Jupyter != nil {
+		values["jupyter"] = strconv.FormatBool(*s.Jupyter)
+	}
+	for name, value := range values {
+		if value == "" || flags.Changed(name) {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return breverrors.NewValidationError(fmt.Sprintf("spec file: invalid %s: %v", name, err))
+		}
+	}
+	return nil
+}
+
+// composeSource returns the compose file to validate the build mode with,
+// standing in for compose YAML inlined in the spec
+func (s *CreateSpec) composeSource(composeFile string) string {
+	if composeFile == "" && s != nil && s.ComposeYAML != "" {
+		return "compose-yaml"
+	}
+	return composeFile
+}
+
+// applyTo sets the fields of the create request that have no flag. It runs
+// after applyBuildMode, so the build mode chosen by flags or the file is kept.
+func (s *CreateSpec) applyTo(cwOptions *store.CreateWorkspacesOptions) {
+	if s.Disk != "" {
+		cwOptions.DiskStorage = normalizeDiskStorage(s.Disk)
+	}
+	if cwOptions.WorkspaceGroupID == "" {
+		cwOptions.WorkspaceGroupID = s.WorkspaceGroup
+	}
+	cwOptions.LaunchJupyterOnStart = s.JupyterOnStart
+	if len(s.Ports) > 0 {
+		cwOptions.PortMappings = s.Ports
+	}
+	if len(s.FirewallRules) > 0 {
+		rules := make([]store.CreateFirewallRule, 0, len(s.FirewallRules))
+		for _, rule := range s.FirewallRules {
+			rules = append(rules, store.CreateFirewallRule{Port: rule.Port, AllowedIPs: rule.AllowedIPs, ClientIPs: rule.ClientIPs})
+		}
+		cwOptions.FirewallRules = resolveFirewallRulesClientIP(rules, publicIPLookup)
+	}
+
+	if build := cwOptions.VMBuild; build != nil && s.StartupScriptID != "" {
+		if build.LifeCycleScriptAttr == nil {
+			build.LifeCycleScriptAttr = &store.LifeCycleScriptAttr{}
+		}
+		build.LifeCycleScriptAttr.ID = s.StartupScriptID
+	}
+	if container := cwOptions.CustomContainer; container != nil {
+		container.EntryPoint = s.Entrypoint
+		container.Registry = s.Registry.registry()
+	}
+	if compose := cwOptions.DockerCompose; compose != nil {
+		if compose.FileURL == "" && compose.YamlString == "" {
+			compose.YamlString = s.ComposeYAML
+		}
+		if len(s.Env) > 0 {
+			compose.EnvironmentVariables = s.Env
+		}
+		for i := range s.Registries {
+			compose.Registries = append(compose.Registries, s.Registries[i].registry())
+		}
+	}
+}
+
+// loadCreateSpec reads the --from-file spec, if any, and fills the flags not
+// set on the command line from it
+func loadCreateSpec(flags *pflag.FlagSet, path string, hasNameArg bool) (*CreateSpec, error) {
+	if path == "" {
+		return nil, nil
+	}
+	spec, err := ReadCreateSpec(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.applyToFlags(flags, hasNameArg); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
@@ -0,0 +1,174 @@
+package gpucreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const containerSpec = `
+name: trainer
+count: 2
+types: [g5.xlarge, g5.2xlarge]
+disk: "500"
+mode: container
+container-image: registry.example.com/team/trainer:latest
+entrypoint: /start.sh
+registry: {url: registry.example.com, username: ci, password-env: TEST_REGISTRY_TOKEN}
+ports: {tensorboard: "6006"}
+firewall-rules:
+  - {port: "6006", allowed-ips: user-ip}
+`
+
+func TestParseCreateSpec(t *testing.T) {
+	t.Setenv("TEST_REGISTRY_TOKEN", "s3cret")
+	spec, err := ParseCreateSpec([]byte(containerSpec))
+	require.NoError(t, err)
+	assert.Equal(t, "trainer", spec.Name)
+	require.NotNil(t, spec.Count)
+	assert.Equal(t, 2, *spec.Count)
+	assert.Equal(t, []string{"g5.xlarge", "g5.2xlarge"}, spec.Types)
+	assert.Equal(t, "s3cret", spec.Registry.Password)
+	assert.Equal(t, map[string]string{"tensorboard": "6006"}, spec.Ports)
+}
+
+func TestParseCreateSpecErrors(t *testing.T) {
+	_, err := ParseCreateSpec([]byte("name: a\nmode: vm\ncontainer_image: x\njupyterr: true\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `line 3: unknown key "container_image"`)
+	assert.Contains(t, err.Error(), `line 4: unknown key "jupyterr"`)
+
+	_, err = ParseCreateSpec([]byte(""))
+	assert.ErrorContains(t, err, "empty")
+
+	_, err = ParseCreateSpec([]byte("type: a\ntypes: [b]\n"))
+	assert.ErrorContains(t, err, "either type or types")
+
+	_, err = ParseCreateSpec([]byte("count: 0\n"))
+	assert.ErrorContains(t, err, "count")
+
+	_, err = ParseCreateSpec([]byte("registry: {password-env: TEST_UNSET_REGISTRY_TOKEN}\n"))
+	assert.ErrorContains(t, err, "TEST_UNSET_REGISTRY_TOKEN")
+
+	_, err = ParseCreateSpec([]byte("firewall-rules: [{allowed-ips: all}]\n"))
+	assert.ErrorContains(t, err, "port")
+
+	_, err = ParseCreateSpec([]byte("labels: {\"bad key\": x}\n"))
+	assert.ErrorContains(t, err, "invalid label key")
+}
+
+func TestParseCreateLabels(t *testing.T) {
+	labels, err := parseCreateLabels(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, labels)
+
+	spec := &CreateSpec{Labels: map[string]string{"team": "infra", "env": "dev"}}
+	labels, err = parseCreateLabels(spec, []string{"team=ml", "example.com/owner=alice", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "ml", "env": "dev", "example.com/owner": "alice", "empty": ""}, labels)
+	assert.Equal(t, "infra", spec.Labels["team"])
+
+	for _, bad := range []string{"team", "=ml", "-team=ml", "a b=c"} {
+		_, err := parseCreateLabels(nil, []string{bad})
+		assert.ErrorContains(t, err, "--label", bad)
+	}
+}
+
+func TestReadCreateSpecResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("startup-script: '@setup.sh'\ncompose-file: compose.yaml\n"), 0o600))
+
+	spec, err := ReadCreateSpec(path)
+	require.NoError(t, err)
+	assert.Equal(t, "@"+filepath.Join(dir, "setup.sh"), spec.StartupScript)
+	assert.Equal(t, filepath.Join(dir, "compose.yaml"), spec.ComposeFile)
+
+	require.NoError(t, os.WriteFile(path, []byte("compose-file: https://example.com/compose.yaml\n"), 0o600))
+	spec, err = ReadCreateSpec(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/compose.yaml", spec.ComposeFile)
+
+	require.NoError(t, os.WriteFile(path, []byte("\nnmae: x\n"), 0o600))
+	_, err = ReadCreateSpec(path)
+	assert.ErrorContains(t, err, path)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestCreateSpecFlagsOverrideFile(t *testing.T) {
+	t.Setenv("TEST_REGISTRY_TOKEN", "s3cret")
+	spec, err := ParseCreateSpec([]byte(containerSpec))
+	require.NoError(t, err)
+
+	cmd := NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	require.NoError(t, cmd.ParseFlags([]string{"--count", "3", "--type", "p4d.24xlarge"}))
+	require.NoError(t, spec.applyToFlags(cmd.Flags(), false))
+
+	flags := cmd.Flags()
+	count, _ := flags.GetInt("count")
+	types, _ := flags.GetString("type")
+	name, _ := flags.GetString("name")
+	mode, _ := flags.GetString("mode")
+	image, _ := flags.GetString("container-image")
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "p4d.24xlarge", types)
+	assert.Equal(t, "trainer", name)
+	assert.Equal(t, "container", mode)
+	assert.Equal(t, "registry.example.com/team/trainer:latest", image)
+
+	// a name argument wins over the file
+	cmd = NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	require.NoError(t, spec.applyToFlags(cmd.Flags(), true))
+	name, _ = cmd.Flags().GetString("name")
+	assert.Empty(t, name)
+	types, _ = cmd.Flags().GetString("type")
+	assert.Equal(t, "g5.xlarge,g5.2xlarge", types)
+}
+
+func TestCreateSpecApplyTo(t *testing.T) {
+	orig := publicIPLookup
+	publicIPLookup = func() (string, error) { return "203.0.113.7", nil }
+	defer func() { publicIPLookup = orig }()
+
+	t.Setenv("TEST_REGISTRY_TOKEN", "s3cret")
+	spec, err := ParseCreateSpec([]byte(containerSpec))
+	require.NoError(t, err)
+
+	cwOptions := store.NewCreateWorkspacesOptions("cluster", "trainer")
+	cwOptions.DiskStorage = "256Gi"
+	require.NoError(t, applyBuildMode(cwOptions, GPUCreateOptions{Mode: "container", ContainerImage: spec.ContainerImage, Spec: spec}))
+	spec.applyTo(cwOptions)
+
+	assert.Equal(t, "500Gi", cwOptions.DiskStorage)
+	assert.Equal(t, map[string]string{"tensorboard": "6006"}, cwOptions.PortMappings)
+	assert.Equal(t, []store.CreateFirewallRule{{Port: "6006", AllowedIPs: "user-ip", ClientIPs: []string{"203.0.113.7/32"}}}, cwOptions.FirewallRules)
+	require.NotNil(t, cwOptions.CustomContainer)
+	assert.Equal(t, "/start.sh", cwOptions.CustomContainer.EntryPoint)
+	assert.Equal(t, &store.Registry{Url: "registry.example.com", Username: "ci", Password: "s3cret"}, cwOptions.CustomContainer.Registry)
+}
+
+func TestCreateSpecInlineCompose(t *testing.T) {
+	spec, err := ParseCreateSpec([]byte("mode: compose\ncompose-yaml: |\n  services: {}\nenv: {A: b}\n"))
+	require.NoError(t, err)
+	require.NoError(t, ValidateBuildMode("compose", "", spec.composeSource("")))
+
+	cwOptions := store.NewCreateWorkspacesOptions("cluster", "svc")
+	require.NoError(t, applyBuildMode(cwOptions, GPUCreateOptions{Mode: "compose", Spec: spec}))
+	spec.applyTo(cwOptions)
+
+	require.NotNil(t, cwOptions.DockerCompose)
+	assert.Equal(t, "services: {}\n", cwOptions.DockerCompose.YamlString)
+	assert.Equal(t, map[string]string{"A": "b"}, cwOptions.DockerCompose.EnvironmentVariables)
+
+	// a compose file from the flags wins over the inline YAML
+	cwOptions = store.NewCreateWorkspacesOptions("cluster", "svc")
+	require.NoError(t, applyBuildMode(cwOptions, GPUCreateOptions{Mode: "compose", ComposeFile: "https://example.com/c.yaml", Spec: spec}))
+	spec.applyTo(cwOptions)
+	assert.Equal(t, "https://example.com/c.yaml", cwOptions.DockerCompose.FileURL)
+	assert.Empty(t, cwOptions.DockerCompose.YamlString)
+}
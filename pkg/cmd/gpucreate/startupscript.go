@@ -0,0 +1,212 @@
+package gpucreate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+)
+
+// includeDirective starts a line that is replaced by the named file. It is not
+// '#include' so C sources written by a heredoc are left alone.
+const includeDirective = "brev:include"
+
+// maxIncludeDepth bounds nested include directives
+const maxIncludeDepth = 16
+
+var (
+	includeRe   = regexp.MustCompile(`^\s*#\s*brev:include\s+(\S.*?)\s*$`)
+	scriptVarRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	scriptRefRe = regexp.MustCompile(`\$\{[A-Za-z_][A-Za-z0-9_]*\}`)
+)
+
+// scriptSection is one script of a composed startup script
+type scriptSection struct {
+	source  string
+	content string
+}
+
+// RenderStartupScript builds the startup script from the --startup-script values
+// in order. Each value is an inline script, @file, or @directory (its files in
+// name order). Several scripts are concatenated with section markers. '# brev:include
+// path' lines are replaced by the file, relative to the including script, and
+// ${KEY} is replaced for each of vars.
+func RenderStartupScript(values []string, vars map[string]string) (string, error) {
+	var sections []scriptSection
+	for _, value := range values {
+		loaded, err := loadScriptSections(value)
+		if err != nil {
+			return "", err
+		}
+		sections = append(sections, loaded...)
+	}
+	if len(sections) == 0 {
+		if len(vars) > 0 {
+			return "", breverrors.NewValidationError("--var needs a --startup-script to substitute into")
+		}
+		return "", nil
+	}
+
+	script := sections[0].content
+	if len(sections) > 1 {
+		script = joinScriptSections(sections)
+	}
+	return substituteScriptVars(script, vars)
+}
+
+// loadScriptSections reads one --startup-script value
+func loadScriptSections(value string) ([]scriptSection, error) {
+	if !strings.HasPrefix(value, "@") {
+		content, err := expandIncludes(value, ".", nil)
+		if err != nil {
+			return nil, err
+		}
+		return []scriptSection{{source: "inline script", content: content}}, nil
+	}
+
+	path := strings.TrimPrefix(value, "@")
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if !info.IsDir() {
+		content, err := readScriptFile(path, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []scriptSection{{source: path, content: content}}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	var sections []scriptSection
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		file := filepath.Join(path, entry.Name())
+		content, err := readScriptFile(file, nil)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, scriptSection{source: file, content: content})
+	}
+	if len(sections) == 0 {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("startup script directory %s has no scripts", path))
+	}
+	return sections, nil
+}
+
+// readScriptFile reads a script and expands its includes. chain holds the
+// files that include this one, to report cycles.
+func readScriptFile(path string, chain []string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", breverrors.WrapAndTrace(err)
+	}
+	for _, including := range chain {
+		if including == abs {
+			return "", breverrors.NewValidationError(fmt.Sprintf("%s cycle: %s -> %s", includeDirective, strings.Join(chain, " -> "), abs))
+		}
+	}
+	if len(chain) >= maxIncludeDepth {
+		return "", breverrors.NewValidationError(fmt.Sprintf("%s nested more than %d deep at %s", includeDirective, maxIncludeDepth, path))
+	}
+	content, err := os.ReadFile(path) //nolint:gosec // user-provided script path
+	if err != nil {
+		return "", breverrors.WrapAndTrace(err)
+	}
+	return expandIncludes(string(content), filepath.Dir(path), append(chain, abs))
+}
+
+// expandIncludes replaces '# brev:include path' lines with the included file
+func expandIncludes(content, baseDir string, chain []string) (string, error) {
+	if !strings.Contains(content, includeDirective) {
+		return content, nil
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		match := includeRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		path := strings.Trim(match[1], `"'`)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		included, err := readScriptFile(path, chain)
+		if err != nil {
+			return "", err
+		}
+		lines[i] = fmt.Sprintf("# --- begin include %s ---\n%s\n# --- end include %s ---", path, strings.TrimSuffix(included, "\n"), path)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// joinScriptSections concatenates scripts with markers, keeping the first
+// script's shebang on the first line
+func joinScriptSections(sections []scriptSection) string {
+	var b strings.Builder
+	first := sections[0].content
+	if strings.HasPrefix(first, "#!") {
+		shebang, rest, _ := strings.Cut(first, "\n")
+		b.WriteString(shebang + "\n")
+		sections[0].content = rest
+	}
+	for i, section := range sections {
+		fmt.Fprintf(&b, "# ===== brev startup script %d/%d: %s =====\n", i+1, len(sections), section.source)
+		b.WriteString(strings.TrimSuffix(section.content, "\n"))
+		fmt.Fprintf(&b, "\n# ===== end of %s =====\n", section.source)
+	}
+	return b.String()
+}
+
+// ParseScriptVars parses --var KEY=VALUE flags
+func ParseScriptVars(values []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, value := range values {
+		key, val, found := strings.Cut(value, "=")
+		if !found || !scriptVarRe.MatchString(key) {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --var %q: expected KEY=VALUE with KEY made of letters, digits and '_'", value))
+		}
+		vars[key] = val
+	}
+	return vars, nil
+}
+
+// substituteScriptVars replaces ${KEY} for each var in one pass, so values are
+// not substituted again. Other ${...} are left to the shell. A var the script
+// does not reference is an error, as it is most likely a typo.
+func substituteScriptVars(script string, vars map[string]string) (string, error) {
+	if len(vars) == 0 {
+		return script, nil
+	}
+	used := map[string]bool{}
+	script = scriptRefRe.ReplaceAllStringFunc(script, func(ref string) string {
+		key := ref[2 : len(ref)-1]
+		value, ok := vars[key]
+		if !ok {
+			return ref
+		}
+		used[key] = true
+		return value
+	})
+	var unused []string
+	for key := range vars {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", breverrors.NewValidationError(fmt.Sprintf("--var %s: no ${...} reference in the startup script", strings.Join(unused, ", ")))
+	}
+	return script, nil
+}
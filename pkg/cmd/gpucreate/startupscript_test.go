@@ -0,0 +1,124 @@
+package gpucreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestRenderStartupScriptSingle(t *testing.T) {
+	script, err := RenderStartupScript(nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, script)
+
+	script, err = RenderStartupScript([]string{"pip install torch"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "pip install torch", script)
+
+	dir := t.TempDir()
+	writeScript(t, filepath.Join(dir, "setup.sh"), "#!/bin/bash\necho hi\n")
+	script, err = RenderStartupScript([]string{"@" + filepath.Join(dir, "setup.sh")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/bash\necho hi\n", script)
+
+	_, err = RenderStartupScript([]string{"@" + filepath.Join(dir, "missing.sh")}, nil)
+	assert.Error(t, err)
+}
+
+func TestRenderStartupScriptComposed(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, filepath.Join(dir, "common.d", "20-python.sh"), "pip install uv\n")
+	writeScript(t, filepath.Join(dir, "common.d", "10-apt.sh"), "#!/bin/bash\napt-get update\n")
+	writeScript(t, filepath.Join(dir, "common.d", ".hidden"), "rm -rf /\n")
+	writeScript(t, filepath.Join(dir, "train.sh"), "# brev:include lib/env.sh\npython train.py --model ${MODEL}\n")
+	writeScript(t, filepath.Join(dir, "lib", "env.sh"), "export HF_HOME=/data/hf\n")
+
+	script, err := RenderStartupScript([]string{
+		"@" + filepath.Join(dir, "common.d"),
+		"@" + filepath.Join(dir, "train.sh"),
+		"echo done ${HOME}",
+	}, map[string]string{"MODEL": "llama3"})
+	require.NoError(t, err)
+
+	want := "#!/bin/bash\n" +
+		"# ===== brev startup script 1/4: " + filepath.Join(dir, "common.d", "10-apt.sh") + " =====\n" +
+		"apt-get update\n" +
+		"# ===== end of " + filepath.Join(dir, "common.d", "10-apt.sh") + " =====\n" +
+		"# ===== brev startup script 2/4: " + filepath.Join(dir, "common.d", "20-python.sh") + " =====\n" +
+		"pip install uv\n" +
+		"# ===== end of " + filepath.Join(dir, "common.d", "20-python.sh") + " =====\n" +
+		"# ===== brev startup script 3/4: " + filepath.Join(dir, "train.sh") + " =====\n" +
+		"# --- begin include " + filepath.Join(dir, "lib", "env.sh") + " ---\n" +
+		"export HF_HOME=/data/hf\n" +
+		"# --- end include " + filepath.Join(dir, "lib", "env.sh") + " ---\n" +
+		"python train.py --model llama3\n" +
+		"# ===== end of " + filepath.Join(dir, "train.sh") + " =====\n" +
+		"# ===== brev startup script 4/4: inline script =====\n" +
+		"echo done ${HOME}\n" +
+		"# ===== end of inline script =====\n"
+	assert.Equal(t, want, script)
+}
+
+func TestRenderStartupScriptIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, filepath.Join(dir, "a.sh"), "# brev:include b.sh\n")
+	writeScript(t, filepath.Join(dir, "b.sh"), "#brev:include \"a.sh\"\n")
+	_, err := RenderStartupScript([]string{"@" + filepath.Join(dir, "a.sh")}, nil)
+	assert.ErrorContains(t, err, "brev:include cycle")
+
+	writeScript(t, filepath.Join(dir, "c.sh"), "# brev:include missing.sh\n")
+	_, err = RenderStartupScript([]string{"@" + filepath.Join(dir, "c.sh")}, nil)
+	assert.Error(t, err)
+
+	// C includes in a heredoc are not directives
+	heredoc := "cat > a.c <<EOF\n#include <stdio.h>\n#include \"util.h\"\nEOF"
+	script, err := RenderStartupScript([]string{heredoc}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, heredoc, script)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "empty"), 0o755))
+	_, err = RenderStartupScript([]string{"@" + filepath.Join(dir, "empty")}, nil)
+	assert.ErrorContains(t, err, "no scripts")
+}
+
+func TestScriptVars(t *testing.T) {
+	vars, err := ParseScriptVars([]string{"A=1", "B=x=y", "EMPTY="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "B": "x=y", "EMPTY": ""}, vars)
+
+	for _, bad := range []string{"A", "=1", "1A=2", "A-B=1"} {
+		_, err := ParseScriptVars([]string{bad})
+		assert.Error(t, err, bad)
+	}
+
+	// values are not substituted again
+	script, err := RenderStartupScript([]string{"echo ${A} ${B} ${C}"}, map[string]string{"A": "${B}", "B": "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "echo ${B} b ${C}", script)
+
+	_, err = RenderStartupScript([]string{"echo ${A}"}, map[string]string{"A": "1", "TYPO": "2"})
+	assert.ErrorContains(t, err, "--var TYPO")
+
+	_, err = RenderStartupScript(nil, map[string]string{"A": "1"})
+	assert.ErrorContains(t, err, "--startup-script")
+}
+
+func TestCreateDryRunPrintsStartupScript(t *testing.T) {
+	cmd := NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	cmd.SetArgs([]string{"dry-run-test", "--type", "g5.xlarge", "--dry-run", "-s", "echo ${WHO}", "-s", "echo two", "--var", "WHO=me"})
+	assert.NoError(t, cmd.Execute())
+
+	cmd = NewCmdGPUCreate(terminal.New(), NewMockGPUCreateStore())
+	cmd.SetArgs([]string{"dry-run-test", "--type", "g5.xlarge", "--dry-run", "-s", "echo", "--var", "WHO=me"})
+	assert.ErrorContains(t, cmd.Execute(), "--var WHO")
+}
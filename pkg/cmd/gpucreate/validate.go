@@ -0,0 +1,437 @@
+package gpucreate
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// SkipValidationHelp describes --skip-validation
+const SkipValidationHelp = "Send the compose file or container image to the API without checking it locally"
+
+// jupyterPort is where Jupyter listens when it is installed on the instance
+const jupyterPort = 8888
+
+// reservedPorts are host ports Brev uses on every instance
+var reservedPorts = map[int]string{22: "SSH"}
+
+// composeTopLevelKeys are the top-level keys of the compose specification; x- extensions are also allowed
+var composeTopLevelKeys = map[string]bool{
+	"version": true, "name": true, "services": true, "networks": true,
+	"volumes": true, "configs": true, "secrets": true, "include": true,
+}
+
+var (
+	serviceNameRe = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	// interpolationRe matches $$ (an escaped $) and ${...}
+	interpolationRe = regexp.MustCompile(`\$\$|\$\{([^}]*)\}`)
+	// variableRe splits the inside of ${...} into the name and an optional modifier
+	variableRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-+?])(.*))?$`)
+)
+
+// ValidateBuild checks the compose file or container image of opts before
+// anything is sent to the API. Launchables are not checked.
+func ValidateBuild(opts GPUCreateOptions) error {
+	if opts.LaunchableID != "" {
+		return nil
+	}
+	switch opts.Mode {
+	case "container":
+		return ValidateImageReference(opts.ContainerImage)
+	case "compose":
+		data, source, err := readComposeForValidation(opts)
+		if err != nil || data == nil {
+			return err
+		}
+		var env map[string]string
+		if opts.Spec != nil {
+			env = opts.Spec.Env
+		}
+		jupyter := opts.JupyterSet && opts.Jupyter
+		return ValidateCompose(source, data, env, jupyter)
+	}
+	return nil
+}
+
+// composeFetcher downloads a compose file given by URL; a var so tests can stub it
+var composeFetcher = fetchComposeFile
+
+// readComposeForValidation returns the compose YAML that will be sent and a
+// name for it in messages. A compose URL that cannot be fetched from here is
+// not checked, since the API may still reach it.
+func readComposeForValidation(opts GPUCreateOptions) ([]byte, string, error) {
+	switch {
+	case opts.ComposeFile == "":
+		if opts.Spec == nil {
+			return nil, "", nil
+		}
+		return []byte(opts.Spec.ComposeYAML), "compose-yaml", nil
+	case isURL(opts.ComposeFile):
+		data, err := composeFetcher(opts.ComposeFile)
+		if err != nil {
+			return nil, "", nil //nolint:nilerr // unreachable URLs are left to the API
+		}
+		return data, opts.ComposeFile, nil
+	default:
+		data, err := os.ReadFile(opts.ComposeFile)
+		if err != nil {
+			return nil, "", breverrors.WrapAndTrace(fmt.Errorf("could not read compose file %s: %w", opts.ComposeFile, err))
+		}
+		return data, opts.ComposeFile, nil
+	}
+}
+
+func fetchComposeFile(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url) //nolint:noctx // short-lived CLI request
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	return body, nil
+}
+
+// composeProblems collects the problems found in a compose file, by line
+type composeProblems []string
+
+func (p *composeProblems) add(node *yaml.Node, format string, a ...interface{}) {
+	line := 0
+	if node != nil {
+		line = node.Line
+	}
+	*p = append(*p, fmt.Sprintf("line %d: %s", line, fmt.Sprintf(format, a...)))
+}
+
+// ValidateCompose checks the shape of a compose file, its service names, that
+// no published port collides with a port Brev uses (or with another service),
+// and that every required ${VAR} is set in env. jupyter reserves the Jupyter port.
+func ValidateCompose(source string, data []byte, env map[string]string, jupyter bool) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return composeError(source, []string{err.Error()})
+	}
+	var problems composeProblems
+	if len(doc.Content) == 0 {
+		return composeError(source, []string{"the file is empty; it needs a services section"})
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		problems.add(root, "the top level must be a mapping with a services section")
+		return composeError(source, problems)
+	}
+
+	reserved := map[int]string{}
+	for port, use := range reservedPorts {
+		reserved[port] = use
+	}
+	if jupyter {
+		reserved[jupyterPort] = "Jupyter"
+	}
+
+	var services *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch {
+		case key.Value == "services":
+			services = value
+		case !composeTopLevelKeys[key.Value] && !strings.HasPrefix(key.Value, "x-"):
+			problems.add(key, "unknown top-level key %q; expected services, networks, volumes, configs, secrets or an x- extension", key.Value)
+		}
+	}
+	switch {
+	case services == nil:
+		problems.add(root, "no services section")
+	case services.Kind != yaml.MappingNode || len(services.Content) == 0:
+		problems.add(services, "services must map service names to their configuration")
+	default:
+		validateServices(services, reserved, &problems)
+	}
+
+	validateInterpolation(data, env, &problems)
+	if len(problems) > 0 {
+		return composeError(source, problems)
+	}
+	return nil
+}
+
+func validateServices(services *yaml.Node, reserved map[int]string, problems *composeProblems) {
+	published := map[string]string{} // port/protocol -> service that publishes it
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		key, service := services.Content[i], services.Content[i+1]
+		name := key.Value
+		if !serviceNameRe.MatchString(name) {
+			problems.add(key, "invalid service name %q; use letters, digits, '.', '_' and '-'", name)
+		}
+		service = resolveAlias(service)
+		if service.Kind != yaml.MappingNode {
+			problems.add(service, "service %q must be a mapping", name)
+			continue
+		}
+		hasImage := false
+		for _, field := range mappingFields(service) {
+			switch field.key.Value {
+			case "image", "build", "extends":
+				// extends takes the image or build from another service
+				hasImage = true
+			case "ports":
+				validatePorts(name, resolveAlias(field.value), reserved, published, problems)
+			}
+		}
+		if !hasImage {
+			problems.add(key, "service %q needs an image, a build section or extends", name)
+		}
+	}
+}
+
+// resolveAlias returns the node an alias (*name) refers to
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// mappingField is a key and its value in a mapping
+type mappingField struct {
+	key, value *yaml.Node
+}
+
+// mappingFields returns the fields of a mapping with merge keys (<<: *common)
+// expanded. Fields set in the mapping itself override merged ones.
+func mappingFields(mapping *yaml.Node) []mappingField {
+	var fields, merged []mappingField
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if key.Tag != "!!merge" && key.Value != "<<" {
+			fields = append(fields, mappingField{key: key, value: value})
+			seen[key.Value] = true
+			continue
+		}
+		// the value is a mapping or a list of them, usually aliases
+		value = resolveAlias(value)
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, source := range sources {
+			if source = resolveAlias(source); source.Kind == yaml.MappingNode {
+				merged = append(merged, mappingFields(source)...)
+			}
+		}
+	}
+	for _, field := range merged {
+		if !seen[field.key.Value] {
+			fields = append(fields, field)
+			seen[field.key.Value] = true
+		}
+	}
+	return fields
+}
+
+func validatePorts(service string, ports *yaml.Node, reserved map[int]string, published map[string]string, problems *composeProblems) {
+	if ports.Kind != yaml.SequenceNode {
+		problems.add(ports, "service %q: ports must be a list", service)
+		return
+	}
+	for _, entry := range ports.Content {
+		hostPorts, protocol, err := publishedPorts(resolveAlias(entry))
+		if err != nil {
+			problems.add(entry, "service %q: %v", service, err)
+			continue
+		}
+		for _, port := range hostPorts {
+			if use, ok := reserved[port]; ok {
+				problems.add(entry, "service %q publishes port %d, which Brev uses for %s; publish another host port, e.g. \"%d:<container port>\"", service, port, use, port+10000)
+				continue
+			}
+			key := fmt.Sprintf("%d/%s", port, protocol)
+			if other, ok := published[key]; ok {
+				problems.add(entry, "service %q publishes port %d, which service %q already publishes", service, port, other)
+				continue
+			}
+			published[key] = service
+		}
+	}
+}
+
+// publishedPorts returns the host ports a ports entry publishes. Entries that
+// only name a container port publish a random host port and return none, as
+// do entries with ${VAR}s, which are only known once interpolated.
+func publishedPorts(entry *yaml.Node) ([]int, string, error) {
+	protocol := "tcp"
+	var hostPorts string
+	switch entry.Kind {
+	case yaml.ScalarNode:
+		value := entry.Value
+		if strings.Contains(value, "${") {
+			return nil, protocol, nil
+		}
+		if i := strings.LastIndex(value, "/"); i >= 0 {
+			protocol = value[i+1:]
+			value = value[:i]
+		}
+		parts := strings.Split(value, ":")
+		if len(parts) < 2 {
+			return nil, protocol, nil
+		}
+		hostPorts = parts[len(parts)-2]
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(entry.Content); i += 2 {
+			switch entry.Content[i].Value {
+			case "published":
+				hostPorts = entry.Content[i+1].Value
+			case "protocol":
+				protocol = entry.Content[i+1].Value
+			}
+		}
+	default:
+		return nil, protocol, fmt.Errorf("invalid ports entry")
+	}
+	if hostPorts == "" || strings.Contains(hostPorts, "${") {
+		return nil, protocol, nil
+	}
+	ports, err := expandPortRange(hostPorts)
+	return ports, protocol, err
+}
+
+func expandPortRange(value string) ([]int, error) {
+	low, high, isRange := strings.Cut(value, "-")
+	start, err := parsePort(low)
+	if err != nil {
+		return nil, err
+	}
+	end := start
+	if isRange {
+		if end, err = parsePort(high); err != nil {
+			return nil, err
+		}
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid port range %q", value)
+	}
+	ports := make([]int, 0, end-start+1)
+	for port := start; port <= end; port++ {
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", value)
+	}
+	return port, nil
+}
+
+// validateInterpolation checks the ${VAR} references. Variables with a default
+// (${VAR:-x}, ${VAR-x}) or an alternative (${VAR:+x}) need not be set.
+func validateInterpolation(data []byte, env map[string]string, problems *composeProblems) {
+	missing := map[string][]int{}
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, match := range interpolationRe.FindAllStringSubmatch(line, -1) {
+			if match[0] == "$$" {
+				continue
+			}
+			parts := variableRe.FindStringSubmatch(match[1])
+			if parts == nil {
+				problems.add(&yaml.Node{Line: i + 1}, "invalid variable reference %q", match[0])
+				continue
+			}
+			name, modifier := parts[1], strings.TrimPrefix(parts[2], ":")
+			if modifier == "-" || modifier == "+" {
+				continue
+			}
+			if _, ok := env[name]; !ok {
+				missing[name] = append(missing[name], i+1)
+			}
+		}
+	}
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		problems.add(&yaml.Node{Line: missing[name][0]}, "${%s} is not set; add it under env in the --from-file spec, or give a default with ${%s:-value}", name, name)
+	}
+}
+
+func composeError(source string, problems []string) error {
+	return breverrors.NewValidationError(fmt.Sprintf("compose file %s is invalid:\n  %s\n(use --skip-validation to send it anyway)", source, strings.Join(problems, "\n  ")))
+}
+
+var (
+	// the grammar of github.com/distribution/reference
+	imageComponent = `[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*`
+	imageDomain    = `(?:localhost|(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?|\[[a-fA-F0-9:]+\](?::[0-9]+)?)`
+	imageTag       = `[\w][\w.-]{0,127}`
+	imageDigest    = `[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}`
+	imageRefRe     = regexp.MustCompile(`^(?:(` + imageDomain + `)/)?(` + imageComponent + `(?:/` + imageComponent + `)*)(?::(` + imageTag + `))?(?:@(` + imageDigest + `))?$`)
+	imageTagRe     = regexp.MustCompile(`^` + imageTag + `$`)
+	imageDigestRe  = regexp.MustCompile(`^` + imageDigest + `$`)
+)
+
+// ValidateImageReference checks the syntax of a container image reference, e.g.
+// nvcr.io/nvidia/pytorch:24.01-py3
+func ValidateImageReference(ref string) error {
+	reason := ""
+	switch {
+	case ref == "":
+		reason = "it is empty"
+	case strings.ContainsAny(ref, " \t\n"):
+		reason = "it contains whitespace"
+	case isURL(ref) || strings.Contains(ref, "://"):
+		reason = "drop the URL scheme, e.g. registry.example.com/team/image:tag"
+	case !imageRefRe.MatchString(ref):
+		reason = imageReferenceHint(ref)
+	default:
+		return nil
+	}
+	return breverrors.NewValidationError(fmt.Sprintf("invalid container image %q: %s (use --skip-validation to send it anyway)", ref, reason))
+}
+
+func imageReferenceHint(ref string) string {
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		if !imageDigestRe.MatchString(name[i+1:]) {
+			return "the digest must look like sha256:<64 hex characters>"
+		}
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		if !imageTagRe.MatchString(name[i+1:]) {
+			return "the tag may only contain letters, digits, '_', '.' and '-', up to 128 characters"
+		}
+		name = name[:i]
+	}
+	// the registry may have upper case letters, the repository may not
+	repository := name
+	if registry, rest, found := strings.Cut(name, "/"); found && (strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		repository = rest
+	}
+	if repository != strings.ToLower(repository) {
+		return "repository names must be lowercase"
+	}
+	return "expected [registry/]repository[:tag][@digest]"
+}
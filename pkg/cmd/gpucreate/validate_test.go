@@ -0,0 +1,177 @@
+package gpucreate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateComposeValid(t *testing.T) {
+	compose := `
+services:
+  web:
+    image: nginx:${NGINX_TAG:-latest}
+    ports:
+      - "8080:80"
+      - 9000
+      - target: 443
+        published: "8443"
+    environment:
+      TOKEN: ${API_TOKEN}
+      LITERAL: $${NOT_A_VAR}
+  worker:
+    build: .
+    ports:
+      - "127.0.0.1:8081:81/udp"
+x-common: {}
+volumes:
+  data: {}
+`
+	assert.NoError(t, ValidateCompose("compose.yaml", []byte(compose), map[string]string{"API_TOKEN": "x"}, false))
+}
+
+func TestValidateComposeProblems(t *testing.T) {
+	compose := `services:
+  web:
+    image: nginx
+    ports:
+      - "22:22"
+      - "8000-8001:8000-8001"
+  "bad name":
+    image: redis
+  api:
+    ports:
+      - "8001:80"
+      - "8888:8888"
+    environment:
+      TOKEN: ${API_TOKEN}
+      URL: ${BASE_URL:?required}
+      OTHER: ${1BAD}
+service: {}
+`
+	err := ValidateCompose("compose.yaml", []byte(compose), nil, true)
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, "compose file compose.yaml is invalid")
+	assert.Contains(t, msg, `line 5: service "web" publishes port 22, which Brev uses for SSH`)
+	assert.Contains(t, msg, `line 7: invalid service name "bad name"`)
+	assert.Contains(t, msg, `line 9: service "api" needs an image`)
+	assert.Contains(t, msg, `line 11: service "api" publishes port 8001, which service "web" already publishes`)
+	assert.Contains(t, msg, `line 12: service "api" publishes port 8888, which Brev uses for Jupyter`)
+	assert.Contains(t, msg, `line 14: ${API_TOKEN} is not set`)
+	assert.Contains(t, msg, `line 15: ${BASE_URL} is not set`)
+	assert.Contains(t, msg, `line 16: invalid variable reference "${1BAD}"`)
+	assert.Contains(t, msg, `line 17: unknown top-level key "service"`)
+	assert.Contains(t, msg, "--skip-validation")
+
+	// without Jupyter 8888 is free
+	err = ValidateCompose("c", []byte("services:\n  a:\n    image: x\n    ports: [\"8888:8888\"]\n"), nil, false)
+	assert.NoError(t, err)
+}
+
+func TestValidateComposeInterpolatedPortsMergeKeysAndExtends(t *testing.T) {
+	compose := `
+x-common: &common
+  image: nvcr.io/nvidia/pytorch:24.01-py3
+  restart: always
+x-ports: &ports
+  ports:
+    - "8001:80"
+services:
+  web:
+    image: nginx
+    ports:
+      - "${HOST_PORT:-8080}:80"
+      - target: 443
+        published: "${HTTPS_PORT:-8443}"
+  trainer:
+    <<: *common
+    command: python train.py
+  both:
+    <<: [*common, *ports]
+  worker:
+    extends:
+      service: trainer
+`
+	assert.NoError(t, ValidateCompose("compose.yaml", []byte(compose), nil, false))
+
+	// merged fields are checked too, and set fields override them
+	compose = `
+x-ssh: &ssh
+  image: x
+  ports: ["22:22"]
+services:
+  a:
+    <<: *ssh
+  b:
+    <<: *ssh
+    ports: ["2222:22"]
+`
+	err := ValidateCompose("compose.yaml", []byte(compose), nil, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `service "a" publishes port 22`)
+	assert.NotContains(t, err.Error(), `service "b"`)
+}
+
+func TestValidateComposeShape(t *testing.T) {
+	for _, compose := range []string{"", "- a\n- b\n", "version: '3'\n", "services: []\n", "services: {a: [1]}\n", "services: {a: {image: x, ports: \"80\"}}\n", "services: {a: {image: x, ports: [\"0:80\"]}}\n", "a: [\n"} {
+		assert.Error(t, ValidateCompose("c", []byte(compose), nil, false), compose)
+	}
+}
+
+func TestValidateImageReference(t *testing.T) {
+	valid := []string{
+		"ubuntu",
+		"ubuntu:22.04",
+		"nvcr.io/nvidia/pytorch:24.01-py3",
+		"localhost:5000/team/image",
+		"Registry.Example.com/team/image:v1",
+		"ghcr.io/org/img@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}
+	for _, ref := range valid {
+		assert.NoError(t, ValidateImageReference(ref), ref)
+	}
+
+	invalid := map[string]string{
+		"":                            "empty",
+		"nvcr.io/nvidia/pytorch 24":   "whitespace",
+		"https://nvcr.io/nvidia/img":  "scheme",
+		"nvcr.io/NVIDIA/pytorch:24":   "lowercase",
+		"ubuntu:bad tag":              "whitespace",
+		"ubuntu:-bad":                 "tag",
+		"ubuntu@sha256:abc":           "digest",
+		"team//image":                 "expected",
+		"nvcr.io/nvidia/pytorch:24:1": "tag",
+	}
+	for ref, want := range invalid {
+		err := ValidateImageReference(ref)
+		require.Error(t, err, ref)
+		assert.Contains(t, err.Error(), want, ref)
+	}
+}
+
+func TestValidateBuild(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "compose.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("services:\n  a:\n    image: x\n    ports: [\"22:22\"]\n"), 0o600))
+
+	assert.ErrorContains(t, ValidateBuild(GPUCreateOptions{Mode: "compose", ComposeFile: path}), "SSH")
+	assert.ErrorContains(t, ValidateBuild(GPUCreateOptions{Mode: "compose", ComposeFile: filepath.Join(dir, "missing.yaml")}), "could not read")
+	assert.ErrorContains(t, ValidateBuild(GPUCreateOptions{Mode: "container", ContainerImage: "Bad/Image"}), "lowercase")
+	assert.NoError(t, ValidateBuild(GPUCreateOptions{Mode: "vm"}))
+	assert.NoError(t, ValidateBuild(GPUCreateOptions{Mode: "container", ContainerImage: "Bad/Image", LaunchableID: "env-123"}))
+
+	spec := &CreateSpec{ComposeYAML: "services:\n  a:\n    image: x:${TAG}\n", Env: map[string]string{"TAG": "1"}}
+	assert.NoError(t, ValidateBuild(GPUCreateOptions{Mode: "compose", Spec: spec}))
+
+	orig := composeFetcher
+	defer func() { composeFetcher = orig }()
+	composeFetcher = func(string) ([]byte, error) { return []byte("services: {}\n"), nil }
+	assert.ErrorContains(t, ValidateBuild(GPUCreateOptions{Mode: "compose", ComposeFile: "https://example.com/c.yaml"}), "https://example.com/c.yaml")
+	composeFetcher = func(string) ([]byte, error) { return nil, errors.New("offline") }
+	assert.NoError(t, ValidateBuild(GPUCreateOptions{Mode: "compose", ComposeFile: "https://example.com/c.yaml"}))
+}
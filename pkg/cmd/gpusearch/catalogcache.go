@@ -0,0 +1,75 @@
+package gpusearch
+
+import (
+	"time"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// DefaultCatalogCacheTTL is how long cached instance-type listings are served
+// before being refetched, unless overridden by --cache-ttl or personal settings
+const DefaultCatalogCacheTTL = 15 * time.Minute
+
+// CatalogCacheMode selects how instance-type listings use the on-disk cache
+type CatalogCacheMode int
+
+const (
+	// CatalogCacheDefault serves fresh cache entries, otherwise fetches and falls back to a stale entry on error
+	CatalogCacheDefault CatalogCacheMode = iota
+	// CatalogCacheRefresh always fetches and then updates the cache
+	CatalogCacheRefresh
+	// CatalogCacheOffline only reads from the cache and never touches the network
+	CatalogCacheOffline
+)
+
+// CatalogCachePolicy controls caching of GetInstanceTypes and GetAllInstanceTypesWithWorkspaceGroups.
+// A zero TTL means "use the configured default".
+type CatalogCachePolicy struct {
+	Mode CatalogCacheMode
+	TTL  time.Duration
+}
+
+// CatalogCacheConfigurer is implemented by stores that cache instance-type listings on disk
+type CatalogCacheConfigurer interface {
+	SetCatalogCachePolicy(policy CatalogCachePolicy)
+}
+
+// ApplyCatalogCachePolicy sets the policy on s if it supports catalog caching
+func ApplyCatalogCachePolicy(s interface{}, policy CatalogCachePolicy) {
+	if c, ok := s.(CatalogCacheConfigurer); ok {
+		c.SetCatalogCachePolicy(policy)
+	}
+}
+
+// CatalogCacheFlags holds the --refresh, --offline and --cache-ttl flag values
+type CatalogCacheFlags struct {
+	Refresh bool
+	Offline bool
+	TTL     time.Duration
+}
+
+// AddCatalogCacheFlags registers the catalog cache flags on a command
+func AddCatalogCacheFlags(cmd *cobra.Command, f *CatalogCacheFlags) {
+	cmd.Flags().BoolVar(&f.Refresh, "refresh", false, "Ignore the cached instance-type catalog and refetch it")
+	cmd.Flags().BoolVar(&f.Offline, "offline", false, "Only use the cached instance-type catalog (no network)")
+	cmd.Flags().DurationVar(&f.TTL, "cache-ttl", 0, "How long the cached instance-type catalog stays fresh (default 15m, or catalog_cache_ttl in personal settings)")
+}
+
+// Policy validates the flags and converts them to a CatalogCachePolicy
+func (f CatalogCacheFlags) Policy() (CatalogCachePolicy, error) {
+	if f.Refresh && f.Offline {
+		return CatalogCachePolicy{}, breverrors.NewValidationError("--refresh and --offline cannot be used together")
+	}
+	if f.TTL < 0 {
+		return CatalogCachePolicy{}, breverrors.NewValidationError("--cache-ttl must not be negative")
+	}
+	policy := CatalogCachePolicy{Mode: CatalogCacheDefault, TTL: f.TTL}
+	switch {
+	case f.Refresh:
+		policy.Mode = CatalogCacheRefresh
+	case f.Offline:
+		policy.Mode = CatalogCacheOffline
+	}
+	return policy, nil
+}
@@ -0,0 +1,412 @@
+package gpusearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+const catalogSnapshotFileName = "catalog_snapshot.json"
+
+var (
+	diffLong = `Compare instance-type catalog snapshots.
+
+Reports instance types that were added or removed, price changes, and changes
+in estimated boot time or disk size limits.
+
+With no arguments, the live catalog is compared against the snapshot saved by
+the previous run (~/.brev/catalog_snapshot.json). With one argument, the live
+catalog is compared against that snapshot file. With two arguments, the two
+snapshot files are compared without touching the network.
+
+Every live fetch is saved as the new baseline unless --no-save is set. Use
+--save to also write the live snapshot to a file of your choice. Snapshot files
+are the instance types API response ({"items": [...]}) with an optional
+"taken_at" timestamp.`
+
+	diffExample = `
+  # Record a baseline, then later see what changed since then
+  brev search diff
+
+  # Archive today's catalog and compare against last week's archive
+  brev search diff catalog-last-week.json --save catalog-today.json
+
+  # Compare two snapshot files offline
+  brev search diff old.json new.json
+
+  # Machine-readable output
+  brev search diff --json
+`
+)
+
+// CatalogSnapshot is an instance-type catalog captured at a point in time.
+// It is a superset of InstanceTypesResponse, so raw API responses can be diffed too.
+type CatalogSnapshot struct {
+	TakenAt *time.Time     `json:"taken_at,omitempty"`
+	Items   []InstanceType `json:"items"`
+}
+
+// CatalogSnapshotStore is implemented by stores that know the brev home directory
+type CatalogSnapshotStore interface {
+	GetBrevHomePath() (string, error)
+}
+
+// CatalogDiffEntry describes an instance type that was added or removed
+type CatalogDiffEntry struct {
+	Type         string  `json:"type"`
+	Provider     string  `json:"provider"`
+	GPU          string  `json:"gpu"`
+	PricePerHour float64 `json:"price_per_hour"`
+}
+
+// CatalogFieldChange describes a single changed field of an instance type
+type CatalogFieldChange struct {
+	Field string  `json:"field"`
+	Old   float64 `json:"old"`
+	New   float64 `json:"new"`
+	Delta float64 `json:"delta"`
+}
+
+// CatalogTypeChange lists the changed fields of an instance type present in both snapshots
+type CatalogTypeChange struct {
+	Type     string               `json:"type"`
+	Provider string               `json:"provider"`
+	GPU      string               `json:"gpu"`
+	Changes  []CatalogFieldChange `json:"changes"`
+}
+
+// CatalogDiff is the result of comparing two catalog snapshots
+type CatalogDiff struct {
+	OldTakenAt *time.Time          `json:"old_taken_at,omitempty"`
+	NewTakenAt *time.Time          `json:"new_taken_at,omitempty"`
+	Added      []CatalogDiffEntry  `json:"added"`
+	Removed    []CatalogDiffEntry  `json:"removed"`
+	Changed    []CatalogTypeChange `json:"changed"`
+}
+
+// IsEmpty returns true if the snapshots are equivalent
+func (d *CatalogDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff field names, also used as JSON values
+const (
+	diffFieldPrice    = "price_per_hour"
+	diffFieldBootTime = "boot_time_seconds"
+	diffFieldDiskMin  = "disk_min_gb"
+	diffFieldDiskMax  = "disk_max_gb"
+)
+
+// catalogDiffFields is the order in which changed fields are reported
+var catalogDiffFields = []string{diffFieldPrice, diffFieldBootTime, diffFieldDiskMin, diffFieldDiskMax}
+
+// catalogTypeSummary holds the comparable, parsed fields of an instance type
+type catalogTypeSummary struct {
+	entry  CatalogDiffEntry
+	fields map[string]float64
+}
+
+// summarizeInstanceType parses the fields tracked by the diff
+func summarizeInstanceType(item InstanceType) catalogTypeSummary {
+	price := 0.0
+	if item.BasePrice.Amount != "" {
+		price, _ = strconv.ParseFloat(item.BasePrice.Amount, 64)
+	}
+	diskMin, diskMax, _ := extractDiskInfo(item.SupportedStorage)
+
+	gpu := "-"
+	if len(item.SupportedGPUs) > 0 {
+		g := item.SupportedGPUs[0]
+		gpu = fmt.Sprintf("%dx %s", g.Count, g.Name)
+	}
+
+	return catalogTypeSummary{
+		entry: CatalogDiffEntry{
+			Type:         item.Type,
+			Provider:     item.Provider,
+			GPU:          gpu,
+			PricePerHour: price,
+		},
+		fields: map[string]float64{
+			diffFieldPrice:    price,
+			diffFieldBootTime: float64(parseDurationToSeconds(item.EstimatedDeployTime)),
+			diffFieldDiskMin:  diskMin,
+			diffFieldDiskMax:  diskMax,
+		},
+	}
+}
+
+// summarizeCatalog indexes a catalog by instance type; the first entry of a duplicated type wins
+func summarizeCatalog(items []InstanceType) map[string]catalogTypeSummary {
+	byType := make(map[string]catalogTypeSummary, len(items))
+	for _, item := range items {
+		if _, ok := byType[item.Type]; ok {
+			continue
+		}
+		byType[item.Type] = summarizeInstanceType(item)
+	}
+	return byType
+}
+
+// DiffCatalogs compares two catalog snapshots
+func DiffCatalogs(oldSnap, newSnap *CatalogSnapshot) *CatalogDiff {
+	oldTypes := summarizeCatalog(oldSnap.Items)
+	newTypes := summarizeCatalog(newSnap.Items)
+
+	diff := &CatalogDiff{
+		OldTakenAt: oldSnap.TakenAt,
+		NewTakenAt: newSnap.TakenAt,
+		Added:      []CatalogDiffEntry{},
+		Removed:    []CatalogDiffEntry{},
+		Changed:    []CatalogTypeChange{},
+	}
+
+	for name, newSummary := range newTypes {
+		oldSummary, ok := oldTypes[name]
+		if !ok {
+			diff.Added = append(diff.Added, newSummary.entry)
+			continue
+		}
+		var changes []CatalogFieldChange
+		for _, field := range catalogDiffFields {
+			oldVal, newVal := oldSummary.fields[field], newSummary.fields[field]
+			if oldVal != newVal {
+				changes = append(changes, CatalogFieldChange{Field: field, Old: oldVal, New: newVal, Delta: newVal - oldVal})
+			}
+		}
+		if len(changes) > 0 {
+			diff.Changed = append(diff.Changed, CatalogTypeChange{
+				Type:     name,
+				Provider: newSummary.entry.Provider,
+				GPU:      newSummary.entry.GPU,
+				Changes:  changes,
+			})
+		}
+	}
+	for name, oldSummary := range oldTypes {
+		if _, ok := newTypes[name]; !ok {
+			diff.Removed = append(diff.Removed, oldSummary.entry)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Type < diff.Added[j].Type })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Type < diff.Removed[j].Type })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Type < diff.Changed[j].Type })
+	return diff
+}
+
+// ReadCatalogSnapshot reads a snapshot (or a raw instance types API response) from a file
+func ReadCatalogSnapshot(path string) (*CatalogSnapshot, error) {
+	var snap CatalogSnapshot
+	if err := files.ReadJSON(files.AppFs, path, &snap); err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	return &snap, nil
+}
+
+// WriteCatalogSnapshot writes a snapshot to a file
+func WriteCatalogSnapshot(path string, snap *CatalogSnapshot) error {
+	return breverrors.WrapAndTrace(files.OverwriteJSON(files.AppFs, path, snap))
+}
+
+// newCmdDiffSubcommand creates the 'diff' subcommand
+func newCmdDiffSubcommand(t *terminal.Terminal, store GPUSearchStore) *cobra.Command {
+	var jsonOutput bool
+	var noSave bool
+	var savePath string
+
+	cmd := &cobra.Command{
+		Use:                   "diff [old-snapshot] [new-snapshot]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show added/removed instance types and price changes between catalog snapshots",
+		Long:                  diffLong,
+		Example:               diffExample,
+		Args:                  cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunCatalogDiff(t, store, args, savePath, noSave, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the diff as JSON")
+	cmd.Flags().BoolVar(&noSave, "no-save", false, "Do not update the saved baseline snapshot")
+	cmd.Flags().StringVar(&savePath, "save", "", "Also write the live catalog snapshot to this file")
+
+	return cmd
+}
+
+// RunCatalogDiff resolves the snapshots to compare, prints the diff and updates the baseline
+func RunCatalogDiff(t *terminal.Terminal, store GPUSearchStore, args []string, savePath string, noSave, jsonOutput bool) error {
+	if len(args) == 2 {
+		if savePath != "" {
+			return breverrors.NewValidationError("--save cannot be used when comparing two snapshot files")
+		}
+		oldSnap, err := ReadCatalogSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		newSnap, err := ReadCatalogSnapshot(args[1])
+		if err != nil {
+			return err
+		}
+		return displayCatalogDiff(t, DiffCatalogs(oldSnap, newSnap), jsonOutput)
+	}
+
+	baselinePath, err := defaultSnapshotPath(store)
+	if err != nil && (len(args) == 0 || !noSave) {
+		return err
+	}
+
+	oldPath := baselinePath
+	if len(args) == 1 {
+		oldPath = args[0]
+	}
+	var oldSnap *CatalogSnapshot
+	if _, statErr := os.Stat(oldPath); statErr == nil {
+		oldSnap, err = ReadCatalogSnapshot(oldPath)
+		if err != nil {
+			return err
+		}
+	} else if len(args) == 1 {
+		return breverrors.WrapAndTrace(statErr)
+	}
+
+	// Always compare against the live catalog rather than the search cache
+	ApplyCatalogCachePolicy(store, CatalogCachePolicy{Mode: CatalogCacheRefresh})
+	response, err := store.GetInstanceTypes(true)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	now := time.Now().UTC()
+	newSnap := &CatalogSnapshot{TakenAt: &now, Items: response.Items}
+
+	if savePath != "" {
+		if err := WriteCatalogSnapshot(savePath, newSnap); err != nil {
+			return err
+		}
+	}
+	if !noSave {
+		if err := WriteCatalogSnapshot(baselinePath, newSnap); err != nil {
+			return err
+		}
+	}
+
+	if oldSnap == nil {
+		if !jsonOutput {
+			t.Vprintf("No previous snapshot found. Saved the current catalog (%d instance types) to %s\n", len(newSnap.Items), baselinePath)
+			t.Vprint("Run 'brev search diff' again later to see what changed.\n")
+			return nil
+		}
+		oldSnap = &CatalogSnapshot{}
+	}
+
+	return displayCatalogDiff(t, DiffCatalogs(oldSnap, newSnap), jsonOutput)
+}
+
+// defaultSnapshotPath returns the baseline snapshot path under the brev home directory
+func defaultSnapshotPath(store GPUSearchStore) (string, error) {
+	s, ok := store.(CatalogSnapshotStore)
+	if !ok {
+		return "", breverrors.NewValidationError("no default snapshot location; pass snapshot files explicitly")
+	}
+	brevHome, err := s.GetBrevHomePath()
+	if err != nil {
+		return "", breverrors.WrapAndTrace(err)
+	}
+	return filepath.Join(brevHome, catalogSnapshotFileName), nil
+}
+
+// displayCatalogDiff renders the diff as a table or JSON
+func displayCatalogDiff(t *terminal.Terminal, diff *CatalogDiff, jsonOutput bool) error {
+	if jsonOutput {
+		output, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if diff.IsEmpty() {
+		t.Vprint(t.Green("No changes in the instance-type catalog\n"))
+		return nil
+	}
+
+	piped := IsStdoutPiped()
+	colorize := func(s string, color func(format string, a ...interface{}) string) string {
+		if piped {
+			return s
+		}
+		return color("%s", s)
+	}
+
+	ta := table.NewWriter()
+	ta.SetOutputMirror(os.Stdout)
+	ta.Style().Options = getBrevTableOptions()
+	ta.AppendHeader(table.Row{"CHANGE", "TYPE", "PROVIDER", "GPU", "FIELD", "OLD", "NEW", "DELTA"})
+
+	for _, e := range diff.Added {
+		price := formatDiffValue(diffFieldPrice, e.PricePerHour)
+		ta.AppendRow(table.Row{colorize("added", t.Green), e.Type, e.Provider, e.GPU, "$/HR", "-", price, "-"})
+	}
+	for _, e := range diff.Removed {
+		price := formatDiffValue(diffFieldPrice, e.PricePerHour)
+		ta.AppendRow(table.Row{colorize("removed", t.Red), e.Type, e.Provider, e.GPU, "$/HR", price, "-", "-"})
+	}
+	for _, c := range diff.Changed {
+		for _, fc := range c.Changes {
+			ta.AppendRow(table.Row{
+				colorize("changed", t.Yellow), c.Type, c.Provider, c.GPU,
+				diffFieldLabels[fc.Field],
+				formatDiffValue(fc.Field, fc.Old),
+				formatDiffValue(fc.Field, fc.New),
+				formatDiffDelta(fc.Field, fc.Delta),
+			})
+		}
+	}
+	ta.Render()
+
+	if !piped {
+		t.Vprintf("\n%s\n", t.Green(fmt.Sprintf("%d added, %d removed, %d changed", len(diff.Added), len(diff.Removed), len(diff.Changed))))
+	}
+	return nil
+}
+
+// diffFieldLabels maps diff fields to table labels
+var diffFieldLabels = map[string]string{
+	diffFieldPrice:    "$/HR",
+	diffFieldBootTime: "BOOT",
+	diffFieldDiskMin:  "DISK_MIN",
+	diffFieldDiskMax:  "DISK_MAX",
+}
+
+// formatDiffValue formats a diff field value for display
+func formatDiffValue(field string, value float64) string {
+	switch field {
+	case diffFieldPrice:
+		return fmt.Sprintf("$%.2f", value)
+	case diffFieldBootTime:
+		return formatBootTime(int(value))
+	default:
+		return formatDiskSize(value, value)
+	}
+}
+
+// formatDiffDelta formats a signed change
+func formatDiffDelta(field string, delta float64) string {
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+		delta = -delta
+	}
+	return sign + formatDiffValue(field, delta)
+}
@@ -0,0 +1,79 @@
+package gpusearch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffTestType(name, price, bootTime, minSize, maxSize string) InstanceType {
+	return InstanceType{
+		Type:                name,
+		Provider:            "aws",
+		SupportedGPUs:       []GPU{{Count: 1, Name: "A10G", Memory: "24GiB"}},
+		SupportedStorage:    []Storage{{MinSize: minSize, MaxSize: maxSize}},
+		BasePrice:           BasePrice{Currency: "USD", Amount: price},
+		EstimatedDeployTime: bootTime,
+	}
+}
+
+func TestDiffCatalogs(t *testing.T) {
+	oldSnap := &CatalogSnapshot{Items: []InstanceType{
+		diffTestType("g5.xlarge", "1.00", "5m0s", "100GiB", "1000GiB"),
+		diffTestType("g5.2xlarge", "1.50", "5m0s", "100GiB", "1000GiB"),
+		diffTestType("g4dn.xlarge", "0.60", "5m0s", "100GiB", "1000GiB"),
+	}}
+	newSnap := &CatalogSnapshot{Items: []InstanceType{
+		diffTestType("g5.xlarge", "0.80", "5m0s", "100GiB", "1000GiB"),
+		diffTestType("g5.2xlarge", "1.50", "7m0s", "100GiB", "2000GiB"),
+		diffTestType("p5.48xlarge", "98.00", "10m0s", "100GiB", "1000GiB"),
+	}}
+
+	diff := DiffCatalogs(oldSnap, newSnap)
+
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "p5.48xlarge", diff.Added[0].Type)
+	assert.Equal(t, 98.0, diff.Added[0].PricePerHour)
+	assert.Equal(t, "1x A10G", diff.Added[0].GPU)
+
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, "g4dn.xlarge", diff.Removed[0].Type)
+
+	require.Len(t, diff.Changed, 2)
+	assert.Equal(t, "g5.2xlarge", diff.Changed[0].Type)
+	require.Len(t, diff.Changed[0].Changes, 2)
+	assert.Equal(t, diffFieldBootTime, diff.Changed[0].Changes[0].Field)
+	assert.Equal(t, 120.0, diff.Changed[0].Changes[0].Delta)
+	assert.Equal(t, diffFieldDiskMax, diff.Changed[0].Changes[1].Field)
+
+	assert.Equal(t, "g5.xlarge", diff.Changed[1].Type)
+	require.Len(t, diff.Changed[1].Changes, 1)
+	assert.Equal(t, diffFieldPrice, diff.Changed[1].Changes[0].Field)
+	assert.InDelta(t, -0.2, diff.Changed[1].Changes[0].Delta, 0.0001)
+}
+
+func TestDiffCatalogsIdentical(t *testing.T) {
+	snap := &CatalogSnapshot{Items: []InstanceType{diffTestType("g5.xlarge", "1.00", "5m0s", "100GiB", "1000GiB")}}
+	diff := DiffCatalogs(snap, snap)
+	assert.True(t, diff.IsEmpty())
+}
+
+func TestReadCatalogSnapshotAcceptsRawResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raw.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"items":[{"type":"g5.xlarge","base_price":{"currency":"USD","amount":"1.00"}}]}`), 0o600))
+
+	snap, err := ReadCatalogSnapshot(path)
+	require.NoError(t, err)
+	assert.Nil(t, snap.TakenAt)
+	require.Len(t, snap.Items, 1)
+	assert.Equal(t, "g5.xlarge", snap.Items[0].Type)
+}
+
+func TestFormatDiffDelta(t *testing.T) {
+	assert.Equal(t, "-$0.20", formatDiffDelta(diffFieldPrice, -0.2))
+	assert.Equal(t, "+2m", formatDiffDelta(diffFieldBootTime, 120))
+	assert.Equal(t, "+1TB", formatDiffDelta(diffFieldDiskMax, 1000))
+}
@@ -118,11 +118,45 @@ var (
 
 Use 'brev search gpu' (default) to find GPU instances.
 Use 'brev search cpu' to find CPU-only instances.
+Use 'brev search diff' to see what changed in the catalog since a snapshot.
+Use --save-preset <name> to save the filter and sort flags of a search and
+--preset <name> to reuse them here or with 'brev create' ('brev search presets'
+lists and removes them). Flags given on the command line override the preset.
+Use --pick to choose types from the results with the arrow keys (type to
+filter) and create an instance with them, tried in the order picked.
 
 Features column shows instance capabilities:
   S = Stoppable (can stop and restart without losing data)
   R = Rebootable (can reboot the instance)
-  P = Flex Ports (can modify firewall/port rules)`
+  P = Flex Ports (can modify firewall/port rules)
+
+Price-performance sort keys (shown as columns with --wide):
+  price-per-gpu   = $/GPU/hour
+  price-per-vram  = $/GB of total VRAM/hour
+  price-per-tflop = $/dense FP16 TFLOP/hour (from a built-in GPU spec table)
+Instances whose metric is unknown are listed last.
+
+Filter expressions (--where) combine conditions with &&, || and !, e.g.
+  vram>=80 && provider in ("aws","gcp") && price<4 && stoppable
+Fields: type, provider, cloud, gpu, arch (text: =, !=, ~ substring, in);
+gpu_count, vram, total_vram, capability, vcpu, ram, disk, disk_min, boot_time
+(minutes), price, price_per_gpu, price_per_vram, price_per_tflop, fp16_tflops,
+mem_bandwidth (numbers: <, <=, >, >=, =, !=, in); stoppable, rebootable,
+flex_ports (booleans). Text comparisons are case-insensitive.
+
+Output formats (-o): table (default), json, csv, yaml, markdown, or
+template=<go template> executed once per result (fields as in --json, e.g.
+{{.Type}} {{.PricePerHour}}). csv, yaml and markdown use the table columns
+(all of them with --wide); --columns picks and orders columns from: type,
+target_disk, provider, cloud, gpu, gpu_count, vram, total_vram, capability, ram,
+arch, disk, disk_min, disk_max, disk_price, boot_time, features, vcpu, price,
+price_per_gpu, price_per_vram, price_per_tflop, fp16_tflops, mem_bandwidth,
+stoppable, rebootable, flex_ports. csv/yaml values are unformatted numbers
+(GB, minutes, $/hour).
+
+The instance-type catalog is cached under ~/.brev/catalog_cache for 15 minutes
+(override with --cache-ttl or "catalog_cache_ttl" in ~/.brev/personal_settings.json).
+Use --refresh to force a refetch and --offline to search only the cached catalog.`
 
 	gpuExample = `
   # List all GPU instances (default)
@@ -135,12 +169,35 @@ Features column shows instance capabilities:
   # Filter by minimum VRAM per GPU (in GB)
   brev search gpu --min-vram 24
 
-  # Wide output (includes RAM, ARCH columns)
+  # Wide output (includes RAM, ARCH and price-performance columns)
   brev search gpu --wide
 
+  # Rank by price-performance ($/GPU-hour, $/GB-VRAM-hour, $/FP16-TFLOP-hour)
+  brev search gpu --sort price-per-tflop --wide
+  brev search gpu --min-vram 40 --sort price-per-vram
+
   # Sort and combine filters
   brev search gpu --gpu-name H100 --sort price
   brev search gpu --stoppable --min-total-vram 40 --sort price
+
+  # Export for a spreadsheet or docs
+  brev search gpu --wide -o csv > gpus.csv
+  brev search gpu --gpu-name H100 -o markdown --columns type,provider,gpu_count,price,price_per_gpu
+  brev search gpu -o 'template={{.Type}} {{printf "%.2f" .PricePerHour}}'
+
+  # Save a filter set as a preset and reuse it
+  brev search gpu --min-vram 80 --stoppable --provider aws --max-boot-time 10 --save-preset h100-fast
+  brev search --preset h100-fast --sort price-per-gpu
+
+  # Pick types interactively (in fallback order) and create an instance with them
+  brev search gpu --min-vram 40 --pick
+
+  # Search the cached catalog without network access
+  brev search gpu --offline --gpu-name H100
+
+  # Filter with an expression (&&, ||, !, parentheses, <, <=, >, >=, =, !=, ~ for substring, in (...))
+  brev search gpu --where 'vram>=80 && provider in ("aws","gcp") && price<4 && stoppable'
+  brev search gpu --where 'gpu ~ h100 || (gpu ~ a100 && gpu_count>=8)'
 `
 
 	cpuExample = `
@@ -158,6 +215,12 @@ Features column shows instance capabilities:
 
   # Sort by price
   brev search cpu --sort price
+
+  # Export as YAML with selected columns
+  brev search cpu -o yaml --columns type,vcpu,ram,price
+
+  # Filter with an expression
+  brev search cpu --where 'vcpu>=16 && ram>=64 && arch=x86_64'
 `
 )
 
@@ -172,9 +235,66 @@ type sharedFlags struct {
 	stoppable   bool
 	rebootable  bool
 	flexPorts   bool
+	where       string
 	sortBy      string
 	descending  bool
 	jsonOutput  bool
+	output      string
+	columns     string
+	pick        bool
+	presets     PresetFlags
+	cache       CatalogCacheFlags
+}
+
+// applyCatalogCache configures the store's catalog cache from the shared flags
+func (f *sharedFlags) applyCatalogCache(store GPUSearchStore) error {
+	policy, err := f.cache.Policy()
+	if err != nil {
+		return err
+	}
+	ApplyCatalogCachePolicy(store, policy)
+	return nil
+}
+
+// filters returns the filter criteria set by the shared flags
+func (f *sharedFlags) filters() FilterOptions {
+	return FilterOptions{
+		Provider:    f.provider,
+		Arch:        f.arch,
+		MinRAM:      f.minRAM,
+		MinDisk:     f.minDisk,
+		MinVCPU:     f.minVCPU,
+		MaxBootTime: f.maxBootTime,
+		Stoppable:   f.stoppable,
+		Rebootable:  f.rebootable,
+		FlexPorts:   f.flexPorts,
+		Where:       f.where,
+	}
+}
+
+// outputOptions validates the output flags and, with --pick, routes results to the picker
+func (f *sharedFlags) outputOptions(wide bool, onPick PickHandler) (OutputOptions, error) {
+	out, err := ParseOutputOptions(f.output, f.jsonOutput, wide, f.columns)
+	if err != nil || !f.pick {
+		return out, err
+	}
+	if out.Format != OutputTable || len(out.Columns) > 0 {
+		return OutputOptions{}, breverrors.NewValidationError("--pick cannot be combined with --json, -o or --columns")
+	}
+	if onPick == nil {
+		return OutputOptions{}, breverrors.NewValidationError("--pick is not supported here")
+	}
+	if !IsInteractive() {
+		return OutputOptions{}, breverrors.NewValidationError("--pick requires an interactive terminal")
+	}
+	out.Pick = func(instances []GPUInstanceInfo) error {
+		picked, err := PromptPicker{}.Pick(instances)
+		if err != nil {
+			return err
+		}
+		return onPick(picked)
+	}
+	return out, nil
 }
 
 // addSharedFlags adds common flags to a command
@@ -188,13 +308,20 @@ func addSharedFlags(cmd *cobra.Command, f *sharedFlags) {
 	cmd.Flags().BoolVar(&f.stoppable, "stoppable", false, "Only show instances that can be stopped and restarted")
 	cmd.Flags().BoolVar(&f.rebootable, "rebootable", false, "Only show instances that can be rebooted")
 	cmd.Flags().BoolVar(&f.flexPorts, "flex-ports", false, "Only show instances with configurable firewall/port rules")
+	cmd.Flags().StringVar(&f.where, "where", "", `Filter expression, e.g. 'vram>=80 && provider in ("aws","gcp") && price<4 && stoppable'`)
 	cmd.Flags().StringVarP(&f.sortBy, "sort", "s", "price", "Sort by column (see --help for options)")
 	cmd.Flags().BoolVarP(&f.descending, "desc", "d", false, "Sort in descending order")
 	cmd.Flags().BoolVar(&f.jsonOutput, "json", false, "Output results as JSON")
+	cmd.Flags().StringVarP(&f.output, "output", "o", "", "Output format: table, json, csv, yaml, markdown or template=<go template>")
+	cmd.Flags().StringVar(&f.columns, "columns", "", "Comma-separated columns to output (see --help for names)")
+	cmd.Flags().BoolVar(&f.pick, "pick", false, "Interactively pick instance types from the results and create an instance with them")
+	AddPresetFlags(cmd, &f.presets)
+	AddCatalogCacheFlags(cmd, &f.cache)
 }
 
 // NewCmdGPUSearch creates the search command with gpu and cpu subcommands
-func NewCmdGPUSearch(t *terminal.Terminal, store GPUSearchStore) *cobra.Command {
+// onPick is called with the types chosen via --pick (normally creating an instance).
+func NewCmdGPUSearch(t *terminal.Terminal, store GPUSearchStore, onPick PickHandler) *cobra.Command {
 	// GPU-specific flags (also used by parent default)
 	var gpuName string
 	var minVRAM float64
@@ -212,8 +339,20 @@ func NewCmdGPUSearch(t *terminal.Terminal, store GPUSearchStore) *cobra.Command
 		Long:                  searchLong,
 		Example:               gpuExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := shared.presets.Apply(t, cmd, store); err != nil {
+				return err
+			}
+			if err := shared.applyCatalogCache(store); err != nil {
+				return err
+			}
 			// Default behavior: GPU search
-			return RunGPUSearch(t, store, gpuName, shared.provider, shared.arch, minVRAM, minTotalVRAM, minCapability, shared.minRAM, shared.minDisk, shared.minVCPU, shared.maxBootTime, shared.stoppable, shared.rebootable, shared.flexPorts, shared.sortBy, shared.descending, shared.jsonOutput, wide)
+			out, err := shared.outputOptions(wide, onPick)
+			if err != nil {
+				return err
+			}
+			filters := shared.filters()
+			filters.GPUName, filters.MinVRAM, filters.MinTotalVRAM, filters.MinCapability = gpuName, minVRAM, minTotalVRAM, minCapability
+			return RunGPUSearch(t, store, filters, shared.sortBy, shared.descending, out)
 		},
 	}
 
@@ -222,18 +361,20 @@ func NewCmdGPUSearch(t *terminal.Terminal, store GPUSearchStore) *cobra.Command
 	cmd.Flags().Float64VarP(&minVRAM, "min-vram", "v", 0, "Minimum VRAM per GPU in GB")
 	cmd.Flags().Float64VarP(&minTotalVRAM, "min-total-vram", "t", 0, "Minimum total VRAM (GPU count * VRAM) in GB")
 	cmd.Flags().Float64VarP(&minCapability, "min-capability", "c", 0, "Minimum GPU compute capability (e.g., 8.0 for Ampere)")
-	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Show additional columns (RAM, ARCH)")
+	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Show additional columns (RAM, ARCH, price-performance)")
 	addSharedFlags(cmd, &shared)
 
 	// Add subcommands
-	cmd.AddCommand(newCmdGPUSubcommand(t, store))
-	cmd.AddCommand(newCmdCPUSubcommand(t, store))
+	cmd.AddCommand(newCmdGPUSubcommand(t, store, onPick))
+	cmd.AddCommand(newCmdCPUSubcommand(t, store, onPick))
+	cmd.AddCommand(newCmdDiffSubcommand(t, store))
+	cmd.AddCommand(newCmdPresets(t, store))
 
 	return cmd
 }
 
 // newCmdGPUSubcommand creates the explicit 'gpu' subcommand
-func newCmdGPUSubcommand(t *terminal.Terminal, store GPUSearchStore) *cobra.Command {
+func newCmdGPUSubcommand(t *terminal.Terminal, store GPUSearchStore, onPick PickHandler) *cobra.Command {
 	var gpuName string
 	var minVRAM float64
 	var minTotalVRAM float64
@@ -247,7 +388,19 @@ func newCmdGPUSubcommand(t *terminal.Terminal, store GPUSearchStore) *cobra.Comm
 		Short:                 "Search GPU instance types",
 		Example:               gpuExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunGPUSearch(t, store, gpuName, shared.provider, shared.arch, minVRAM, minTotalVRAM, minCapability, shared.minRAM, shared.minDisk, shared.minVCPU, shared.maxBootTime, shared.stoppable, shared.rebootable, shared.flexPorts, shared.sortBy, shared.descending, shared.jsonOutput, wide)
+			if err := shared.presets.Apply(t, cmd, store); err != nil {
+				return err
+			}
+			if err := shared.applyCatalogCache(store); err != nil {
+				return err
+			}
+			out, err := shared.outputOptions(wide, onPick)
+			if err != nil {
+				return err
+			}
+			filters := shared.filters()
+			filters.GPUName, filters.MinVRAM, filters.MinTotalVRAM, filters.MinCapability = gpuName, minVRAM, minTotalVRAM, minCapability
+			return RunGPUSearch(t, store, filters, shared.sortBy, shared.descending, out)
 		},
 	}
 
@@ -255,14 +408,14 @@ func newCmdGPUSubcommand(t *terminal.Terminal, store GPUSearchStore) *cobra.Comm
 	cmd.Flags().Float64VarP(&minVRAM, "min-vram", "v", 0, "Minimum VRAM per GPU in GB")
 	cmd.Flags().Float64VarP(&minTotalVRAM, "min-total-vram", "t", 0, "Minimum total VRAM (GPU count * VRAM) in GB")
 	cmd.Flags().Float64VarP(&minCapability, "min-capability", "c", 0, "Minimum GPU compute capability (e.g., 8.0 for Ampere)")
-	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Show additional columns (RAM, ARCH)")
+	cmd.Flags().BoolVarP(&wide, "wide", "w", false, "Show additional columns (RAM, ARCH, price-performance)")
 	addSharedFlags(cmd, &shared)
 
 	return cmd
 }
 
 // newCmdCPUSubcommand creates the 'cpu' subcommand
-func newCmdCPUSubcommand(t *terminal.Terminal, store GPUSearchStore) *cobra.Command {
+func newCmdCPUSubcommand(t *terminal.Terminal, store GPUSearchStore, onPick PickHandler) *cobra.Command {
 	var shared sharedFlags
 
 	cmd := &cobra.Command{
@@ -271,7 +424,17 @@ func newCmdCPUSubcommand(t *terminal.Terminal, store GPUSearchStore) *cobra.Comm
 		Short:                 "Search CPU-only instance types",
 		Example:               cpuExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunCPUSearch(t, store, shared.provider, shared.arch, shared.minRAM, shared.minDisk, shared.minVCPU, shared.maxBootTime, shared.stoppable, shared.rebootable, shared.flexPorts, shared.sortBy, shared.descending, shared.jsonOutput)
+			if err := shared.presets.Apply(t, cmd, store); err != nil {
+				return err
+			}
+			if err := shared.applyCatalogCache(store); err != nil {
+				return err
+			}
+			out, err := shared.outputOptions(false, onPick)
+			if err != nil {
+				return err
+			}
+			return RunCPUSearch(t, store, shared.filters(), shared.sortBy, shared.descending, out)
 		},
 	}
 
@@ -290,6 +453,8 @@ type GPUInstanceInfo struct {
 	VRAMPerGPU     float64 `json:"vram_per_gpu_gb"`
 	TotalVRAM      float64 `json:"total_vram_gb"`
 	Capability     float64 `json:"capability"`
+	FP16TFLOPS     float64 `json:"fp16_tflops_per_gpu,omitempty"`
+	MemBandwidth   float64 `json:"mem_bandwidth_gbps,omitempty"` // per GPU, GB/s
 	VCPUs          int     `json:"vcpus"`
 	Memory         string  `json:"memory"`
 	RAMInGB        float64 `json:"ram_gb"`
@@ -303,6 +468,9 @@ type GPUInstanceInfo struct {
 	FlexPorts      bool    `json:"flex_ports"`
 	TargetDisk     float64 `json:"target_disk_gb,omitempty"`
 	PricePerHour   float64 `json:"price_per_hour"`
+	PricePerGPU    float64 `json:"price_per_gpu_hour,omitempty"`
+	PricePerVRAM   float64 `json:"price_per_vram_gb_hour,omitempty"`
+	PricePerTFLOP  float64 `json:"price_per_fp16_tflop_hour,omitempty"`
 	Manufacturer   string  `json:"-"` // exclude from JSON output
 }
 
@@ -313,11 +481,16 @@ func IsStdoutPiped() bool {
 }
 
 // RunGPUSearch executes the GPU search with filters and sorting
-func RunGPUSearch(t *terminal.Terminal, store GPUSearchStore, gpuName, provider, arch string, minVRAM, minTotalVRAM, minCapability, minRAM, minDisk float64, minVCPU, maxBootTime int, stoppable, rebootable, flexPorts bool, sortBy string, descending, jsonOutput, wide bool) error {
+func RunGPUSearch(t *terminal.Terminal, store GPUSearchStore, filters FilterOptions, sortBy string, descending bool, out OutputOptions) error {
 	if err := validateSortOption(sortBy); err != nil {
 		return err
 	}
 
+	whereExpr, err := ParseWhereFlag(filters.Where)
+	if err != nil {
+		return err
+	}
+
 	piped := IsStdoutPiped()
 
 	response, err := store.GetInstanceTypes(false)
@@ -326,29 +499,35 @@ func RunGPUSearch(t *terminal.Terminal, store GPUSearchStore, gpuName, provider,
 	}
 
 	if response == nil || len(response.Items) == 0 {
-		return displayEmptyResults(t, "No instance types found", jsonOutput, piped)
+		return displayEmptyResults(t, "No instance types found", out, piped, false)
 	}
 
 	instances := ProcessInstances(response.Items)
 
 	// Filter to GPU-only instances
-	filtered := FilterInstances(instances, gpuName, provider, arch, minVRAM, minTotalVRAM, minCapability, minRAM, minDisk, minVCPU, maxBootTime, stoppable, rebootable, flexPorts, false)
+	filtered := FilterInstances(instances, filters.GPUName, filters.Provider, filters.Arch, filters.MinVRAM, filters.MinTotalVRAM, filters.MinCapability, filters.MinRAM, filters.MinDisk, filters.MinVCPU, filters.MaxBootTime, filters.Stoppable, filters.Rebootable, filters.FlexPorts, false)
+	filtered = FilterWhere(filtered, whereExpr)
 
 	if len(filtered) == 0 {
-		return displayEmptyResults(t, "No GPU instances match the specified filters", jsonOutput, piped)
+		return displayEmptyResults(t, "No GPU instances match the specified filters", out, piped, false)
 	}
 
-	setTargetDisks(filtered, minDisk)
+	setTargetDisks(filtered, filters.MinDisk)
 	SortInstances(filtered, sortBy, descending)
-	return DisplayGPUResults(t, filtered, jsonOutput, piped, wide)
+	return DisplayResults(t, filtered, out, piped, false)
 }
 
 // RunCPUSearch executes the CPU search with filters and sorting
-func RunCPUSearch(t *terminal.Terminal, store GPUSearchStore, provider, arch string, minRAM, minDisk float64, minVCPU, maxBootTime int, stoppable, rebootable, flexPorts bool, sortBy string, descending, jsonOutput bool) error {
+func RunCPUSearch(t *terminal.Terminal, store GPUSearchStore, filters FilterOptions, sortBy string, descending bool, out OutputOptions) error {
 	if err := validateSortOption(sortBy); err != nil {
 		return err
 	}
 
+	whereExpr, err := ParseWhereFlag(filters.Where)
+	if err != nil {
+		return err
+	}
+
 	piped := IsStdoutPiped()
 
 	response, err := store.GetInstanceTypes(true)
@@ -357,21 +536,22 @@ func RunCPUSearch(t *terminal.Terminal, store GPUSearchStore, provider, arch str
 	}
 
 	if response == nil || len(response.Items) == 0 {
-		return displayEmptyResults(t, "No instance types found", jsonOutput, piped)
+		return displayEmptyResults(t, "No instance types found", out, piped, true)
 	}
 
 	instances := ProcessInstances(response.Items)
 
 	// Filter to CPU-only instances
-	filtered := FilterCPUInstances(instances, provider, arch, minRAM, minDisk, minVCPU, maxBootTime, stoppable, rebootable, flexPorts)
+	filtered := FilterCPUInstances(instances, filters.Provider, filters.Arch, filters.MinRAM, filters.MinDisk, filters.MinVCPU, filters.MaxBootTime, filters.Stoppable, filters.Rebootable, filters.FlexPorts)
+	filtered = FilterWhere(filtered, whereExpr)
 
 	if len(filtered) == 0 {
-		return displayEmptyResults(t, "No CPU instances match the specified filters", jsonOutput, piped)
+		return displayEmptyResults(t, "No CPU instances match the specified filters", out, piped, true)
 	}
 
-	setTargetDisks(filtered, minDisk)
+	setTargetDisks(filtered, filters.MinDisk)
 	SortInstances(filtered, sortBy, descending)
-	return DisplayCPUResults(t, filtered, jsonOutput, piped)
+	return DisplayResults(t, filtered, out, piped, true)
 }
 
 // validateSortOption returns an error if sortBy is not a valid option
@@ -388,10 +568,9 @@ func validateSortOption(sortBy string) error {
 }
 
 // displayEmptyResults handles output when no results are found
-func displayEmptyResults(t *terminal.Terminal, message string, jsonOutput, piped bool) error {
-	if jsonOutput {
-		fmt.Println("[]")
-		return nil
+func displayEmptyResults(t *terminal.Terminal, message string, out OutputOptions, piped, cpu bool) error {
+	if handled, err := displayEmptyOutput(out, cpu); handled {
+		return err
 	}
 	if piped {
 		return nil
@@ -493,6 +672,10 @@ var validSortOptions = map[string]bool{
 	"boot-time":  true,
 	"ram":        true,
 	"arch":       true,
+	// Price-performance metrics
+	"price-per-gpu":   true,
+	"price-per-vram":  true,
+	"price-per-tflop": true,
 }
 
 // parseToGB converts size/memory strings like "22GiB360MiB", "16TiB", "2TiB768GiB" to GB
@@ -629,85 +812,100 @@ func extractCloud(instanceType, provider string) string {
 	return provider
 }
 
-// gpuCapabilityEntry represents a GPU pattern and its compute capability
-type gpuCapabilityEntry struct {
-	pattern    string
-	capability float64
+// gpuSpec describes the published per-GPU characteristics of a known GPU model.
+// FP16TFLOPS is dense FP16 tensor throughput and MemBandwidth is in GB/s; zero means unknown.
+type gpuSpec struct {
+	pattern      string
+	capability   float64
+	fp16TFLOPS   float64
+	memBandwidth float64
 }
 
-// getGPUCapability returns the compute capability for known GPU types
-func getGPUCapability(gpuName string) float64 {
-	gpuName = strings.ToUpper(gpuName)
+// gpuSpecs lists known GPUs. Order matters: more specific patterns must come before
+// less specific ones (e.g., "A100" before "A10", "L40S" before "L40")
+var gpuSpecs = []gpuSpec{
+	// NVIDIA Professional (before other RTX patterns)
+	{"RTXPRO6000", 12.0, 504, 1792},
+
+	// NVIDIA Blackwell
+	{"B300", 10.3, 2250, 8000},
+	{"B200", 10.0, 2250, 8000},
+	{"RTX5090", 10.0, 419, 1792},
+
+	// NVIDIA Hopper
+	{"H100", 9.0, 989, 3350},
+	{"H200", 9.0, 989, 4800},
+
+	// NVIDIA Ada Lovelace (L40S before L40, L4; RTX*Ada before RTX*)
+	{"L40S", 8.9, 362, 864},
+	{"L40", 8.9, 181, 864},
+	{"L4", 8.9, 121, 300},
+	{"RTX6000ADA", 8.9, 364, 960},
+	{"RTX4000ADA", 8.9, 107, 360},
+	{"RTX4090", 8.9, 330, 1008},
+	{"RTX4080", 8.9, 195, 717},
+
+	// NVIDIA Ampere (A100 before A10G, A10)
+	{"A100", 8.0, 312, 1555},
+	{"A10G", 8.6, 70, 600},
+	{"A10", 8.6, 125, 600},
+	{"A40", 8.6, 150, 696},
+	{"A6000", 8.6, 155, 768},
+	{"A5000", 8.6, 111, 768},
+	{"A4000", 8.6, 77, 448},
+	{"A30", 8.0, 165, 933},
+	{"A16", 8.6, 36, 200},
+	{"RTX3090", 8.6, 142, 936},
+	{"RTX3080", 8.6, 119, 760},
+
+	// NVIDIA Turing
+	{"T4", 7.5, 65, 320},
+	{"RTX6000", 7.5, 130, 672},
+	{"RTX2080", 7.5, 85, 448},
+
+	// NVIDIA Volta
+	{"V100", 7.0, 125, 900},
+
+	// NVIDIA Pascal (P100 before P40, P4); P40/P4 have no usable FP16 rate
+	{"P100", 6.0, 19, 732},
+	{"P40", 6.1, 0, 346},
+	{"P4", 6.1, 0, 192},
+
+	// NVIDIA Maxwell
+	{"M60", 5.2, 0, 160},
+
+	// NVIDIA Kepler
+	{"K80", 3.7, 0, 240},
+
+	// Gaudi (Habana) - not CUDA compatible
+	{"HL-205", 0, 0, 0},
+	{"GAUDI3", 0, 0, 0},
+	{"GAUDI2", 0, 0, 0},
+	{"GAUDI", 0, 0, 0},
+}
 
-	// Order matters: more specific patterns must come before less specific ones
-	// (e.g., "A100" before "A10", "L40S" before "L40")
-	capabilities := []gpuCapabilityEntry{
-		// NVIDIA Professional (before other RTX patterns)
-		{"RTXPRO6000", 12.0},
-
-		// NVIDIA Blackwell
-		{"B300", 10.3},
-		{"B200", 10.0},
-		{"RTX5090", 10.0},
-
-		// NVIDIA Hopper
-		{"H100", 9.0},
-		{"H200", 9.0},
-
-		// NVIDIA Ada Lovelace (L40S before L40, L4; RTX*Ada before RTX*)
-		{"L40S", 8.9},
-		{"L40", 8.9},
-		{"L4", 8.9},
-		{"RTX6000ADA", 8.9},
-		{"RTX4000ADA", 8.9},
-		{"RTX4090", 8.9},
-		{"RTX4080", 8.9},
-
-		// NVIDIA Ampere (A100 before A10G, A10)
-		{"A100", 8.0},
-		{"A10G", 8.6},
-		{"A10", 8.6},
-		{"A40", 8.6},
-		{"A6000", 8.6},
-		{"A5000", 8.6},
-		{"A4000", 8.6},
-		{"A30", 8.0},
-		{"A16", 8.6},
-		{"RTX3090", 8.6},
-		{"RTX3080", 8.6},
-
-		// NVIDIA Turing
-		{"T4", 7.5},
-		{"RTX6000", 7.5},
-		{"RTX2080", 7.5},
-
-		// NVIDIA Volta
-		{"V100", 7.0},
-
-		// NVIDIA Pascal (P100 before P40, P4)
-		{"P100", 6.0},
-		{"P40", 6.1},
-		{"P4", 6.1},
-
-		// NVIDIA Maxwell
-		{"M60", 5.2},
-
-		// NVIDIA Kepler
-		{"K80", 3.7},
-
-		// Gaudi (Habana) - not CUDA compatible
-		{"HL-205", 0},
-		{"GAUDI3", 0},
-		{"GAUDI2", 0},
-		{"GAUDI", 0},
-	}
-
-	for _, entry := range capabilities {
+// getGPUSpec returns the spec entry for a known GPU type, or a zero-valued spec if unknown
+func getGPUSpec(gpuName string) gpuSpec {
+	gpuName = strings.ToUpper(gpuName)
+	for _, entry := range gpuSpecs {
 		if strings.Contains(gpuName, entry.pattern) {
-			return entry.capability
+			return entry
 		}
 	}
-	return 0
+	return gpuSpec{}
+}
+
+// getGPUCapability returns the compute capability for known GPU types
+func getGPUCapability(gpuName string) float64 {
+	return getGPUSpec(gpuName).capability
+}
+
+// perUnit divides an hourly price by a quantity, returning 0 when either is unknown
+func perUnit(price, quantity float64) float64 {
+	if price <= 0 || quantity <= 0 {
+		return 0
+	}
+	return price / quantity
 }
 
 // ProcessInstances converts raw instance types to GPUInstanceInfo
@@ -771,7 +969,8 @@ func ProcessInstances(items []InstanceType) []GPUInstanceInfo {
 			}
 
 			totalVRAM := vramPerGPU * float64(gpu.Count)
-			capability := getGPUCapability(gpu.Name)
+			spec := getGPUSpec(gpu.Name)
+			totalTFLOPS := spec.fp16TFLOPS * float64(gpu.Count)
 
 			instances = append(instances, GPUInstanceInfo{
 				Type:           item.Type,
@@ -781,7 +980,9 @@ func ProcessInstances(items []InstanceType) []GPUInstanceInfo {
 				GPUCount:       gpu.Count,
 				VRAMPerGPU:     vramPerGPU,
 				TotalVRAM:      totalVRAM,
-				Capability:     capability,
+				Capability:     spec.capability,
+				FP16TFLOPS:     spec.fp16TFLOPS,
+				MemBandwidth:   spec.memBandwidth,
 				VCPUs:          item.VCPU,
 				Memory:         item.Memory,
 				RAMInGB:        ramInGB,
@@ -794,6 +995,9 @@ func ProcessInstances(items []InstanceType) []GPUInstanceInfo {
 				Rebootable:     item.Rebootable,
 				FlexPorts:      item.CanModifyFirewallRules,
 				PricePerHour:   price,
+				PricePerGPU:    perUnit(price, float64(gpu.Count)),
+				PricePerVRAM:   perUnit(price, totalVRAM),
+				PricePerTFLOP:  perUnit(price, totalTFLOPS),
 				Manufacturer:   gpu.Manufacturer,
 			})
 		}
@@ -817,6 +1021,7 @@ type FilterOptions struct {
 	Stoppable     bool
 	Rebootable    bool
 	FlexPorts     bool
+	Where         string // --where expression, applied by the search commands with FilterWhere
 }
 
 // matchesStringFilters checks GPU name and provider filters
@@ -969,6 +1174,16 @@ func SortInstances(instances []GPUInstanceInfo, sortBy string, descending bool)
 				return true
 			}
 			less = instances[i].BootTime < instances[j].BootTime
+		case "price-per-gpu", "price-per-vram", "price-per-tflop":
+			// Instances with an unknown metric always sort last
+			vi, vj := pricePerformanceMetric(instances[i], sortBy), pricePerformanceMetric(instances[j], sortBy)
+			switch {
+			case vi == 0:
+				return false
+			case vj == 0:
+				return true
+			}
+			less = vi < vj
 		default:
 			less = instances[i].PricePerHour < instances[j].PricePerHour
 		}
@@ -980,6 +1195,19 @@ func SortInstances(instances []GPUInstanceInfo, sortBy string, descending bool)
 	})
 }
 
+// pricePerformanceMetric returns the derived $/unit/hour value for a price-performance sort key
+func pricePerformanceMetric(inst GPUInstanceInfo, sortBy string) float64 {
+	switch strings.ToLower(sortBy) {
+	case "price-per-gpu":
+		return inst.PricePerGPU
+	case "price-per-vram":
+		return inst.PricePerVRAM
+	case "price-per-tflop":
+		return inst.PricePerTFLOP
+	}
+	return 0
+}
+
 // getBrevTableOptions returns table styling options
 func getBrevTableOptions() table.Options {
 	options := table.OptionsDefault
@@ -1056,6 +1284,9 @@ type formattedInstanceFields struct {
 	Price      string
 	Provider   string
 	TargetDisk string
+	PerGPU     string
+	PerVRAM    string
+	PerTFLOP   string
 }
 
 // formatInstanceFields formats common instance fields for table display
@@ -1104,7 +1335,18 @@ func formatInstanceFields(inst GPUInstanceInfo, includeUnits bool) formattedInst
 		Price:      fmt.Sprintf("$%.2f", inst.PricePerHour),
 		Provider:   providerStr,
 		TargetDisk: fmt.Sprintf("%.0f", inst.TargetDisk),
+		PerGPU:     formatUnitPrice(inst.PricePerGPU, "%.2f"),
+		PerVRAM:    formatUnitPrice(inst.PricePerVRAM, "%.3f"),
+		PerTFLOP:   formatUnitPrice(inst.PricePerTFLOP, "%.3f"),
+	}
+}
+
+// formatUnitPrice formats a derived $/unit/hour value, showing "-" when unknown
+func formatUnitPrice(value float64, format string) string {
+	if value <= 0 {
+		return "-"
 	}
+	return "$" + fmt.Sprintf(format, value)
 }
 
 // displayGPUTable renders the GPU instances as a table
@@ -1174,13 +1416,13 @@ func displayGPUTablePlain(instances []GPUInstanceInfo) {
 	ta.Render()
 }
 
-// displayGPUTableWide renders the GPU instances with additional RAM and ARCH columns
+// displayGPUTableWide renders the GPU instances with additional RAM, ARCH and price-performance columns
 func displayGPUTableWide(t *terminal.Terminal, instances []GPUInstanceInfo) {
 	ta := table.NewWriter()
 	ta.SetOutputMirror(os.Stdout)
 	ta.Style().Options = getBrevTableOptions()
 
-	header := table.Row{"TYPE", "PROVIDER", "GPU", "COUNT", "VRAM/GPU", "TOTAL VRAM", "CAPABILITY", "RAM", "ARCH", "DISK", "$/GB/MO", "BOOT", "FEATURES", "VCPUs", "$/HR"}
+	header := table.Row{"TYPE", "PROVIDER", "GPU", "COUNT", "VRAM/GPU", "TOTAL VRAM", "CAPABILITY", "RAM", "ARCH", "DISK", "$/GB/MO", "BOOT", "FEATURES", "VCPUs", "$/HR", "$/GPU/HR", "$/VRAM GB/HR", "$/TFLOP/HR"}
 	ta.AppendHeader(header)
 
 	for _, inst := range instances {
@@ -1201,6 +1443,9 @@ func displayGPUTableWide(t *terminal.Terminal, instances []GPUInstanceInfo) {
 			f.Features,
 			inst.VCPUs,
 			f.Price,
+			f.PerGPU,
+			f.PerVRAM,
+			f.PerTFLOP,
 		}
 		ta.AppendRow(row)
 	}
@@ -1214,7 +1459,7 @@ func displayGPUTablePlainWide(instances []GPUInstanceInfo) {
 	ta.SetOutputMirror(os.Stdout)
 	ta.Style().Options = getBrevTableOptions()
 
-	header := table.Row{"TYPE", "TARGET_DISK", "PROVIDER", "GPU", "COUNT", "VRAM/GPU", "TOTAL_VRAM", "CAPABILITY", "RAM", "ARCH", "DISK", "$/GB/MO", "BOOT", "FEATURES", "VCPUs", "$/HR"}
+	header := table.Row{"TYPE", "TARGET_DISK", "PROVIDER", "GPU", "COUNT", "VRAM/GPU", "TOTAL_VRAM", "CAPABILITY", "RAM", "ARCH", "DISK", "$/GB/MO", "BOOT", "FEATURES", "VCPUs", "$/HR", "$/GPU/HR", "$/VRAM_GB/HR", "$/TFLOP/HR"}
 	ta.AppendHeader(header)
 
 	for _, inst := range instances {
@@ -1236,6 +1481,9 @@ func displayGPUTablePlainWide(instances []GPUInstanceInfo) {
 			f.Features,
 			inst.VCPUs,
 			f.Price,
+			f.PerGPU,
+			f.PerVRAM,
+			f.PerTFLOP,
 		}
 		ta.AppendRow(row)
 	}
@@ -330,6 +330,68 @@ func TestSortInstancesByType(t *testing.T) {
 	assert.Equal(t, "p4d.24xlarge", instances[0].Type, "p4d.24xlarge should be first when descending")
 }
 
+func TestPricePerformanceMetrics(t *testing.T) {
+	response := createTestInstanceTypes()
+	instances := ProcessInstances(response.Items)
+
+	byType := make(map[string]GPUInstanceInfo)
+	for _, inst := range instances {
+		byType[inst.Type] = inst
+	}
+
+	p4d := byType["p4d.24xlarge"]
+	assert.InDelta(t, 32.77/8, p4d.PricePerGPU, 0.0001)
+	assert.InDelta(t, 32.77/320, p4d.PricePerVRAM, 0.0001)
+	assert.InDelta(t, 32.77/(8*312), p4d.PricePerTFLOP, 0.0001)
+	assert.Equal(t, 312.0, p4d.FP16TFLOPS)
+	assert.Equal(t, 1555.0, p4d.MemBandwidth)
+
+	// Same GPU, different count: per-GPU price is comparable
+	assert.InDelta(t, byType["p3.2xlarge"].PricePerGPU, byType["p3.8xlarge"].PricePerGPU, 0.0001)
+}
+
+func TestPricePerformanceUnknownGPU(t *testing.T) {
+	instances := ProcessInstances([]InstanceType{
+		{
+			Type:          "mystery.xlarge",
+			SupportedGPUs: []GPU{{Count: 2, Name: "X9000", Manufacturer: "NVIDIA", Memory: "48GiB"}},
+			BasePrice:     BasePrice{Currency: "USD", Amount: "2.00"},
+		},
+	})
+	assert.Len(t, instances, 1)
+	assert.InDelta(t, 1.0, instances[0].PricePerGPU, 0.0001)
+	assert.InDelta(t, 2.0/96, instances[0].PricePerVRAM, 0.0001)
+	assert.Equal(t, 0.0, instances[0].PricePerTFLOP, "Unknown GPUs have no TFLOP metric")
+}
+
+func TestSortInstancesByPricePerformance(t *testing.T) {
+	response := createTestInstanceTypes()
+	instances := ProcessInstances(response.Items)
+
+	SortInstances(instances, "price-per-tflop", false)
+	assert.Equal(t, "g6.xlarge", instances[0].Type, "L4 should be cheapest per TFLOP")
+
+	SortInstances(instances, "price-per-vram", false)
+	assert.Equal(t, "g4dn.xlarge", instances[0].Type, "T4 should be cheapest per GB of VRAM")
+
+	SortInstances(instances, "price-per-gpu", true)
+	assert.Equal(t, "p4d.24xlarge", instances[0].Type, "A100 should be most expensive per GPU")
+}
+
+func TestSortInstancesByPricePerformanceUnknownLast(t *testing.T) {
+	instances := []GPUInstanceInfo{
+		{Type: "unknown", PricePerTFLOP: 0},
+		{Type: "expensive", PricePerTFLOP: 0.05},
+		{Type: "cheap", PricePerTFLOP: 0.01},
+	}
+
+	SortInstances(instances, "price-per-tflop", false)
+	assert.Equal(t, []string{"cheap", "expensive", "unknown"}, []string{instances[0].Type, instances[1].Type, instances[2].Type})
+
+	SortInstances(instances, "price-per-tflop", true)
+	assert.Equal(t, []string{"expensive", "cheap", "unknown"}, []string{instances[0].Type, instances[1].Type, instances[2].Type})
+}
+
 func TestEmptyInstanceTypes(t *testing.T) {
 	response := &InstanceTypesResponse{Items: []InstanceType{}}
 	instances := ProcessInstances(response.Items)
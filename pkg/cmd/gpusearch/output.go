@@ -0,0 +1,391 @@
+package gpusearch
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by -o/--output
+const (
+	OutputTable    = "table"
+	OutputJSON     = "json"
+	OutputCSV      = "csv"
+	OutputYAML     = "yaml"
+	OutputMarkdown = "markdown"
+	OutputTemplate = "template"
+)
+
+// OutputOptions controls how search results are rendered
+type OutputOptions struct {
+	Format   string
+	Template *template.Template
+	Wide     bool
+	Columns  []string // canonical column names; empty means the default (or --wide) set
+	// Pick, when set, hands the results to the interactive picker instead of rendering them (--pick)
+	Pick func(instances []GPUInstanceInfo) error
+}
+
+// ParseOutputOptions validates the -o/--output, --json, --wide and --columns flags.
+// output is one of table, json, csv, yaml, markdown (md) or template=<go template>.
+func ParseOutputOptions(output string, jsonOutput, wide bool, columns string) (OutputOptions, error) {
+	opts := OutputOptions{Format: OutputTable, Wide: wide}
+
+	name, arg, hasArg := strings.Cut(output, "=")
+	switch strings.ToLower(name) {
+	case "", OutputTable:
+	case OutputJSON:
+		opts.Format = OutputJSON
+	case OutputCSV:
+		opts.Format = OutputCSV
+	case OutputYAML, "yml":
+		opts.Format = OutputYAML
+	case OutputMarkdown, "md":
+		opts.Format = OutputMarkdown
+	case OutputTemplate, "go-template":
+		if !hasArg || arg == "" {
+			return OutputOptions{}, breverrors.NewValidationError("-o template requires a template, e.g. -o 'template={{.Type}} {{.PricePerHour}}'")
+		}
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return OutputOptions{}, breverrors.NewValidationError(fmt.Sprintf("invalid output template: %v", err))
+		}
+		opts.Format = OutputTemplate
+		opts.Template = tmpl
+	default:
+		return OutputOptions{}, breverrors.NewValidationError(fmt.Sprintf("invalid output format %q. Valid formats: table, json, csv, yaml, markdown, template=<tmpl>", output))
+	}
+	if hasArg && opts.Format != OutputTemplate {
+		return OutputOptions{}, breverrors.NewValidationError(fmt.Sprintf("output format %q does not take an argument", name))
+	}
+
+	if jsonOutput {
+		if opts.Format != OutputTable && opts.Format != OutputJSON {
+			return OutputOptions{}, breverrors.NewValidationError("--json cannot be combined with -o " + name)
+		}
+		opts.Format = OutputJSON
+	}
+
+	if columns != "" {
+		if opts.Format == OutputTemplate {
+			return OutputOptions{}, breverrors.NewValidationError("--columns cannot be combined with -o template")
+		}
+		cols, err := parseColumnList(columns)
+		if err != nil {
+			return OutputOptions{}, err
+		}
+		opts.Columns = cols
+	}
+	return opts, nil
+}
+
+// searchColumn describes a selectable output column
+type searchColumn struct {
+	header  string
+	display func(inst GPUInstanceInfo, f formattedInstanceFields) string
+	raw     func(inst GPUInstanceInfo) interface{}
+}
+
+// searchColumns maps column names (shared with --where where they overlap) to columns.
+// raw values are used for csv/yaml/json; sizes are in GB, boot_time in minutes and prices in $/hour.
+var searchColumns = map[string]searchColumn{
+	"type":            {"TYPE", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return i.Type }, func(i GPUInstanceInfo) interface{} { return i.Type }},
+	"target_disk":     {"TARGET_DISK", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.TargetDisk }, func(i GPUInstanceInfo) interface{} { return i.TargetDisk }},
+	"provider":        {"PROVIDER", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.Provider }, func(i GPUInstanceInfo) interface{} { return i.Provider }},
+	"cloud":           {"CLOUD", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return i.Cloud }, func(i GPUInstanceInfo) interface{} { return i.Cloud }},
+	"gpu":             {"GPU", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return i.GPUName }, func(i GPUInstanceInfo) interface{} { return i.GPUName }},
+	"gpu_count":       {"COUNT", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return strconv.Itoa(i.GPUCount) }, func(i GPUInstanceInfo) interface{} { return i.GPUCount }},
+	"vram":            {"VRAM/GPU", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.VRAM }, func(i GPUInstanceInfo) interface{} { return i.VRAMPerGPU }},
+	"total_vram":      {"TOTAL VRAM", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.TotalVRAM }, func(i GPUInstanceInfo) interface{} { return i.TotalVRAM }},
+	"capability":      {"CAPABILITY", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.Capability }, func(i GPUInstanceInfo) interface{} { return i.Capability }},
+	"ram":             {"RAM", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.RAM }, func(i GPUInstanceInfo) interface{} { return i.RAMInGB }},
+	"arch":            {"ARCH", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return i.Arch }, func(i GPUInstanceInfo) interface{} { return i.Arch }},
+	"disk":            {"DISK", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.Disk }, func(i GPUInstanceInfo) interface{} { return formatDiskSize(i.DiskMin, i.DiskMax) }},
+	"disk_min":        {"DISK MIN", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return formatDiskSize(i.DiskMin, i.DiskMin) }, func(i GPUInstanceInfo) interface{} { return i.DiskMin }},
+	"disk_max":        {"DISK MAX", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return formatDiskSize(i.DiskMax, i.DiskMax) }, func(i GPUInstanceInfo) interface{} { return i.DiskMax }},
+	"disk_price":      {"$/GB/MO", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.DiskPrice }, func(i GPUInstanceInfo) interface{} { return i.DiskPricePerMo }},
+	"boot_time":       {"BOOT", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.Boot }, func(i GPUInstanceInfo) interface{} { return float64(i.BootTime) / 60 }},
+	"features":        {"FEATURES", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.Features }, func(i GPUInstanceInfo) interface{} { return formatFeatures(i.Stoppable, i.Rebootable, i.FlexPorts) }},
+	"vcpu":            {"VCPUs", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return strconv.Itoa(i.VCPUs) }, func(i GPUInstanceInfo) interface{} { return i.VCPUs }},
+	"price":           {"$/HR", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.Price }, func(i GPUInstanceInfo) interface{} { return i.PricePerHour }},
+	"price_per_gpu":   {"$/GPU/HR", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.PerGPU }, func(i GPUInstanceInfo) interface{} { return i.PricePerGPU }},
+	"price_per_vram":  {"$/VRAM GB/HR", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.PerVRAM }, func(i GPUInstanceInfo) interface{} { return i.PricePerVRAM }},
+	"price_per_tflop": {"$/TFLOP/HR", func(_ GPUInstanceInfo, f formattedInstanceFields) string { return f.PerTFLOP }, func(i GPUInstanceInfo) interface{} { return i.PricePerTFLOP }},
+	"fp16_tflops":     {"FP16 TFLOPS", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return formatOptionalNumber(i.FP16TFLOPS) }, func(i GPUInstanceInfo) interface{} { return i.FP16TFLOPS }},
+	"mem_bandwidth":   {"MEM BW GB/S", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return formatOptionalNumber(i.MemBandwidth) }, func(i GPUInstanceInfo) interface{} { return i.MemBandwidth }},
+	"stoppable":       {"STOPPABLE", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return strconv.FormatBool(i.Stoppable) }, func(i GPUInstanceInfo) interface{} { return i.Stoppable }},
+	"rebootable":      {"REBOOTABLE", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return strconv.FormatBool(i.Rebootable) }, func(i GPUInstanceInfo) interface{} { return i.Rebootable }},
+	"flex_ports":      {"FLEX PORTS", func(i GPUInstanceInfo, _ formattedInstanceFields) string { return strconv.FormatBool(i.FlexPorts) }, func(i GPUInstanceInfo) interface{} { return i.FlexPorts }},
+}
+
+// Default column sets, matching the table layouts
+var (
+	gpuDefaultColumns = []string{"type", "provider", "gpu", "gpu_count", "vram", "total_vram", "capability", "disk", "disk_price", "boot_time", "features", "vcpu", "price"}
+	gpuWideColumns    = []string{"type", "provider", "gpu", "gpu_count", "vram", "total_vram", "capability", "ram", "arch", "disk", "disk_price", "boot_time", "features", "vcpu", "price", "price_per_gpu", "price_per_vram", "price_per_tflop"}
+	cpuDefaultColumns = []string{"type", "provider", "vcpu", "ram", "arch", "disk", "disk_price", "boot_time", "features", "price"}
+)
+
+// OutputColumnNames returns the sorted list of names usable with --columns
+func OutputColumnNames() []string {
+	names := make([]string, 0, len(searchColumns))
+	for name := range searchColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseColumnList resolves a comma-separated column list to canonical names
+func parseColumnList(columns string) ([]string, error) {
+	var cols []string
+	for _, c := range strings.Split(columns, ",") {
+		name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(c)), "-", "_")
+		if name == "" {
+			continue
+		}
+		if alias, ok := whereFieldAliases[name]; ok {
+			name = alias
+		}
+		if _, ok := searchColumns[name]; !ok {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("unknown column %q (valid columns: %s)", strings.TrimSpace(c), strings.Join(OutputColumnNames(), ", ")))
+		}
+		cols = append(cols, name)
+	}
+	if len(cols) == 0 {
+		return nil, breverrors.NewValidationError("--columns must name at least one column")
+	}
+	return cols, nil
+}
+
+// columnsFor returns the columns to render for GPU or CPU results
+func (o OutputOptions) columnsFor(cpu bool) []string {
+	switch {
+	case len(o.Columns) > 0:
+		return o.Columns
+	case cpu:
+		return cpuDefaultColumns
+	case o.Wide:
+		return gpuWideColumns
+	default:
+		return gpuDefaultColumns
+	}
+}
+
+// DisplayResults renders GPU (cpu=false) or CPU instances in the requested output format
+func DisplayResults(t *terminal.Terminal, instances []GPUInstanceInfo, opts OutputOptions, piped, cpu bool) error {
+	if opts.Pick != nil {
+		return opts.Pick(instances)
+	}
+	switch opts.Format {
+	case OutputTable:
+		if len(opts.Columns) == 0 {
+			if cpu {
+				return DisplayCPUResults(t, instances, false, piped)
+			}
+			return DisplayGPUResults(t, instances, false, piped, opts.Wide)
+		}
+		displayColumnTable(t, instances, opts.Columns, piped)
+		return nil
+	case OutputJSON:
+		if len(opts.Columns) == 0 {
+			return displayJSON(instances)
+		}
+		return writeColumnJSON(os.Stdout, instances, opts.Columns)
+	case OutputCSV:
+		return writeCSV(os.Stdout, instances, opts.columnsFor(cpu))
+	case OutputYAML:
+		return writeYAML(os.Stdout, instances, opts.columnsFor(cpu))
+	case OutputMarkdown:
+		writeMarkdown(os.Stdout, instances, opts.columnsFor(cpu))
+		return nil
+	case OutputTemplate:
+		return writeTemplate(os.Stdout, instances, opts.Template)
+	}
+	return nil
+}
+
+// displayEmptyOutput prints the empty result for machine-readable formats.
+// It returns false for table output so the caller can show a message instead.
+func displayEmptyOutput(opts OutputOptions, cpu bool) (bool, error) {
+	switch opts.Format {
+	case OutputJSON, OutputYAML:
+		fmt.Println("[]")
+		return true, nil
+	case OutputCSV:
+		return true, writeCSV(os.Stdout, nil, opts.columnsFor(cpu))
+	case OutputMarkdown, OutputTemplate:
+		return true, nil
+	}
+	return false, nil
+}
+
+// displayColumnTable renders a table with a user-chosen column list
+func displayColumnTable(t *terminal.Terminal, instances []GPUInstanceInfo, columns []string, piped bool) {
+	ta := table.NewWriter()
+	ta.SetOutputMirror(os.Stdout)
+	ta.Style().Options = getBrevTableOptions()
+
+	header := table.Row{}
+	for _, name := range columns {
+		h := searchColumns[name].header
+		if piped {
+			h = strings.ReplaceAll(h, " ", "_")
+		}
+		header = append(header, h)
+	}
+	ta.AppendHeader(header)
+
+	for _, inst := range instances {
+		f := formatInstanceFields(inst, !piped)
+		row := table.Row{}
+		for _, name := range columns {
+			value := searchColumns[name].display(inst, f)
+			if name == "gpu" && !piped {
+				value = t.Green(value)
+			}
+			row = append(row, value)
+		}
+		ta.AppendRow(row)
+	}
+	ta.Render()
+}
+
+// formatRawValue formats a raw column value for CSV
+func formatRawValue(v interface{}) string {
+	switch val := v.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// writeCSV writes instances as CSV with a header row of column names
+func writeCSV(w io.Writer, instances []GPUInstanceInfo, columns []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	for _, inst := range instances {
+		record := make([]string, len(columns))
+		for i, name := range columns {
+			record[i] = formatRawValue(searchColumns[name].raw(inst))
+		}
+		if err := cw.Write(record); err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+	}
+	cw.Flush()
+	return breverrors.WrapAndTrace(cw.Error())
+}
+
+// writeYAML writes instances as a YAML list, keeping keys in column order
+func writeYAML(w io.Writer, instances []GPUInstanceInfo, columns []string) error {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, inst := range instances {
+		item := &yaml.Node{Kind: yaml.MappingNode}
+		for _, name := range columns {
+			value := &yaml.Node{}
+			if err := value.Encode(searchColumns[name].raw(inst)); err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			item.Content = append(item.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+		}
+		list.Content = append(list.Content, item)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(list); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	return breverrors.WrapAndTrace(enc.Close())
+}
+
+// writeColumnJSON writes instances as a JSON list restricted to the given columns, in column order
+func writeColumnJSON(w io.Writer, instances []GPUInstanceInfo, columns []string) error {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, inst := range instances {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for j, name := range columns {
+			value, err := json.Marshal(searchColumns[name].raw(inst))
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			if j > 0 {
+				buf.WriteString(",")
+			}
+			fmt.Fprintf(&buf, "\n    %q: %s", name, value)
+		}
+		buf.WriteString("\n  }")
+	}
+	if len(instances) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	_, err := w.Write(buf.Bytes())
+	return breverrors.WrapAndTrace(err)
+}
+
+// writeMarkdown writes instances as a GitHub-flavored Markdown table
+func writeMarkdown(w io.Writer, instances []GPUInstanceInfo, columns []string) {
+	ta := table.NewWriter()
+	ta.SetOutputMirror(w)
+
+	header := table.Row{}
+	for _, name := range columns {
+		header = append(header, searchColumns[name].header)
+	}
+	ta.AppendHeader(header)
+
+	for _, inst := range instances {
+		f := formatInstanceFields(inst, true)
+		row := table.Row{}
+		for _, name := range columns {
+			row = append(row, searchColumns[name].display(inst, f))
+		}
+		ta.AppendRow(row)
+	}
+	ta.RenderMarkdown()
+}
+
+// writeTemplate executes the template once per instance, one line each
+func writeTemplate(w io.Writer, instances []GPUInstanceInfo, tmpl *template.Template) error {
+	for _, inst := range instances {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, inst); err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+	}
+	return nil
+}
+
+// formatOptionalNumber formats a value that is 0 when unknown
+func formatOptionalNumber(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,116 @@
+package gpusearch
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func outputTestInstances() []GPUInstanceInfo {
+	return []GPUInstanceInfo{
+		{Type: "g5.xlarge", Provider: "aws", Cloud: "aws", GPUName: "A10G", GPUCount: 1, VRAMPerGPU: 24, TotalVRAM: 24, VCPUs: 4, BootTime: 420, PricePerHour: 1.006, Stoppable: true},
+		{Type: "p4d.24xlarge", Provider: "aws", Cloud: "aws", GPUName: "A100, 40GB", GPUCount: 8, VRAMPerGPU: 40, TotalVRAM: 320, VCPUs: 96, BootTime: 600, PricePerHour: 32.77},
+	}
+}
+
+func TestParseOutputOptions(t *testing.T) {
+	opts, err := ParseOutputOptions("", false, true, "")
+	require.NoError(t, err)
+	assert.Equal(t, OutputTable, opts.Format)
+	assert.True(t, opts.Wide)
+
+	opts, err = ParseOutputOptions("", true, false, "")
+	require.NoError(t, err)
+	assert.Equal(t, OutputJSON, opts.Format)
+
+	opts, err = ParseOutputOptions("md", false, false, "Type, count,VCPUS")
+	require.NoError(t, err)
+	assert.Equal(t, OutputMarkdown, opts.Format)
+	assert.Equal(t, []string{"type", "gpu_count", "vcpu"}, opts.Columns)
+
+	opts, err = ParseOutputOptions("template={{.Type}}", false, false, "")
+	require.NoError(t, err)
+	assert.Equal(t, OutputTemplate, opts.Format)
+	assert.NotNil(t, opts.Template)
+}
+
+func TestParseOutputOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		json    bool
+		columns string
+	}{
+		{"unknown format", "xml", false, ""},
+		{"json conflicts with csv", "csv", true, ""},
+		{"template without body", "template", false, ""},
+		{"bad template", "template={{.Type", false, ""},
+		{"argument on csv", "csv=x", false, ""},
+		{"unknown column", "csv", false, "type,bogus"},
+		{"empty column list", "csv", false, " , "},
+		{"columns with template", "template={{.Type}}", false, "type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOutputOptions(tt.output, tt.json, false, tt.columns)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestColumnsFor(t *testing.T) {
+	assert.Equal(t, gpuDefaultColumns, OutputOptions{}.columnsFor(false))
+	assert.Equal(t, gpuWideColumns, OutputOptions{Wide: true}.columnsFor(false))
+	assert.Equal(t, cpuDefaultColumns, OutputOptions{Wide: true}.columnsFor(true))
+	assert.Equal(t, []string{"price"}, OutputOptions{Columns: []string{"price"}}.columnsFor(true))
+}
+
+func TestAllColumnsDefined(t *testing.T) {
+	for _, set := range [][]string{gpuDefaultColumns, gpuWideColumns, cpuDefaultColumns} {
+		for _, name := range set {
+			_, ok := searchColumns[name]
+			assert.True(t, ok, name)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, outputTestInstances(), []string{"type", "gpu", "gpu_count", "boot_time", "price", "stoppable"}))
+	assert.Equal(t, "type,gpu,gpu_count,boot_time,price,stoppable\n"+
+		"g5.xlarge,A10G,1,7,1.006,true\n"+
+		"p4d.24xlarge,\"A100, 40GB\",8,10,32.77,false\n", buf.String())
+}
+
+func TestWriteYAMLKeepsColumnOrder(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeYAML(&buf, outputTestInstances()[:1], []string{"type", "price", "gpu_count"}))
+	assert.Equal(t, "- type: g5.xlarge\n  price: 1.006\n  gpu_count: 1\n", buf.String())
+}
+
+func TestWriteColumnJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeColumnJSON(&buf, outputTestInstances(), []string{"type", "price"}))
+	assert.JSONEq(t, `[{"type":"g5.xlarge","price":1.006},{"type":"p4d.24xlarge","price":32.77}]`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeColumnJSON(&buf, nil, []string{"type"}))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	writeMarkdown(&buf, outputTestInstances()[:1], []string{"type", "gpu", "price"})
+	assert.Equal(t, "| TYPE | GPU | $/HR |\n| --- | --- | --- |\n| g5.xlarge | A10G | $1.01 |\n", buf.String())
+}
+
+func TestWriteTemplate(t *testing.T) {
+	opts, err := ParseOutputOptions(`template={{.Type}} {{printf "%.2f" .PricePerHour}}`, false, false, "")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTemplate(&buf, outputTestInstances(), opts.Template))
+	assert.Equal(t, "g5.xlarge 1.01\np4d.24xlarge 32.77\n", buf.String())
+}
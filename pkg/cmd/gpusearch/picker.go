@@ -0,0 +1,112 @@
+package gpusearch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/manifoldco/promptui"
+)
+
+// PickHandler receives the instances chosen with --pick, in the order they were picked
+type PickHandler func(picked []GPUInstanceInfo) error
+
+// InstancePicker lets the user choose one or more instances interactively
+type InstancePicker interface {
+	Pick(instances []GPUInstanceInfo) ([]GPUInstanceInfo, error)
+}
+
+// PromptPicker is an InstancePicker backed by an arrow-key select list with live filtering
+type PromptPicker struct{}
+
+const pickDoneLabel = "✔ Done"
+
+// Pick shows the instances until the user selects "Done". Each selected row is
+// appended to the result and removed from the list, so the order is the
+// fallback order passed to create.
+func (PromptPicker) Pick(instances []GPUInstanceInfo) ([]GPUInstanceInfo, error) {
+	remaining := append([]GPUInstanceInfo(nil), instances...)
+	var picked []GPUInstanceInfo
+
+	for len(remaining) > 0 {
+		items := make([]string, 0, len(remaining)+1)
+		offset := 0
+		if len(picked) > 0 {
+			items = append(items, fmt.Sprintf("%s (%d selected: %s)", pickDoneLabel, len(picked), pickedTypes(picked)))
+			offset = 1
+		}
+		for _, inst := range remaining {
+			items = append(items, formatPickRow(inst))
+		}
+
+		label := "Pick an instance type (type to filter, ↑/↓ to move, enter to select)"
+		if len(picked) > 0 {
+			label = "Pick a fallback instance type, or Done to continue"
+		}
+		prompt := promptui.Select{
+			Label:             label,
+			Items:             items,
+			Size:              15,
+			StartInSearchMode: true,
+			Searcher: func(input string, index int) bool {
+				return pickRowMatches(items[index], input)
+			},
+		}
+
+		idx, _, err := prompt.Run()
+		if err != nil {
+			if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+				return nil, breverrors.NewValidationError("selection cancelled")
+			}
+			return nil, breverrors.WrapAndTrace(err)
+		}
+		if idx < offset {
+			break
+		}
+		picked = append(picked, remaining[idx-offset])
+		remaining = append(remaining[:idx-offset], remaining[idx-offset+1:]...)
+	}
+
+	return picked, nil
+}
+
+// IsInteractive returns true if both stdin and stdout are terminals
+func IsInteractive() bool {
+	stat, err := os.Stdin.Stat()
+	if err != nil || (stat.Mode()&os.ModeCharDevice) == 0 {
+		return false
+	}
+	return !IsStdoutPiped()
+}
+
+// formatPickRow formats an instance as a single aligned line for the picker
+func formatPickRow(inst GPUInstanceInfo) string {
+	f := formatInstanceFields(inst, true)
+	gpu := "-"
+	if inst.GPUCount > 0 {
+		gpu = fmt.Sprintf("%dx %s %s", inst.GPUCount, inst.GPUName, f.VRAM)
+	}
+	return fmt.Sprintf("%-28s %-22s %-24s %6s/hr  boot %-6s disk %s", inst.Type, f.Provider, gpu, f.Price, f.Boot, f.Disk)
+}
+
+// pickRowMatches reports whether every space-separated term appears in the row (case-insensitive)
+func pickRowMatches(row, input string) bool {
+	row = strings.ToLower(row)
+	for _, term := range strings.Fields(strings.ToLower(input)) {
+		if !strings.Contains(row, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// pickedTypes joins the types of the picked instances
+func pickedTypes(picked []GPUInstanceInfo) string {
+	types := make([]string, len(picked))
+	for i, inst := range picked {
+		types[i] = inst.Type
+	}
+	return strings.Join(types, ", ")
+}
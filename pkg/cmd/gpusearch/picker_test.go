@@ -0,0 +1,54 @@
+package gpusearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickRowMatches(t *testing.T) {
+	row := formatPickRow(GPUInstanceInfo{Type: "g5.xlarge", Provider: "aws", GPUName: "A10G", GPUCount: 1, VRAMPerGPU: 24, PricePerHour: 1.01})
+
+	assert.True(t, pickRowMatches(row, ""))
+	assert.True(t, pickRowMatches(row, "a10g"))
+	assert.True(t, pickRowMatches(row, "AWS g5"))
+	assert.False(t, pickRowMatches(row, "aws h100"))
+}
+
+func TestFormatPickRowCPU(t *testing.T) {
+	row := formatPickRow(GPUInstanceInfo{Type: "m5.large", Provider: "aws", PricePerHour: 0.1})
+	assert.Contains(t, row, "m5.large")
+	assert.Contains(t, row, "$0.10/hr")
+}
+
+func TestPickedTypes(t *testing.T) {
+	assert.Equal(t, "a, b", pickedTypes([]GPUInstanceInfo{{Type: "a"}, {Type: "b"}}))
+}
+
+func TestPickFlagValidation(t *testing.T) {
+	onPick := func([]GPUInstanceInfo) error { return nil }
+
+	f := sharedFlags{pick: true, jsonOutput: true}
+	_, err := f.outputOptions(false, onPick)
+	assert.Error(t, err)
+
+	f = sharedFlags{pick: true}
+	_, err = f.outputOptions(false, nil)
+	assert.Error(t, err)
+
+	f = sharedFlags{pick: false, output: "csv"}
+	out, err := f.outputOptions(false, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, out.Pick)
+}
+
+func TestDisplayResultsUsesPick(t *testing.T) {
+	var got []GPUInstanceInfo
+	opts := OutputOptions{Format: OutputTable, Pick: func(instances []GPUInstanceInfo) error {
+		got = instances
+		return nil
+	}}
+	instances := []GPUInstanceInfo{{Type: "g5.xlarge"}}
+	assert.NoError(t, DisplayResults(nil, instances, opts, false, false))
+	assert.Equal(t, instances, got)
+}
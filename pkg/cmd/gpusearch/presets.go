@@ -0,0 +1,278 @@
+package gpusearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// PresetStore persists named search presets in the personal settings file
+type PresetStore interface {
+	GetSearchPresets() (map[string]files.SearchPreset, error)
+	SaveSearchPreset(name string, preset files.SearchPreset) error
+	DeleteSearchPresets(names []string) ([]string, error)
+}
+
+// presetFlagNames are the filter and sort flags that presets record and recall
+var presetFlagNames = []string{
+	"gpu-name", "provider", "arch",
+	"min-vram", "min-total-vram", "min-capability", "min-ram", "min-disk", "min-vcpu", "max-boot-time",
+	"stoppable", "rebootable", "flex-ports", "where", "sort", "desc",
+}
+
+var presetNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// PresetFlags holds the --preset and --save-preset flag values
+type PresetFlags struct {
+	Preset     string
+	SavePreset string
+}
+
+// AddPresetFlags registers --preset and --save-preset on a command
+func AddPresetFlags(cmd *cobra.Command, f *PresetFlags) {
+	cmd.Flags().StringVar(&f.Preset, "preset", "", "Apply a saved filter preset (flags given on the command line win)")
+	cmd.Flags().StringVar(&f.SavePreset, "save-preset", "", "Save the filter and sort flags of this command as a named preset")
+}
+
+// Apply loads --preset into flags the user did not set, then saves --save-preset.
+// It must run before the command reads its flag variables. Messages go to stderr
+// so piped output is unaffected.
+func (f PresetFlags) Apply(t *terminal.Terminal, cmd *cobra.Command, s interface{}) error {
+	if f.Preset == "" && f.SavePreset == "" {
+		return nil
+	}
+	store, ok := s.(PresetStore)
+	if !ok {
+		return breverrors.NewValidationError("presets are not supported here")
+	}
+
+	if f.Preset != "" {
+		presets, err := store.GetSearchPresets()
+		if err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		preset, ok := presets[f.Preset]
+		if !ok {
+			return breverrors.NewValidationError(fmt.Sprintf("preset %q not found (see 'brev search presets ls')", f.Preset))
+		}
+		skipped, err := ApplyPreset(cmd.Flags(), preset)
+		if err != nil {
+			return err
+		}
+		for _, name := range skipped {
+			t.Eprintf("Preset %s: --%s is not supported by 'brev %s', ignoring\n", f.Preset, name, cmd.Name())
+		}
+	}
+
+	if f.SavePreset != "" {
+		if !presetNameRegex.MatchString(f.SavePreset) {
+			return breverrors.NewValidationError(fmt.Sprintf("invalid preset name %q: use letters, digits, '.', '_' and '-'", f.SavePreset))
+		}
+		preset := PresetFromFlags(cmd.Flags())
+		if len(preset) == 0 {
+			return breverrors.NewValidationError("--save-preset needs at least one filter or sort flag to save")
+		}
+		if err := store.SaveSearchPreset(f.SavePreset, preset); err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		t.Eprintf("Saved preset %s: %s\n", f.SavePreset, FormatPreset(preset))
+	}
+	return nil
+}
+
+// ApplyPreset sets each preset flag that exists on flags and was not set explicitly.
+// It returns the preset flags that do not exist on flags.
+func ApplyPreset(flags *pflag.FlagSet, preset files.SearchPreset) ([]string, error) {
+	var skipped []string
+	for _, name := range sortedPresetKeys(preset) {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			skipped = append(skipped, name)
+			continue
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := flags.Set(name, preset[name]); err != nil {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("invalid value %q for --%s in preset: %v", preset[name], name, err))
+		}
+	}
+	return skipped, nil
+}
+
+// PresetFromFlags records the preset flags that were set, explicitly or by --preset
+func PresetFromFlags(flags *pflag.FlagSet) files.SearchPreset {
+	preset := files.SearchPreset{}
+	for _, name := range presetFlagNames {
+		if flag := flags.Lookup(name); flag != nil && flag.Changed {
+			preset[name] = flag.Value.String()
+		}
+	}
+	return preset
+}
+
+// FormatPreset renders a preset as command-line flags
+func FormatPreset(preset files.SearchPreset) string {
+	parts := make([]string, 0, len(preset))
+	for _, name := range sortedPresetKeys(preset) {
+		value := preset[name]
+		switch {
+		case value == "true":
+			parts = append(parts, "--"+name)
+		case strings.ContainsAny(value, " \t'\"&|()<>!"):
+			parts = append(parts, fmt.Sprintf("--%s '%s'", name, strings.ReplaceAll(value, "'", `'\''`)))
+		default:
+			parts = append(parts, fmt.Sprintf("--%s %s", name, value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// sortedPresetKeys returns the preset's flag names in the order of presetFlagNames, unknown names last
+func sortedPresetKeys(preset files.SearchPreset) []string {
+	order := make(map[string]int, len(presetFlagNames))
+	for i, name := range presetFlagNames {
+		order[name] = i
+	}
+	keys := make([]string, 0, len(preset))
+	for name := range preset {
+		keys = append(keys, name)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		oi, iok := order[keys[i]]
+		oj, jok := order[keys[j]]
+		if iok != jok {
+			return iok
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// newCmdPresets creates the 'presets' subcommand with ls and rm
+func newCmdPresets(t *terminal.Terminal, store GPUSearchStore) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "presets",
+		DisableFlagsInUseLine: true,
+		Short:                 "Manage saved search presets",
+		Long: `Manage saved search presets.
+
+Presets are saved with --save-preset on 'brev search' or 'brev create' and
+applied with --preset. They are stored in ~/.brev/personal_settings.json.`,
+		Example: `
+  brev search gpu --min-vram 80 --stoppable --provider aws --max-boot-time 10 --save-preset h100-fast
+  brev search --preset h100-fast
+  brev create my-instance --preset h100-fast
+  brev search presets ls
+  brev search presets rm h100-fast
+`,
+	}
+
+	var jsonOutput bool
+	lsCmd := &cobra.Command{
+		Use:                   "ls",
+		Aliases:               []string{"list"},
+		DisableFlagsInUseLine: true,
+		Short:                 "List saved search presets",
+		Args:                  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			presetStore, ok := store.(PresetStore)
+			if !ok {
+				return breverrors.NewValidationError("presets are not supported here")
+			}
+			return RunListPresets(t, presetStore, jsonOutput)
+		},
+	}
+	lsCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output presets as JSON")
+
+	rmCmd := &cobra.Command{
+		Use:                   "rm <name>...",
+		Aliases:               []string{"delete"},
+		DisableFlagsInUseLine: true,
+		Short:                 "Delete saved search presets",
+		Args:                  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			presetStore, ok := store.(PresetStore)
+			if !ok {
+				return breverrors.NewValidationError("presets are not supported here")
+			}
+			return RunRemovePresets(t, presetStore, args)
+		},
+	}
+
+	cmd.AddCommand(lsCmd)
+	cmd.AddCommand(rmCmd)
+	return cmd
+}
+
+// RunListPresets prints the saved presets
+func RunListPresets(t *terminal.Terminal, store PresetStore, jsonOutput bool) error {
+	presets, err := store.GetSearchPresets()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(presets, "", "  ")
+		if err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	if len(presets) == 0 {
+		t.Vprint(t.Yellow("No saved presets. Save one with --save-preset <name> on 'brev search' or 'brev create'."))
+		return nil
+	}
+
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ta := table.NewWriter()
+	ta.SetOutputMirror(os.Stdout)
+	ta.Style().Options = getBrevTableOptions()
+	ta.AppendHeader(table.Row{"NAME", "FLAGS"})
+	for _, name := range names {
+		ta.AppendRow(table.Row{name, FormatPreset(presets[name])})
+	}
+	ta.Render()
+	return nil
+}
+
+// RunRemovePresets deletes the named presets; it fails if any did not exist
+func RunRemovePresets(t *terminal.Terminal, store PresetStore, names []string) error {
+	missing, err := store.DeleteSearchPresets(names)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	missingSet := map[string]bool{}
+	for _, name := range missing {
+		missingSet[name] = true
+	}
+	for _, name := range names {
+		if !missingSet[name] {
+			t.Vprintf("Deleted preset %s\n", name)
+		}
+	}
+	if len(missing) > 0 {
+		return breverrors.NewValidationError(fmt.Sprintf("preset(s) not found: %s", strings.Join(missing, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package gpusearch
+
+import (
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPresetStore struct {
+	MockGPUSearchStore
+	presets map[string]files.SearchPreset
+}
+
+func (m *mockPresetStore) GetSearchPresets() (map[string]files.SearchPreset, error) {
+	return m.presets, nil
+}
+
+func (m *mockPresetStore) SaveSearchPreset(name string, preset files.SearchPreset) error {
+	m.presets[name] = preset
+	return nil
+}
+
+func (m *mockPresetStore) DeleteSearchPresets(names []string) ([]string, error) {
+	var missing []string
+	for _, name := range names {
+		if _, ok := m.presets[name]; !ok {
+			missing = append(missing, name)
+			continue
+		}
+		delete(m.presets, name)
+	}
+	return missing, nil
+}
+
+func newPresetTestCmd(f *sharedFlags) *cobra.Command {
+	cmd := &cobra.Command{Use: "search"}
+	cmd.Flags().StringP("gpu-name", "g", "", "")
+	cmd.Flags().Float64("min-vram", 0, "")
+	addSharedFlags(cmd, f)
+	return cmd
+}
+
+func TestApplyPresetKeepsExplicitFlags(t *testing.T) {
+	var f sharedFlags
+	cmd := newPresetTestCmd(&f)
+	require.NoError(t, cmd.ParseFlags([]string{"--provider", "gcp"}))
+
+	skipped, err := ApplyPreset(cmd.Flags(), files.SearchPreset{
+		"provider":  "aws",
+		"min-vram":  "80",
+		"stoppable": "true",
+		"bogus":     "1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bogus"}, skipped)
+	assert.Equal(t, "gcp", f.provider)
+	assert.True(t, f.stoppable)
+	v, _ := cmd.Flags().GetFloat64("min-vram")
+	assert.Equal(t, 80.0, v)
+}
+
+func TestApplyPresetInvalidValue(t *testing.T) {
+	var f sharedFlags
+	cmd := newPresetTestCmd(&f)
+	_, err := ApplyPreset(cmd.Flags(), files.SearchPreset{"min-vram": "lots"})
+	assert.Error(t, err)
+}
+
+func TestPresetFromFlags(t *testing.T) {
+	var f sharedFlags
+	cmd := newPresetTestCmd(&f)
+	require.NoError(t, cmd.ParseFlags([]string{"--min-vram", "80", "--stoppable", "--json", "--where", "price<4"}))
+
+	preset := PresetFromFlags(cmd.Flags())
+	assert.Equal(t, files.SearchPreset{"min-vram": "80", "stoppable": "true", "where": "price<4"}, preset)
+}
+
+func TestFormatPreset(t *testing.T) {
+	preset := files.SearchPreset{"stoppable": "true", "provider": "aws", "min-vram": "80", "where": "price<4 && vram>=80"}
+	assert.Equal(t, "--provider aws --min-vram 80 --stoppable --where 'price<4 && vram>=80'", FormatPreset(preset))
+}
+
+func TestPresetFlagsApplySaveAndRecall(t *testing.T) {
+	store := &mockPresetStore{presets: map[string]files.SearchPreset{}}
+	term := terminal.New()
+
+	var f sharedFlags
+	cmd := newPresetTestCmd(&f)
+	require.NoError(t, cmd.ParseFlags([]string{"--min-vram", "80", "--provider", "aws", "--save-preset", "h100-fast"}))
+	require.NoError(t, f.presets.Apply(term, cmd, store))
+	assert.Equal(t, files.SearchPreset{"min-vram": "80", "provider": "aws"}, store.presets["h100-fast"])
+
+	var g sharedFlags
+	cmd = newPresetTestCmd(&g)
+	require.NoError(t, cmd.ParseFlags([]string{"--preset", "h100-fast", "--max-boot-time", "10"}))
+	require.NoError(t, g.presets.Apply(term, cmd, store))
+	assert.Equal(t, "aws", g.provider)
+	assert.Equal(t, 10, g.maxBootTime)
+}
+
+func TestPresetFlagsApplyErrors(t *testing.T) {
+	store := &mockPresetStore{presets: map[string]files.SearchPreset{}}
+	term := terminal.New()
+
+	var f sharedFlags
+	cmd := newPresetTestCmd(&f)
+	require.NoError(t, cmd.ParseFlags([]string{"--preset", "missing"}))
+	assert.Error(t, f.presets.Apply(term, cmd, store))
+
+	f = sharedFlags{}
+	cmd = newPresetTestCmd(&f)
+	require.NoError(t, cmd.ParseFlags([]string{"--save-preset", "bad name", "--stoppable"}))
+	assert.Error(t, f.presets.Apply(term, cmd, store))
+
+	f = sharedFlags{}
+	cmd = newPresetTestCmd(&f)
+	require.NoError(t, cmd.ParseFlags([]string{"--save-preset", "empty", "--json"}))
+	assert.Error(t, f.presets.Apply(term, cmd, store))
+
+	f = sharedFlags{}
+	cmd = newPresetTestCmd(&f)
+	require.NoError(t, cmd.ParseFlags([]string{"--preset", "x"}))
+	assert.Error(t, f.presets.Apply(term, cmd, &MockGPUSearchStore{}))
+}
+
+func TestRunRemovePresets(t *testing.T) {
+	store := &mockPresetStore{presets: map[string]files.SearchPreset{"a": {"stoppable": "true"}, "b": {"provider": "aws"}}}
+	term := terminal.New()
+
+	require.NoError(t, RunRemovePresets(term, store, []string{"a"}))
+	assert.NotContains(t, store.presets, "a")
+
+	err := RunRemovePresets(term, store, []string{"b", "nope"})
+	assert.Error(t, err)
+	assert.Empty(t, store.presets)
+}
@@ -0,0 +1,601 @@
+package gpusearch
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+)
+
+// whereFieldKind is the value type of a field addressable from a --where expression
+type whereFieldKind int
+
+const (
+	whereNumber whereFieldKind = iota
+	whereString
+	whereBool
+)
+
+// whereField describes a GPUInstanceInfo field addressable from a --where expression
+type whereField struct {
+	kind whereFieldKind
+	num  func(GPUInstanceInfo) float64
+	str  func(GPUInstanceInfo) string
+	flag func(GPUInstanceInfo) bool
+}
+
+func numField(fn func(GPUInstanceInfo) float64) whereField {
+	return whereField{kind: whereNumber, num: fn}
+}
+
+func strField(fn func(GPUInstanceInfo) string) whereField {
+	return whereField{kind: whereString, str: fn}
+}
+
+func boolField(fn func(GPUInstanceInfo) bool) whereField {
+	return whereField{kind: whereBool, flag: fn}
+}
+
+// whereFields maps expression identifiers to GPUInstanceInfo fields.
+// Sizes are in GB, boot_time is in minutes and prices are in $/hour.
+var whereFields = map[string]whereField{
+	"type":            strField(func(i GPUInstanceInfo) string { return i.Type }),
+	"provider":        strField(func(i GPUInstanceInfo) string { return i.Provider }),
+	"cloud":           strField(func(i GPUInstanceInfo) string { return i.Cloud }),
+	"gpu":             strField(func(i GPUInstanceInfo) string { return i.GPUName }),
+	"arch":            strField(func(i GPUInstanceInfo) string { return i.Arch }),
+	"gpu_count":       numField(func(i GPUInstanceInfo) float64 { return float64(i.GPUCount) }),
+	"vram":            numField(func(i GPUInstanceInfo) float64 { return i.VRAMPerGPU }),
+	"total_vram":      numField(func(i GPUInstanceInfo) float64 { return i.TotalVRAM }),
+	"capability":      numField(func(i GPUInstanceInfo) float64 { return i.Capability }),
+	"vcpu":            numField(func(i GPUInstanceInfo) float64 { return float64(i.VCPUs) }),
+	"ram":             numField(func(i GPUInstanceInfo) float64 { return i.RAMInGB }),
+	"disk":            numField(func(i GPUInstanceInfo) float64 { return i.DiskMax }),
+	"disk_min":        numField(func(i GPUInstanceInfo) float64 { return i.DiskMin }),
+	"boot_time":       numField(func(i GPUInstanceInfo) float64 { return float64(i.BootTime) / 60 }),
+	"price":           numField(func(i GPUInstanceInfo) float64 { return i.PricePerHour }),
+	"price_per_gpu":   numField(func(i GPUInstanceInfo) float64 { return i.PricePerGPU }),
+	"price_per_vram":  numField(func(i GPUInstanceInfo) float64 { return i.PricePerVRAM }),
+	"price_per_tflop": numField(func(i GPUInstanceInfo) float64 { return i.PricePerTFLOP }),
+	"fp16_tflops":     numField(func(i GPUInstanceInfo) float64 { return i.FP16TFLOPS }),
+	"mem_bandwidth":   numField(func(i GPUInstanceInfo) float64 { return i.MemBandwidth }),
+	"stoppable":       boolField(func(i GPUInstanceInfo) bool { return i.Stoppable }),
+	"rebootable":      boolField(func(i GPUInstanceInfo) bool { return i.Rebootable }),
+	"flex_ports":      boolField(func(i GPUInstanceInfo) bool { return i.FlexPorts }),
+}
+
+// whereFieldAliases maps alternate spellings to canonical field names
+var whereFieldAliases = map[string]string{
+	"count":    "gpu_count",
+	"gpus":     "gpu_count",
+	"gpu_name": "gpu",
+	"vcpus":    "vcpu",
+	"memory":   "ram",
+}
+
+// WhereFieldNames returns the sorted list of identifiers usable in --where expressions
+func WhereFieldNames() []string {
+	names := make([]string, 0, len(whereFields))
+	for name := range whereFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WhereExpr is a parsed --where expression that can be evaluated against instances
+type WhereExpr struct {
+	source string
+	root   whereNode
+}
+
+// String returns the original expression text
+func (w *WhereExpr) String() string {
+	if w == nil {
+		return ""
+	}
+	return w.source
+}
+
+// Match reports whether an instance satisfies the expression. A nil expression matches everything.
+func (w *WhereExpr) Match(inst GPUInstanceInfo) bool {
+	if w == nil || w.root == nil {
+		return true
+	}
+	return w.root.eval(inst)
+}
+
+// FilterWhere returns the instances matching the expression, preserving order
+func FilterWhere(instances []GPUInstanceInfo, expr *WhereExpr) []GPUInstanceInfo {
+	if expr == nil {
+		return instances
+	}
+	var filtered []GPUInstanceInfo
+	for _, inst := range instances {
+		if expr.Match(inst) {
+			filtered = append(filtered, inst)
+		}
+	}
+	return filtered
+}
+
+// WhereSyntaxError reports a parse failure at a byte offset within the expression
+type WhereSyntaxError struct {
+	Expr    string
+	Pos     int
+	Message string
+}
+
+func (e *WhereSyntaxError) Error() string {
+	return fmt.Sprintf("invalid --where expression at position %d: %s\n  %s\n  %s^", e.Pos+1, e.Message, e.Expr, strings.Repeat(" ", e.Pos))
+}
+
+// ParseWhere parses a filter expression such as
+//
+//	vram>=80 && provider in ("aws","gcp") && price<4 && stoppable
+//
+// An empty expression returns a nil *WhereExpr, which matches everything.
+func ParseWhere(expr string) (*WhereExpr, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	tokens, err := lexWhere(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &whereParser{expr: expr, tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %s", tok.describe()))
+	}
+	return &WhereExpr{source: expr, root: root}, nil
+}
+
+// ParseWhereFlag parses a --where flag value and wraps syntax errors as validation errors
+func ParseWhereFlag(expr string) (*WhereExpr, error) {
+	w, err := ParseWhere(expr)
+	if err != nil {
+		return nil, breverrors.NewValidationError(err.Error())
+	}
+	return w, nil
+}
+
+// --- lexer ---
+
+type whereTokenKind int
+
+const (
+	tokEOF whereTokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type whereToken struct {
+	kind whereTokenKind
+	text string
+	pos  int
+}
+
+func (t whereToken) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+func isIdentStart(r byte) bool {
+	return r == '_' || unicode.IsLetter(rune(r))
+}
+
+func isIdentChar(r byte) bool {
+	return isIdentStart(r) || r == '-' || r == '.' || unicode.IsDigit(rune(r))
+}
+
+func lexWhere(expr string) ([]whereToken, error) { //nolint:gocyclo,funlen // straightforward lexer switch
+	var tokens []whereToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, whereToken{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, whereToken{tokRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, whereToken{tokComma, ",", i})
+			i++
+		case c == '&' || c == '|':
+			if i+1 >= len(expr) || expr[i+1] != c {
+				return nil, &WhereSyntaxError{Expr: expr, Pos: i, Message: fmt.Sprintf("expected %q", string([]byte{c, c}))}
+			}
+			kind := tokAnd
+			if c == '|' {
+				kind = tokOr
+			}
+			tokens = append(tokens, whereToken{kind, expr[i : i+2], i})
+			i += 2
+		case c == '!' && (i+1 >= len(expr) || expr[i+1] != '='):
+			tokens = append(tokens, whereToken{tokNot, "!", i})
+			i++
+		case strings.ContainsRune("<>=!~", rune(c)):
+			start := i
+			i++
+			if i < len(expr) && expr[i] == '=' {
+				i++
+			}
+			op := expr[start:i]
+			if op == "!" || op == "~=" {
+				return nil, &WhereSyntaxError{Expr: expr, Pos: start, Message: fmt.Sprintf("unknown operator %q", op)}
+			}
+			tokens = append(tokens, whereToken{tokOp, op, start})
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			var sb strings.Builder
+			for i < len(expr) && expr[i] != c {
+				if expr[i] == '\\' && i+1 < len(expr) {
+					i++
+				}
+				sb.WriteByte(expr[i])
+				i++
+			}
+			if i >= len(expr) {
+				return nil, &WhereSyntaxError{Expr: expr, Pos: start, Message: "unterminated string"}
+			}
+			i++
+			tokens = append(tokens, whereToken{tokString, sb.String(), start})
+		case unicode.IsDigit(rune(c)) || c == '.' || (c == '-' && i+1 < len(expr) && unicode.IsDigit(rune(expr[i+1]))):
+			start := i
+			i++
+			for i < len(expr) && (unicode.IsDigit(rune(expr[i])) || expr[i] == '.') {
+				i++
+			}
+			if _, err := strconv.ParseFloat(expr[start:i], 64); err != nil {
+				return nil, &WhereSyntaxError{Expr: expr, Pos: start, Message: fmt.Sprintf("invalid number %q", expr[start:i])}
+			}
+			tokens = append(tokens, whereToken{tokNumber, expr[start:i], start})
+		case isIdentStart(c):
+			start := i
+			for i < len(expr) && isIdentChar(expr[i]) {
+				i++
+			}
+			word := expr[start:i]
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, whereToken{tokAnd, word, start})
+			case "or":
+				tokens = append(tokens, whereToken{tokOr, word, start})
+			case "not":
+				tokens = append(tokens, whereToken{tokNot, word, start})
+			default:
+				tokens = append(tokens, whereToken{tokIdent, word, start})
+			}
+		default:
+			return nil, &WhereSyntaxError{Expr: expr, Pos: i, Message: fmt.Sprintf("unexpected character %q", string(c))}
+		}
+	}
+	tokens = append(tokens, whereToken{tokEOF, "", len(expr)})
+	return tokens, nil
+}
+
+// --- parser ---
+
+type whereParser struct {
+	expr   string
+	tokens []whereToken
+	pos    int
+}
+
+func (p *whereParser) peek() whereToken {
+	return p.tokens[p.pos]
+}
+
+func (p *whereParser) next() whereToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *whereParser) errorAt(tok whereToken, msg string) error {
+	return &WhereSyntaxError{Expr: p.expr, Pos: tok.pos, Message: msg}
+}
+
+func (p *whereParser) parseOr() (whereNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseAnd() (whereNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *whereParser) parseUnary() (whereNode, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *whereParser) parsePrimary() (whereNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorAt(closing, fmt.Sprintf("expected \")\" but found %s", closing.describe()))
+		}
+		return inner, nil
+	case tokIdent:
+		return p.parseComparison(tok)
+	default:
+		return nil, p.errorAt(tok, fmt.Sprintf("expected a field name but found %s", tok.describe()))
+	}
+}
+
+func (p *whereParser) lookupField(tok whereToken) (whereField, error) {
+	name := strings.ReplaceAll(strings.ToLower(tok.text), "-", "_")
+	if alias, ok := whereFieldAliases[name]; ok {
+		name = alias
+	}
+	field, ok := whereFields[name]
+	if !ok {
+		return whereField{}, p.errorAt(tok, fmt.Sprintf("unknown field %q (valid fields: %s)", tok.text, strings.Join(WhereFieldNames(), ", ")))
+	}
+	return field, nil
+}
+
+func (p *whereParser) parseComparison(ident whereToken) (whereNode, error) {
+	field, err := p.lookupField(ident)
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	switch {
+	case op.kind == tokIdent && strings.EqualFold(op.text, "in"):
+		p.next()
+		return p.parseIn(ident, field, false)
+	case op.kind == tokNot && strings.EqualFold(op.text, "not") && p.tokens[p.pos+1].kind == tokIdent && strings.EqualFold(p.tokens[p.pos+1].text, "in"):
+		p.next()
+		p.next()
+		return p.parseIn(ident, field, true)
+	case op.kind != tokOp:
+		if field.kind == whereBool {
+			return boolNode{field: field}, nil
+		}
+		return nil, p.errorAt(op, fmt.Sprintf("expected an operator after %q but found %s", ident.text, op.describe()))
+	}
+	p.next()
+
+	value := p.next()
+	switch field.kind {
+	case whereNumber:
+		return p.numberComparison(op, value, field)
+	case whereString:
+		return p.stringComparison(ident, op, value, field)
+	default:
+		return p.boolComparison(ident, op, value, field)
+	}
+}
+
+func (p *whereParser) numberComparison(op, value whereToken, field whereField) (whereNode, error) {
+	if op.text == "~" {
+		return nil, p.errorAt(op, "operator \"~\" only applies to text fields")
+	}
+	if value.kind != tokNumber {
+		return nil, p.errorAt(value, fmt.Sprintf("expected a number but found %s", value.describe()))
+	}
+	n, _ := strconv.ParseFloat(value.text, 64)
+	return numCompareNode{field: field, op: op.text, value: n}, nil
+}
+
+func (p *whereParser) stringComparison(ident, op, value whereToken, field whereField) (whereNode, error) {
+	switch op.text {
+	case "=", "==", "!=", "~":
+	default:
+		return nil, p.errorAt(op, fmt.Sprintf("operator %q is not supported for text field %q (use =, !=, ~ or in)", op.text, ident.text))
+	}
+	if value.kind != tokString && value.kind != tokIdent && value.kind != tokNumber {
+		return nil, p.errorAt(value, fmt.Sprintf("expected a value but found %s", value.describe()))
+	}
+	return strCompareNode{field: field, op: op.text, value: strings.ToLower(value.text)}, nil
+}
+
+func (p *whereParser) boolComparison(ident, op, value whereToken, field whereField) (whereNode, error) {
+	if op.text != "=" && op.text != "==" && op.text != "!=" {
+		return nil, p.errorAt(op, fmt.Sprintf("operator %q is not supported for boolean field %q", op.text, ident.text))
+	}
+	b, err := strconv.ParseBool(strings.ToLower(value.text))
+	if err != nil || (value.kind != tokIdent && value.kind != tokNumber) {
+		return nil, p.errorAt(value, fmt.Sprintf("expected true or false but found %s", value.describe()))
+	}
+	if op.text == "!=" {
+		b = !b
+	}
+	return boolNode{field: field, want: &b}, nil
+}
+
+func (p *whereParser) parseIn(ident whereToken, field whereField, negate bool) (whereNode, error) {
+	if open := p.next(); open.kind != tokLParen {
+		return nil, p.errorAt(open, fmt.Sprintf("expected \"(\" after in but found %s", open.describe()))
+	}
+	node := inNode{field: field, negate: negate}
+	for {
+		value := p.next()
+		switch {
+		case field.kind == whereNumber && value.kind == tokNumber:
+			n, _ := strconv.ParseFloat(value.text, 64)
+			node.nums = append(node.nums, n)
+		case field.kind == whereString && (value.kind == tokString || value.kind == tokIdent || value.kind == tokNumber):
+			node.strs = append(node.strs, strings.ToLower(value.text))
+		case field.kind == whereBool:
+			return nil, p.errorAt(ident, fmt.Sprintf("in is not supported for boolean field %q", ident.text))
+		default:
+			return nil, p.errorAt(value, fmt.Sprintf("expected a value in list but found %s", value.describe()))
+		}
+		sep := p.next()
+		if sep.kind == tokRParen {
+			return node, nil
+		}
+		if sep.kind != tokComma {
+			return nil, p.errorAt(sep, fmt.Sprintf("expected \",\" or \")\" but found %s", sep.describe()))
+		}
+	}
+}
+
+// --- evaluation ---
+
+type whereNode interface {
+	eval(inst GPUInstanceInfo) bool
+}
+
+type andNode struct{ left, right whereNode }
+
+func (n andNode) eval(inst GPUInstanceInfo) bool { return n.left.eval(inst) && n.right.eval(inst) }
+
+type orNode struct{ left, right whereNode }
+
+func (n orNode) eval(inst GPUInstanceInfo) bool { return n.left.eval(inst) || n.right.eval(inst) }
+
+type notNode struct{ inner whereNode }
+
+func (n notNode) eval(inst GPUInstanceInfo) bool { return !n.inner.eval(inst) }
+
+type boolNode struct {
+	field whereField
+	want  *bool // nil means "is true"
+}
+
+func (n boolNode) eval(inst GPUInstanceInfo) bool {
+	v := n.field.flag(inst)
+	if n.want == nil {
+		return v
+	}
+	return v == *n.want
+}
+
+type numCompareNode struct {
+	field whereField
+	op    string
+	value float64
+}
+
+func (n numCompareNode) eval(inst GPUInstanceInfo) bool {
+	v := n.field.num(inst)
+	switch n.op {
+	case "<":
+		return v < n.value
+	case "<=":
+		return v <= n.value
+	case ">":
+		return v > n.value
+	case ">=":
+		return v >= n.value
+	case "!=":
+		return v != n.value
+	default: // "=", "=="
+		return v == n.value
+	}
+}
+
+type strCompareNode struct {
+	field whereField
+	op    string
+	value string
+}
+
+func (n strCompareNode) eval(inst GPUInstanceInfo) bool {
+	v := strings.ToLower(n.field.str(inst))
+	switch n.op {
+	case "~":
+		return strings.Contains(v, n.value)
+	case "!=":
+		return v != n.value
+	default: // "=", "=="
+		return v == n.value
+	}
+}
+
+type inNode struct {
+	field  whereField
+	negate bool
+	nums   []float64
+	strs   []string
+}
+
+func (n inNode) eval(inst GPUInstanceInfo) bool {
+	found := false
+	if n.field.kind == whereNumber {
+		v := n.field.num(inst)
+		for _, candidate := range n.nums {
+			if v == candidate {
+				found = true
+				break
+			}
+		}
+	} else {
+		v := strings.ToLower(n.field.str(inst))
+		for _, candidate := range n.strs {
+			if v == candidate {
+				found = true
+				break
+			}
+		}
+	}
+	return found != n.negate
+}
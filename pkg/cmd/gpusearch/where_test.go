@@ -0,0 +1,91 @@
+package gpusearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func whereTypes(t *testing.T, expr string) []string {
+	t.Helper()
+	w, err := ParseWhere(expr)
+	assert.NoError(t, err)
+	var types []string
+	for _, inst := range FilterWhere(ProcessInstances(createTestInstanceTypes().Items), w) {
+		types = append(types, inst.Type)
+	}
+	return types
+}
+
+func TestParseWhereEmpty(t *testing.T) {
+	w, err := ParseWhere("   ")
+	assert.NoError(t, err)
+	assert.Nil(t, w)
+	assert.True(t, w.Match(GPUInstanceInfo{}))
+}
+
+func TestWhereNumericComparisons(t *testing.T) {
+	assert.ElementsMatch(t, []string{"p4d.24xlarge"}, whereTypes(t, "vram>=40"))
+	assert.ElementsMatch(t, []string{"g4dn.xlarge", "g6.xlarge"}, whereTypes(t, "price < 1"))
+	assert.ElementsMatch(t, []string{"p3.8xlarge", "p4d.24xlarge"}, whereTypes(t, "gpu_count != 1"))
+	assert.ElementsMatch(t, []string{"p3.8xlarge"}, whereTypes(t, "count in (2, 4)"))
+}
+
+func TestWhereStringComparisons(t *testing.T) {
+	assert.ElementsMatch(t, []string{"p3.2xlarge", "p3.8xlarge"}, whereTypes(t, `gpu = "v100"`))
+	assert.ElementsMatch(t, []string{"g5.xlarge", "g5.2xlarge", "p4d.24xlarge"}, whereTypes(t, "gpu ~ a1"))
+	assert.ElementsMatch(t, []string{"g5.xlarge", "g6.xlarge"}, whereTypes(t, `type in ("g5.xlarge", 'g6.xlarge')`))
+	assert.ElementsMatch(t, []string{"g4dn.xlarge"}, whereTypes(t, "type=g4dn.xlarge"))
+}
+
+func TestWhereBooleanLogic(t *testing.T) {
+	assert.ElementsMatch(t, []string{"g4dn.xlarge", "p4d.24xlarge"},
+		whereTypes(t, "gpu ~ t4 || (gpu ~ a100 && gpu_count>=8)"))
+	assert.ElementsMatch(t, []string{"g5.xlarge", "g5.2xlarge"},
+		whereTypes(t, "vram >= 24 and not gpu in (l4, a100)"))
+	assert.ElementsMatch(t, []string{"g5.xlarge", "g5.2xlarge"},
+		whereTypes(t, "vram >= 24 && gpu not in (l4, a100)"))
+	assert.Empty(t, whereTypes(t, "stoppable"))
+	assert.Len(t, whereTypes(t, "!stoppable && rebootable == false"), 7)
+}
+
+func TestWhereSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		pos     int
+		message string
+	}{
+		{"unknown field", "vram>=80 && colour=red", 13, `unknown field "colour"`},
+		{"missing value", "vram>=", 7, "expected a number"},
+		{"single ampersand", "vram>=80 & stoppable", 10, `expected "&&"`},
+		{"string for number", `price < "cheap"`, 9, "expected a number"},
+		{"ordering on text", "provider > aws", 10, "not supported for text field"},
+		{"unterminated string", `provider = "aws`, 12, "unterminated string"},
+		{"unclosed paren", "(vram>=80", 10, `expected ")"`},
+		{"bad list separator", `provider in ("aws" "gcp")`, 20, `expected "," or ")"`},
+		{"trailing token", "stoppable rebootable", 11, "unexpected"},
+		{"non-bool without operator", "vram", 5, "expected an operator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWhere(tt.expr)
+			if !assert.Error(t, err) {
+				return
+			}
+			var syntaxErr *WhereSyntaxError
+			assert.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.pos, syntaxErr.Pos+1, "position mismatch for %q", tt.expr)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestWhereSyntaxErrorCaret(t *testing.T) {
+	_, err := ParseWhere("vram>=80 && bogus")
+	assert.EqualError(t, err, "invalid --where expression at position 13: unknown field \"bogus\" (valid fields: "+
+		"arch, boot_time, capability, cloud, disk, disk_min, flex_ports, fp16_tflops, gpu, gpu_count, mem_bandwidth, "+
+		"price, price_per_gpu, price_per_tflop, price_per_vram, provider, ram, rebootable, stoppable, total_vram, type, "+
+		"vcpu, vram)\n  vram>=80 && bogus\n              ^")
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 
 	nodev1 "buf.build/gen/go/brevdev/devplane/protocolbuffers/go/devplaneapi/v1"
@@ -51,6 +53,7 @@ func NewCmdLs(t *terminal.Terminal, loginLsStore LsStore, noLoginLsStore LsStore
 	var showAll bool
 	var org string
 	var jsonOutput bool
+	var opts LsOptions
 
 	cmd := &cobra.Command{
 		Annotations: map[string]string{"workspace": ""},
@@ -65,12 +68,48 @@ Subcommands:
   orgs       List organizations
 
 When stdout is piped, outputs instance names only (one per line) for easy chaining
-with other commands like stop, start, or delete.`,
+with other commands like stop, start, or delete.
+
+Selectors narrow the instances listed, in both the table and --json output:
+  --status RUNNING,STOPPED   instance status
+  --created-by me|<email>    creator; searches the whole org, like --all
+  --gpu H100 --type <type>   GPU or instance type
+  --name-regex '^train-'     instance name
+  --older-than 48h           created more than 48h (or e.g. 7d) ago
+  -l team=ml,env!=prod       labels set with 'brev create --label'
+Label selectors are key=value, key!=value, key (has the label) or !key.
+
+--watch keeps listing, polling less often while nothing changes. On a terminal
+it redraws the table in place; when piped it prints one line per status change,
+e.g. "train-03 DEPLOYING → RUNNING". --until stops watching once a condition
+holds:
+  --until RUNNING           all selected instances are RUNNING
+  --until all=RUNNING|STOPPED
+  --until any=FAILURE       at least one selected instance failed
+  --until none              no instance is selected any more, e.g. all deleted
+
+-o picks the output format:
+  -o json                   same as --json
+  -o name                   one instance name per line, e.g. to pipe into brev stop
+  -o custom-columns=NAME:.name,GPU:.gpu,DNS:.dns
+  -o template='{{.name}} {{.labels.team}}'
+Fields are the instance's JSON fields (e.g. .name, .dns, .instanceType,
+.labels.team, .firewallRules[0].port), plus .gpu, .kind (gpu or cpu), .machine,
+.shellStatus and .hourlyPrice. .status is the status the table shows.
+
+The $/hr column shows each instance's catalog price, and the footer the hourly
+burn rate of all RUNNING instances in the org.`,
 		Example: `
   brev ls
   brev ls instances
   brev ls nodes
   brev ls --json
+  brev ls --status RUNNING --gpu H100
+  brev ls --created-by alice@example.com --older-than 7d
+  brev ls -l team=ml --json
+  brev ls --watch --name-regex '^train-' --until RUNNING
+  brev ls -o custom-columns=NAME:.name,GPU:.gpu,DNS:.dns
+  brev ls --status RUNNING -o name | brev stop
   brev ls | grep running | brev stop
   brev ls orgs
   brev ls orgs --json
@@ -91,7 +130,14 @@ with other commands like stop, start, or delete.`,
 			if err != nil {
 				return breverrors.WrapAndTrace(err)
 			}
-			err = RunLs(t, cliAuth, loginLsStore, args, org, showAll, jsonOutput)
+			org, opts.Output, err = resolveOutputFlag(t, cliAuth, loginLsStore, org, opts.Output)
+			if err != nil {
+				return err
+			}
+			if opts.Output == outputJSON {
+				jsonOutput = true
+			}
+			err = RunLs(t, cliAuth, loginLsStore, args, org, showAll, jsonOutput, opts)
 			if err != nil {
 				return breverrors.WrapAndTrace(err)
 			}
@@ -102,7 +148,8 @@ with other commands like stop, start, or delete.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&org, "org", "o", "", "organization (will override active org)")
+	cmd.Flags().StringVar(&org, "org", "", "organization (will override active org)")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", OutputHelp+" (-o was --org; a value that is not a format is still taken as the org, for now)")
 	err := cmd.RegisterFlagCompletionFunc("org", completions.GetOrgsNameCompletionHandler(noLoginLsStore, t))
 	if err != nil {
 		breverrors.GetDefaultErrorReporter().ReportError(breverrors.WrapAndTrace(err))
@@ -111,10 +158,48 @@ with other commands like stop, start, or delete.`,
 
 	cmd.Flags().BoolVar(&showAll, "all", false, "show all instances and external nodes in org")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	AddSelectorFlags(cmd, &opts.Selector)
+	cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false, "keep watching the instances for changes")
+	cmd.Flags().StringVar(&opts.Until, "until", "", "with --watch, stop once a condition holds: STATUS, all=STATUS, any=STATUS or none")
 
 	return cmd
 }
 
+// resolveOutputFlag keeps 'brev ls -o <org>' working: -o used to be --org, so
+// a value that is not an output format is taken as the org, with a warning. A
+// value that is both an output format and an org name is rejected as ambiguous.
+func resolveOutputFlag(t *terminal.Terminal, cliAuth auth.CLIAuth, lsStore LsStore, org, output string) (string, string, error) {
+	if output == "" {
+		return org, output, nil
+	}
+	if IsOutputFormat(output) {
+		if org == "" && !cliAuth.IsAPIKey() && isOrgName(lsStore, output) {
+			return "", "", breverrors.NewValidationError(fmt.Sprintf("-o %[1]q is both an output format and an org name, and -o now means --output; use --org %[1]s to list that org, or pass --org with -o %[1]s", output))
+		}
+		return org, output, nil
+	}
+	if org != "" {
+		return "", "", breverrors.NewValidationError(fmt.Sprintf("unknown output format %q; use %s", output, strings.TrimPrefix(OutputHelp, "output format: ")))
+	}
+	t.Eprintf("%s", t.Yellow("-o for --org is deprecated and will be removed; use --org %s\n", output))
+	return output, "", nil
+}
+
+// isOrgName returns true if one of the user's orgs is called name. Lookup
+// errors are ignored: the value is then used as the output format.
+func isOrgName(lsStore LsStore, name string) bool {
+	orgs, err := lsStore.GetOrganizations(&store.GetOrganizationsOptions{Name: name})
+	if err != nil {
+		return false
+	}
+	for _, o := range orgs {
+		if strings.EqualFold(o.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // trackLsAnalytics sends analytics event for ls command
 func trackLsAnalytics(cliAuth auth.CLIAuth) {
 	userID := ""
@@ -171,8 +256,44 @@ func getOrgForRunLs(cliAuth auth.CLIAuth, lsStore LsStore, orgflag string) (*ent
 	return org, nil
 }
 
-func RunLs(t *terminal.Terminal, cliAuth auth.CLIAuth, lsStore LsStore, args []string, orgflag string, showAll bool, jsonOutput bool) error {
+// LsOptions holds the 'brev ls' flags for listing instances
+type LsOptions struct {
+	Selector SelectorFlags
+	Watch    bool
+	Until    string
+	Output   string // -o; json is passed as jsonOutput
+}
+
+func RunLs(t *terminal.Terminal, cliAuth auth.CLIAuth, lsStore LsStore, args []string, orgflag string, showAll bool, jsonOutput bool, opts LsOptions) error {
 	ls := NewLs(lsStore, t, jsonOutput)
+	ls.selectorFlags = opts.Selector
+	until, err := parseUntil(opts.Until)
+	if err != nil {
+		return err
+	}
+	ls.output, err = parseOutputFormat(opts.Output)
+	if err != nil {
+		return err
+	}
+	listsInstances := len(args) == 0 || classifyLsArg(args[0]) == lsArgInstances || classifyLsArg(args[0]) == lsArgWorkspaces
+	if ls.output != nil {
+		if jsonOutput {
+			return breverrors.NewValidationError("-o cannot be used with --json")
+		}
+		if !listsInstances {
+			return breverrors.NewValidationError("-o only works for instances")
+		}
+	}
+	if opts.Watch {
+		if jsonOutput || ls.output != nil {
+			return breverrors.NewValidationError("--watch cannot be used with --json or -o")
+		}
+		if !listsInstances {
+			return breverrors.NewValidationError("--watch only works for instances")
+		}
+	} else if until != nil {
+		return breverrors.NewValidationError("--until needs --watch")
+	}
 
 	org, err := getOrgForRunLs(cliAuth, lsStore, orgflag)
 	if err != nil {
@@ -181,6 +302,9 @@ func RunLs(t *terminal.Terminal, cliAuth auth.CLIAuth, lsStore LsStore, args []s
 	if len(args) > 1 {
 		return breverrors.NewValidationError("too many args provided")
 	}
+	if opts.Watch {
+		return ls.WatchWorkspaces(cliAuth, org, showAll, until)
+	}
 
 	if len(args) == 1 { //nolint:gocritic // don't want to switch
 		err = handleLsArg(ls, cliAuth, args[0], org, showAll)
@@ -278,10 +402,12 @@ func wrapLsRun(err error) error {
 }
 
 type Ls struct {
-	lsStore    LsStore
-	terminal   *terminal.Terminal
-	jsonOutput bool
-	piped      bool
+	lsStore       LsStore
+	terminal      *terminal.Terminal
+	jsonOutput    bool
+	piped         bool
+	selectorFlags SelectorFlags
+	output        *outputFormat // -o other than json; nil prints the table
 }
 
 func NewLs(lsStore LsStore, terminal *terminal.Terminal, jsonOutput bool) *Ls {
@@ -382,28 +508,22 @@ func (ls Ls) RunUser(_ bool) error {
 	return nil
 }
 
-func (ls Ls) ShowAllWorkspaces(org *entity.Organization, otherOrgs []entity.Organization, workspacesToShow []entity.Workspace, gpuLookup map[string]string) {
-	ls.displayWorkspacesAndHelp(org, otherOrgs, workspacesToShow, workspacesToShow, true, gpuLookup)
+func (ls Ls) ShowAllWorkspaces(org *entity.Organization, otherOrgs []entity.Organization, workspacesToShow []entity.Workspace, gpuLookup map[string]string, prices map[string]float64) {
+	ls.displayWorkspacesAndHelp(org, otherOrgs, workspacesToShow, workspacesToShow, true, gpuLookup, prices)
 }
 
-func (ls Ls) ShowUserWorkspaces(org *entity.Organization, otherOrgs []entity.Organization, user *entity.User, allWorkspaces []entity.Workspace, gpuLookup map[string]string) {
-	userWorkspaces := store.FilterForUserWorkspaces(allWorkspaces, user.ID)
-
-	ls.displayWorkspacesAndHelp(org, otherOrgs, userWorkspaces, allWorkspaces, false, gpuLookup)
-}
-
-func (ls Ls) ShowOrgWorkspaces(org *entity.Organization, workspaces []entity.Workspace, gpuLookup map[string]string) {
+func (ls Ls) ShowOrgWorkspaces(org *entity.Organization, workspaces []entity.Workspace, gpuLookup map[string]string, prices map[string]float64) {
 	if len(workspaces) == 0 {
 		ls.terminal.Vprint(ls.terminal.Yellow("No instances in org %s\n", org.Name))
 		return
 	}
 	ls.terminal.Vprintf("Org %s has %d instances\n", ls.terminal.Yellow(org.Name), len(workspaces))
-	displayWorkspacesTable(ls.terminal, workspaces, gpuLookup)
+	displayWorkspacesTable(ls.terminal, workspaces, gpuLookup, prices)
 
 	fmt.Print("\n")
 }
 
-func (ls Ls) displayWorkspacesAndHelp(org *entity.Organization, otherOrgs []entity.Organization, workspacesToDisplay []entity.Workspace, allWorkspaces []entity.Workspace, showAll bool, gpuLookup map[string]string) {
+func (ls Ls) displayWorkspacesAndHelp(org *entity.Organization, otherOrgs []entity.Organization, workspacesToDisplay []entity.Workspace, allWorkspaces []entity.Workspace, showAll bool, gpuLookup map[string]string, prices map[string]float64) {
 	if len(workspacesToDisplay) == 0 {
 		ls.terminal.Vprint(ls.terminal.Yellow("No instances in org %s\n", org.Name))
 		if !showAll && len(allWorkspaces) > 0 {
@@ -423,28 +543,32 @@ func (ls Ls) displayWorkspacesAndHelp(org *entity.Organization, otherOrgs []enti
 		} else {
 			ls.terminal.Vprintf("You have %d instances in Org %s\n", len(workspacesToDisplay), ls.terminal.Yellow(org.Name))
 		}
-		displayWorkspacesTable(ls.terminal, workspacesToDisplay, gpuLookup)
+		displayWorkspacesTable(ls.terminal, workspacesToDisplay, gpuLookup, prices)
 
 		fmt.Print("\n")
 	}
 }
 
-// buildGPULookup builds a map of instance type name to GPU name.
-// Returns nil if the fetch fails (graceful degradation).
-func buildGPULookup(s LsStore) map[string]string {
+// buildCatalogLookups maps each instance type name to its GPU name and to its
+// hourly price. Returns nil maps if the fetch fails (graceful degradation).
+func buildCatalogLookups(s LsStore) (map[string]string, map[string]float64) {
 	resp, err := s.GetInstanceTypes(true)
 	if err != nil || resp == nil {
-		return nil
+		return nil, nil
 	}
 	lookup := make(map[string]string, len(resp.Items))
+	prices := make(map[string]float64, len(resp.Items))
 	for _, item := range resp.Items {
 		if len(item.SupportedGPUs) > 0 {
 			lookup[item.Type] = item.SupportedGPUs[0].Name
 		} else {
 			lookup[item.Type] = "-"
 		}
+		if price, err := strconv.ParseFloat(item.BasePrice.Amount, 64); err == nil {
+			prices[item.Type] = price
+		}
 	}
-	return lookup
+	return lookup, prices
 }
 
 func (ls Ls) RunWorkspaces(cliAuth auth.CLIAuth, org *entity.Organization, showAll bool) error {
@@ -452,6 +576,7 @@ func (ls Ls) RunWorkspaces(cliAuth auth.CLIAuth, org *entity.Organization, showA
 	var allWorkspaces []entity.Workspace
 	var wsErr error
 	var gpuLookup map[string]string
+	var prices map[string]float64
 	var nodes []*nodev1.ExternalNode
 
 	var wg sync.WaitGroup
@@ -465,7 +590,7 @@ func (ls Ls) RunWorkspaces(cliAuth auth.CLIAuth, org *entity.Organization, showA
 	}()
 	go func() {
 		defer wg.Done()
-		gpuLookup = buildGPULookup(ls.lsStore)
+		gpuLookup, prices = buildCatalogLookups(ls.lsStore)
 	}()
 	if showAll {
 		go func() {
@@ -485,32 +610,34 @@ func (ls Ls) RunWorkspaces(cliAuth auth.CLIAuth, org *entity.Organization, showA
 		return breverrors.WrapAndTrace(wsErr)
 	}
 
-	// Determine which workspaces to show
-	var workspacesToShow []entity.Workspace
-	switch {
-	case showAll:
-		workspacesToShow = allWorkspaces
-	case cliAuth.IsAPIKey():
-		workspacesToShow = allWorkspaces
-	default:
-		user := cliAuth.User()
-		if user == nil {
-			return breverrors.NewValidationError("user is required")
-		}
-		workspacesToShow = store.FilterForUserWorkspaces(allWorkspaces, user.ID)
+	sel, err := ls.compileSelector(cliAuth, allWorkspaces)
+	if err != nil {
+		return err
+	}
+	workspacesToShow, err := selectWorkspaces(cliAuth, allWorkspaces, showAll, sel, gpuLookup)
+	if err != nil {
+		return err
+	}
+
+	if ls.output != nil {
+		return ls.output.write(os.Stdout, workspacesToShow, gpuLookup, prices)
 	}
 
 	// Handle JSON output
 	if ls.jsonOutput {
-		return ls.outputWorkspacesJSON(workspacesToShow, gpuLookup, nodes)
+		return ls.outputWorkspacesJSON(workspacesToShow, gpuLookup, prices, nodes)
 	}
 
+	if sel != nil && len(workspacesToShow) == 0 {
+		ls.terminal.Vprint(ls.terminal.Yellow("No instances match the filters in org %s\n", org.Name))
+		return nil
+	}
 	if cliAuth.IsAPIKey() {
-		ls.ShowOrgWorkspaces(org, workspacesToShow, gpuLookup)
+		ls.ShowOrgWorkspaces(org, workspacesToShow, gpuLookup, prices)
+		ls.showBurnRate(org, workspacesToShow, allWorkspaces, prices)
 		return nil
 	}
-	user := cliAuth.User()
-	if user == nil {
+	if cliAuth.User() == nil {
 		return breverrors.NewValidationError("user is required")
 	}
 
@@ -519,19 +646,53 @@ func (ls Ls) RunWorkspaces(cliAuth auth.CLIAuth, org *entity.Organization, showA
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
 	}
-	if showAll {
-		ls.ShowAllWorkspaces(org, orgs, workspacesToShow, gpuLookup)
+	if showAll || (sel != nil && sel.creatorID != "") {
+		ls.ShowAllWorkspaces(org, orgs, workspacesToShow, gpuLookup, prices)
+		ls.showBurnRate(org, workspacesToShow, allWorkspaces, prices)
 		if len(nodes) > 0 {
 			ls.terminal.Vprintf("\nYou have %d external node(s) in Org %s\n", len(nodes), ls.terminal.Yellow(org.Name))
 			displayNodesTable(ls.terminal, nodes, ls.piped)
 		}
 	} else {
-		ls.ShowUserWorkspaces(org, orgs, user, allWorkspaces, gpuLookup)
+		ls.displayWorkspacesAndHelp(org, orgs, workspacesToShow, allWorkspaces, false, gpuLookup, prices)
+		ls.showBurnRate(org, workspacesToShow, allWorkspaces, prices)
 	}
 
 	return nil
 }
 
+// compileSelector returns nil when no selector flag is set
+func (ls Ls) compileSelector(cliAuth auth.CLIAuth, allWorkspaces []entity.Workspace) (*selector, error) {
+	if ls.selectorFlags.IsZero() {
+		return nil, nil
+	}
+	return ls.selectorFlags.compile(cliAuth, ls.lsStore, allWorkspaces)
+}
+
+// selectWorkspaces returns the workspaces to list: the user's own, or all of
+// them with --all, API key auth or --created-by, narrowed by the selectors
+func selectWorkspaces(cliAuth auth.CLIAuth, allWorkspaces []entity.Workspace, showAll bool, sel *selector, gpuLookup map[string]string) ([]entity.Workspace, error) {
+	var workspacesToShow []entity.Workspace
+	switch {
+	case showAll:
+		workspacesToShow = allWorkspaces
+	case sel != nil && sel.creatorID != "":
+		workspacesToShow = allWorkspaces
+	case cliAuth.IsAPIKey():
+		workspacesToShow = allWorkspaces
+	default:
+		user := cliAuth.User()
+		if user == nil {
+			return nil, breverrors.NewValidationError("user is required")
+		}
+		workspacesToShow = store.FilterForUserWorkspaces(allWorkspaces, user.ID)
+	}
+	if sel != nil {
+		workspacesToShow = sel.filter(workspacesToShow, gpuLookup)
+	}
+	return workspacesToShow, nil
+}
+
 func (ls Ls) RunInstances(cliAuth auth.CLIAuth, org *entity.Organization, showAll bool) error {
 	if err := ls.RunWorkspaces(cliAuth, org, showAll); err != nil {
 		return err
@@ -541,15 +702,18 @@ func (ls Ls) RunInstances(cliAuth auth.CLIAuth, org *entity.Organization, showAl
 
 // WorkspaceInfo represents workspace data for JSON output
 type WorkspaceInfo struct {
-	Name         string `json:"name"`
-	ID           string `json:"id"`
-	Status       string `json:"status"`
-	BuildStatus  string `json:"build_status"`
-	ShellStatus  string `json:"shell_status"`
-	HealthStatus string `json:"health_status"`
-	InstanceType string `json:"instance_type"`
-	InstanceKind string `json:"instance_kind"`
-	GPU          string `json:"gpu"`
+	Name         string            `json:"name"`
+	ID           string            `json:"id"`
+	Status       string            `json:"status"`
+	BuildStatus  string            `json:"build_status"`
+	ShellStatus  string            `json:"shell_status"`
+	HealthStatus string            `json:"health_status"`
+	InstanceType string            `json:"instance_type"`
+	InstanceKind string            `json:"instance_kind"`
+	GPU          string            `json:"gpu"`
+	HourlyPrice  *float64          `json:"hourlyPrice,omitempty"` // catalog price in USD; omitted when unknown
+	CreatedAt    string            `json:"created_at,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
 }
 
 // getGPUForInstance returns the GPU name for an instance type using the lookup map.
@@ -566,6 +730,52 @@ func getGPUForInstance(w entity.Workspace, gpuLookup map[string]string) string {
 	return "-"
 }
 
+// getHourlyPrice returns the catalog price of the instance's type, or nil if unknown
+func getHourlyPrice(w entity.Workspace, prices map[string]float64) *float64 {
+	if price, ok := prices[w.InstanceType]; ok && w.InstanceType != "" {
+		return &price
+	}
+	return nil
+}
+
+func formatHourlyPrice(price *float64) string {
+	if price == nil {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", *price)
+}
+
+// showBurnRate prints what the org's RUNNING instances cost per hour, below
+// the table of shown. It goes to stderr so piping the table, e.g. into
+// grep RUNNING | brev stop, does not pick it up as an instance.
+func (ls Ls) showBurnRate(org *entity.Organization, shown, allWorkspaces []entity.Workspace, prices map[string]float64) {
+	if len(shown) == 0 || prices == nil {
+		return
+	}
+	total := 0.0
+	running := 0
+	unpriced := 0
+	for _, w := range allWorkspaces {
+		if w.Status != entity.Running {
+			continue
+		}
+		running++
+		if price := getHourlyPrice(w, prices); price != nil {
+			total += *price
+		} else {
+			unpriced++
+		}
+	}
+	if running == 0 {
+		return
+	}
+	ls.terminal.Eprintf("Burn rate: %s for %d RUNNING instances in Org %s", ls.terminal.Yellow("$%.2f/hr", total), running, org.Name)
+	if unpriced > 0 {
+		ls.terminal.Eprintf(" (%d without a catalog price)", unpriced)
+	}
+	ls.terminal.Eprintf("\n")
+}
+
 // getInstanceTypeAndKind returns the instance type and kind (gpu/cpu)
 func getInstanceTypeAndKind(w entity.Workspace, gpuLookup map[string]string) (string, string) {
 	if w.InstanceType != "" {
@@ -593,7 +803,7 @@ func toNodeInfos(nodes []*nodev1.ExternalNode) []NodeInfo {
 	return infos
 }
 
-func (ls Ls) outputWorkspacesJSON(workspaces []entity.Workspace, gpuLookup map[string]string, nodes []*nodev1.ExternalNode) error {
+func (ls Ls) outputWorkspacesJSON(workspaces []entity.Workspace, gpuLookup map[string]string, prices map[string]float64, nodes []*nodev1.ExternalNode) error {
 	var wsInfos []WorkspaceInfo
 	for _, w := range workspaces {
 		instanceType, instanceKind := getInstanceTypeAndKind(w, gpuLookup)
@@ -607,6 +817,9 @@ func (ls Ls) outputWorkspacesJSON(workspaces []entity.Workspace, gpuLookup map[s
 			InstanceType: instanceType,
 			InstanceKind: instanceKind,
 			GPU:          getGPUForInstance(w, gpuLookup),
+			HourlyPrice:  getHourlyPrice(w, prices),
+			CreatedAt:    w.CreatedAt,
+			Labels:       w.Labels,
 		})
 	}
 
@@ -652,26 +865,17 @@ func (ls Ls) RunHosts(org *entity.Organization) error {
 	return nil
 }
 
-func getBrevTableOptions() table.Options {
-	options := table.OptionsDefault
-	options.DrawBorder = false
-	options.SeparateColumns = false
-	options.SeparateRows = false
-	options.SeparateHeader = false
-	return options
-}
-
-func displayWorkspacesTable(t *terminal.Terminal, workspaces []entity.Workspace, gpuLookup map[string]string) {
+func displayWorkspacesTable(t *terminal.Terminal, workspaces []entity.Workspace, gpuLookup map[string]string, prices map[string]float64) {
 	ta := table.NewWriter()
 	ta.SetOutputMirror(os.Stdout)
-	ta.Style().Options = getBrevTableOptions()
-	header := table.Row{"Name", "Status", "Build", "Shell", "ID", "Machine", "GPU"}
+	ta.Style().Options = cmdutil.BrevTableOptions()
+	header := table.Row{"Name", "Status", "Build", "Shell", "ID", "Machine", "GPU", "$/hr"}
 	ta.AppendHeader(header)
 	for _, w := range workspaces {
 		status := getWorkspaceDisplayStatus(w)
 		instanceString := cmdutil.GetInstanceString(w)
 		gpu := getGPUForInstance(w, gpuLookup)
-		workspaceRow := []table.Row{{w.Name, getStatusColoredText(t, status), getStatusColoredText(t, string(w.VerbBuildStatus)), getStatusColoredText(t, getShellDisplayStatus(w)), w.ID, instanceString, gpu}}
+		workspaceRow := []table.Row{{w.Name, getStatusColoredText(t, status), getStatusColoredText(t, string(w.VerbBuildStatus)), getStatusColoredText(t, getShellDisplayStatus(w)), w.ID, instanceString, gpu, formatHourlyPrice(getHourlyPrice(w, prices))}}
 		ta.AppendRows(workspaceRow)
 	}
 	ta.Render()
@@ -701,7 +905,7 @@ func getWorkspaceDisplayStatus(w entity.Workspace) string {
 func displayWorkspacesTablePlain(workspaces []entity.Workspace, gpuLookup map[string]string) { //nolint:unused // see TODO above
 	ta := table.NewWriter()
 	ta.SetOutputMirror(os.Stdout)
-	ta.Style().Options = getBrevTableOptions()
+	ta.Style().Options = cmdutil.BrevTableOptions()
 	header := table.Row{"NAME", "STATUS", "BUILD", "SHELL", "ID", "MACHINE", "GPU"}
 	ta.AppendHeader(header)
 	for _, w := range workspaces {
@@ -719,7 +923,7 @@ func displayWorkspacesTablePlain(workspaces []entity.Workspace, gpuLookup map[st
 func displayOrgTablePlain(orgs []entity.Organization, currentOrg *entity.Organization) { //nolint:unused // see TODO above
 	ta := table.NewWriter()
 	ta.SetOutputMirror(os.Stdout)
-	ta.Style().Options = getBrevTableOptions()
+	ta.Style().Options = cmdutil.BrevTableOptions()
 	header := table.Row{"NAME", "ID"}
 	ta.AppendHeader(header)
 	for _, o := range orgs {
@@ -735,7 +939,7 @@ func displayOrgTablePlain(orgs []entity.Organization, currentOrg *entity.Organiz
 func displayOrgTable(t *terminal.Terminal, orgs []entity.Organization, currentOrg *entity.Organization) {
 	ta := table.NewWriter()
 	ta.SetOutputMirror(os.Stdout)
-	ta.Style().Options = getBrevTableOptions()
+	ta.Style().Options = cmdutil.BrevTableOptions()
 	header := table.Row{"NAME", "ID"}
 	ta.AppendHeader(header)
 	for _, o := range orgs {
@@ -820,7 +1024,7 @@ func (ls Ls) outputNodesJSON(nodes []*nodev1.ExternalNode) error {
 func displayNodesTable(t *terminal.Terminal, nodes []*nodev1.ExternalNode, isPiped bool) {
 	ta := table.NewWriter()
 	ta.SetOutputMirror(os.Stdout)
-	ta.Style().Options = getBrevTableOptions()
+	ta.Style().Options = cmdutil.BrevTableOptions()
 	ta.AppendHeader(table.Row{"NAME", "STATUS"})
 	for _, n := range nodes {
 		status := nodeConnectionStatus(n)
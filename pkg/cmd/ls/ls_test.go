@@ -31,6 +31,7 @@ type mockLsStore struct {
 	workspaces           []entity.Workspace
 	authTokens           *entity.AuthTokens
 	workspaceOrgID       string
+	instanceTypes        *gpusearch.InstanceTypesResponse
 	currentUserCalls     int
 	getOrganizationsCall int
 }
@@ -78,6 +79,9 @@ func (m *mockLsStore) GetUsers(_ map[string]string) ([]entity.User, error) {
 }
 
 func (m *mockLsStore) GetInstanceTypes(_ bool) (*gpusearch.InstanceTypesResponse, error) {
+	if m.instanceTypes != nil {
+		return m.instanceTypes, nil
+	}
 	return &gpusearch.InstanceTypesResponse{}, nil
 }
 
@@ -112,7 +116,7 @@ func resolveTestCLIAuth(t *testing.T, s *mockLsStore) authpkg.CLIAuth {
 
 func runLs(t *testing.T, term *terminal.Terminal, s *mockLsStore, args []string, showAll bool) error {
 	t.Helper()
-	return RunLs(term, resolveTestCLIAuth(t, s), s, args, "", showAll, true)
+	return RunLs(term, resolveTestCLIAuth(t, s), s, args, "", showAll, true, LsOptions{})
 }
 
 func TestRunLs_APIKeyJSONSkipsUserAndOrgList(t *testing.T) {
@@ -225,19 +229,30 @@ func TestRunLs_APIKeyRequiresCredentialOrg(t *testing.T) {
 // captureStdout runs fn while capturing stdout and returns the output.
 func captureStdout(t *testing.T, fn func()) string {
 	t.Helper()
+	return captureFile(t, &os.Stdout, fn)
+}
+
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stderr, fn)
+}
+
+// captureFile returns what fn writes to *f, e.g. os.Stdout
+func captureFile(t *testing.T, f **os.File, fn func()) string {
+	t.Helper()
 
 	r, w, err := os.Pipe()
 	if err != nil {
 		t.Fatalf("failed to create pipe: %v", err)
 	}
 
-	origStdout := os.Stdout
-	os.Stdout = w
+	orig := *f
+	*f = w
 
 	fn()
 
 	w.Close()
-	os.Stdout = origStdout
+	*f = orig
 
 	buf := make([]byte, 64*1024)
 	n, _ := r.Read(buf)
@@ -427,7 +442,7 @@ func TestRunLs_ShowAllTable(t *testing.T) {
 	term := terminal.New()
 
 	out := captureStdout(t, func() {
-		err := RunLs(term, resolveTestCLIAuth(t, s), s, nil, "", true, false)
+		err := RunLs(term, resolveTestCLIAuth(t, s), s, nil, "", true, false, LsOptions{})
 		if err != nil {
 			t.Fatalf("RunLs --all returned error: %v", err)
 		}
@@ -643,7 +658,7 @@ func TestOutputWorkspacesJSON_WithNodes(t *testing.T) {
 	}
 
 	out := captureStdout(t, func() {
-		err := ls.outputWorkspacesJSON(workspaces, nil, nodes)
+		err := ls.outputWorkspacesJSON(workspaces, nil, nil, nodes)
 		if err != nil {
 			t.Fatalf("outputWorkspacesJSON returned error: %v", err)
 		}
@@ -691,7 +706,7 @@ func TestOutputWorkspacesJSON_NoNodes(t *testing.T) {
 	}
 
 	out := captureStdout(t, func() {
-		err := ls.outputWorkspacesJSON(workspaces, nil, nil)
+		err := ls.outputWorkspacesJSON(workspaces, nil, nil, nil)
 		if err != nil {
 			t.Fatalf("outputWorkspacesJSON returned error: %v", err)
 		}
@@ -792,3 +807,76 @@ func TestRunLs_ShowAllJSON_WithNodes(t *testing.T) {
 		})
 	})
 }
+
+func newPricedTestStore() *mockLsStore {
+	s := newTestStore()
+	s.instanceTypes = &gpusearch.InstanceTypesResponse{Items: []gpusearch.InstanceType{
+		{Type: "g5.xlarge", SupportedGPUs: []gpusearch.GPU{{Name: "A10G"}}, BasePrice: gpusearch.BasePrice{Currency: "USD", Amount: "1.006"}},
+		{Type: "p5.48xlarge", SupportedGPUs: []gpusearch.GPU{{Name: "H100"}}, BasePrice: gpusearch.BasePrice{Currency: "USD", Amount: "98.32"}},
+	}}
+	s.workspaces = []entity.Workspace{
+		{ID: "ws1", Name: "idle-h100", Status: entity.Running, CreatedByUserID: "u1", InstanceType: "p5.48xlarge"},
+		{ID: "ws2", Name: "stopped-a10", Status: entity.Stopped, CreatedByUserID: "u1", InstanceType: "g5.xlarge"},
+		{ID: "ws3", Name: "teammate-a10", Status: entity.Running, CreatedByUserID: "u2", InstanceType: "g5.xlarge"},
+		{ID: "ws4", Name: "cpu-box", Status: entity.Running, CreatedByUserID: "u1", WorkspaceClassID: "2x8"},
+	}
+	return s
+}
+
+// TestRunLs_HourlyPriceJSON verifies that --json carries each instance's
+// catalog price, and leaves it out when the price is unknown.
+func TestRunLs_HourlyPriceJSON(t *testing.T) {
+	s := newPricedTestStore()
+	out := captureStdout(t, func() {
+		if err := runLs(t, terminal.New(), s, nil, false); err != nil {
+			t.Fatalf("RunLs returned error: %v", err)
+		}
+	})
+	var parsed struct {
+		Workspaces []map[string]interface{} `json:"workspaces"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nraw: %s", err, out)
+	}
+	if len(parsed.Workspaces) != 3 {
+		t.Fatalf("expected 3 workspaces, got %d", len(parsed.Workspaces))
+	}
+	if got := parsed.Workspaces[0]["hourlyPrice"]; got != 98.32 {
+		t.Errorf("expected hourlyPrice 98.32, got %v", got)
+	}
+	if got := parsed.Workspaces[1]["hourlyPrice"]; got != 1.006 {
+		t.Errorf("expected hourlyPrice 1.006, got %v", got)
+	}
+	if _, ok := parsed.Workspaces[2]["hourlyPrice"]; ok {
+		t.Errorf("expected no hourlyPrice for an unpriced instance, got %v", parsed.Workspaces[2])
+	}
+}
+
+// TestRunLs_HourlyPriceTable verifies the $/hr column and that the burn rate
+// footer counts every RUNNING instance in the org, not only the listed ones.
+// The footer goes to stderr so piped output only has the table.
+func TestRunLs_HourlyPriceTable(t *testing.T) {
+	s := newPricedTestStore()
+	var out string
+	errOut := captureStderr(t, func() {
+		out = captureStdout(t, func() {
+			if err := RunLs(terminal.New(), resolveTestCLIAuth(t, s), s, nil, "", false, false, LsOptions{}); err != nil {
+				t.Fatalf("RunLs returned error: %v", err)
+			}
+		})
+	})
+	for _, want := range []string{"$/HR", "$98.32", "$1.01"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in table output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Burn rate") {
+		t.Errorf("expected the burn rate footer off stdout, got:\n%s", out)
+	}
+	if want := "Burn rate: $99.33/hr for 3 RUNNING instances in Org test-org (1 without a catalog price)"; !strings.Contains(errOut, want) {
+		t.Errorf("expected %q on stderr, got:\n%s", want, errOut)
+	}
+	if strings.Contains(out, "teammate-a10") {
+		t.Errorf("expected only the user's instances in the table, got:\n%s", out)
+	}
+}
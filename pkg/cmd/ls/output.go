@@ -0,0 +1,229 @@
+package ls
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	cmdutil "github.com/brevdev/brev-cli/pkg/cmd/util"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// OutputHelp describes the -o formats
+const OutputHelp = "output format: json, name, custom-columns=HEADER:.field,... or template=GO-TEMPLATE"
+
+const (
+	outputJSON          = "json"
+	outputName          = "name"
+	outputCustomColumns = "custom-columns="
+	outputTemplate      = "template="
+	// noValue is shown for fields an instance does not have
+	noValue = "-"
+)
+
+// IsOutputFormat returns true if s is one of the -o formats
+func IsOutputFormat(s string) bool {
+	return s == outputJSON || s == outputName || strings.HasPrefix(s, outputCustomColumns) || strings.HasPrefix(s, outputTemplate)
+}
+
+// column is one custom-columns column
+type column struct {
+	header string
+	path   []string
+}
+
+// outputFormat is a parsed -o other than json
+type outputFormat struct {
+	names    bool
+	columns  []column
+	template *template.Template
+}
+
+var fieldPathRe = regexp.MustCompile(`^(\.[A-Za-z_][A-Za-z0-9_]*(\[[0-9]+\])*)+$`)
+
+// parseOutputFormat parses -o. json is handled by --json and returns nil.
+func parseOutputFormat(s string) (*outputFormat, error) {
+	switch {
+	case s == "" || s == outputJSON:
+		return nil, nil
+	case s == outputName:
+		return &outputFormat{names: true}, nil
+	case strings.HasPrefix(s, outputCustomColumns):
+		var columns []column
+		for _, spec := range strings.Split(strings.TrimPrefix(s, outputCustomColumns), ",") {
+			header, path, found := strings.Cut(spec, ":")
+			if !found || header == "" || !fieldPathRe.MatchString(path) {
+				return nil, breverrors.NewValidationError(fmt.Sprintf("invalid custom column %q: expected HEADER:.field, e.g. GPU:.gpu or TEAM:.labels.team", spec))
+			}
+			columns = append(columns, column{header: header, path: splitFieldPath(path)})
+		}
+		return &outputFormat{columns: columns}, nil
+	case strings.HasPrefix(s, outputTemplate):
+		text := strings.TrimPrefix(s, outputTemplate)
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		tmpl, err := template.New("output").Funcs(template.FuncMap{
+			"lower": strings.ToLower,
+			"upper": strings.ToUpper,
+			"join":  joinValues,
+		}).Parse(text)
+		if err != nil {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("invalid -o template: %v", err))
+		}
+		return &outputFormat{template: tmpl}, nil
+	default:
+		return nil, breverrors.NewValidationError(fmt.Sprintf("unknown output format %q; use %s", s, strings.TrimPrefix(OutputHelp, "output format: ")))
+	}
+}
+
+// splitFieldPath turns .a.b[0] into ["a", "b", "0"]
+func splitFieldPath(path string) []string {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	return strings.Split(strings.TrimPrefix(path, "."), ".")
+}
+
+// workspaceRecord is a workspace as addressed by -o: every entity.Workspace
+// field under its JSON name, plus gpu, kind (gpu or cpu), machine,
+// shellStatus and hourlyPrice as shown by the table. status is the status the
+// table shows.
+func workspaceRecord(w entity.Workspace, gpuLookup map[string]string, prices map[string]float64) (map[string]interface{}, error) {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	record := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&record); err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	_, kind := getInstanceTypeAndKind(w, gpuLookup)
+	record["status"] = getWorkspaceDisplayStatus(w)
+	record["gpu"] = getGPUForInstance(w, gpuLookup)
+	record["kind"] = kind
+	record["machine"] = cmdutil.GetInstanceString(w)
+	record["shellStatus"] = getShellDisplayStatus(w)
+	if price := getHourlyPrice(w, prices); price != nil {
+		record["hourlyPrice"] = json.Number(strconv.FormatFloat(*price, 'f', -1, 64))
+	}
+	return record, nil
+}
+
+// lookupField follows path through record. Keys match case-insensitively
+// when there is no exact match, so .DNS and .dns both work.
+func lookupField(record interface{}, path []string) (interface{}, bool) {
+	value := record
+	for _, key := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				for k, candidate := range v {
+					if strings.EqualFold(k, key) {
+						next, ok = candidate, true
+						break
+					}
+				}
+			}
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, value != nil
+}
+
+// formatValue prints scalars as is and objects and lists as compact JSON
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return noValue
+	case string:
+		if v == "" {
+			return noValue
+		}
+		return v
+	case json.Number, bool:
+		return fmt.Sprint(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// joinValues is the template function join: {{join .additionalUsers ","}}
+func joinValues(value interface{}, sep string) string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return formatValue(value)
+	}
+	parts := make([]string, 0, len(list))
+	for _, item := range list {
+		parts = append(parts, formatValue(item))
+	}
+	return strings.Join(parts, sep)
+}
+
+// write prints the workspaces in the format
+func (f *outputFormat) write(out io.Writer, workspaces []entity.Workspace, gpuLookup map[string]string, prices map[string]float64) error {
+	if f.names {
+		for _, w := range workspaces {
+			_, _ = fmt.Fprintln(out, w.Name)
+		}
+		return nil
+	}
+
+	var ta table.Writer
+	if f.columns != nil {
+		ta = table.NewWriter()
+		ta.SetOutputMirror(out)
+		ta.Style().Options = cmdutil.BrevTableOptions()
+		header := table.Row{}
+		for _, c := range f.columns {
+			header = append(header, c.header)
+		}
+		ta.AppendHeader(header)
+	}
+	for _, w := range workspaces {
+		record, err := workspaceRecord(w, gpuLookup, prices)
+		if err != nil {
+			return err
+		}
+		if f.template != nil {
+			if err := f.template.Execute(out, record); err != nil {
+				return breverrors.NewValidationError(fmt.Sprintf("-o template: %v", err))
+			}
+			continue
+		}
+		row := table.Row{}
+		for _, c := range f.columns {
+			value, _ := lookupField(record, c.path)
+			row = append(row, formatValue(value))
+		}
+		ta.AppendRow(row)
+	}
+	if ta != nil {
+		ta.Render()
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package ls
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+)
+
+func outputTestWorkspaces() []entity.Workspace {
+	return []entity.Workspace{
+		{
+			ID: "ws1", Name: "train-a", Status: entity.Running, DNS: "train-a.brev.dev", InstanceType: "p5.48xlarge",
+			SSHPort: 22, Labels: entity.Labels{"team": "ml"}, AdditionalUsers: []string{"u2", "u3"},
+			FirewallRules: []entity.FirewallRule{{Port: "6006"}},
+		},
+		{ID: "ws2", Name: "cpu-box", Status: entity.Running, HealthStatus: entity.Unhealthy, WorkspaceClassID: "2x8"},
+	}
+}
+
+func writeOutput(t *testing.T, format string) string {
+	t.Helper()
+	f, err := parseOutputFormat(format)
+	if err != nil {
+		t.Fatalf("parseOutputFormat(%q): %v", format, err)
+	}
+	var buf bytes.Buffer
+	if err := f.write(&buf, outputTestWorkspaces(), map[string]string{"p5.48xlarge": "H100"}, map[string]float64{"p5.48xlarge": 98.32}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return buf.String()
+}
+
+func TestOutputName(t *testing.T) {
+	if got := writeOutput(t, "name"); got != "train-a\ncpu-box\n" {
+		t.Errorf("unexpected -o name output %q", got)
+	}
+}
+
+func TestOutputCustomColumns(t *testing.T) {
+	out := writeOutput(t, "custom-columns=NAME:.name,GPU:.gpu,KIND:.kind,DNS:.DNS,TEAM:.labels.team,PORT:.firewallRules[0].port,SSH:.sshPort,STATUS:.status,PRICE:.hourlyPrice")
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got:\n%s", out)
+	}
+	for _, want := range []string{"NAME", "GPU", "KIND", "DNS", "TEAM", "PORT", "SSH", "STATUS", "PRICE"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("header %q is missing %s", lines[0], want)
+		}
+	}
+	if got := strings.Fields(lines[1]); strings.Join(got, " ") != "train-a H100 gpu train-a.brev.dev ml 6006 22 RUNNING 98.32" {
+		t.Errorf("unexpected row %q", got)
+	}
+	if got := strings.Fields(lines[2]); strings.Join(got, " ") != "cpu-box - cpu - - - 0 UNHEALTHY -" {
+		t.Errorf("unexpected row %q", got)
+	}
+}
+
+func TestOutputTemplate(t *testing.T) {
+	out := writeOutput(t, `template={{.name | upper}} {{.machine}} {{join .additionalUsers ","}}`)
+	if out != "TRAIN-A p5.48xlarge u2,u3\nCPU-BOX 2 cpu | 8 gb ram -\n" {
+		t.Errorf("unexpected -o template output %q", out)
+	}
+}
+
+func TestParseOutputFormatErrors(t *testing.T) {
+	for _, bad := range []string{
+		"yaml",
+		"custom-columns=",
+		"custom-columns=NAME",
+		"custom-columns=NAME:name",
+		"custom-columns=:.name",
+		"custom-columns=A:.a[x]",
+		"template={{.name",
+	} {
+		if _, err := parseOutputFormat(bad); err == nil {
+			t.Errorf("expected an error for -o %q", bad)
+		}
+	}
+}
+
+func TestResolveOutputFlag(t *testing.T) {
+	term := terminal.New()
+	s := newTestStore()
+	cliAuth := resolveTestCLIAuth(t, s)
+	org, output, err := resolveOutputFlag(term, cliAuth, s, "", "name")
+	if err != nil || org != "" || output != "name" {
+		t.Errorf("unexpected result %q %q %v", org, output, err)
+	}
+	// -o used to be --org
+	org, output, err = resolveOutputFlag(term, cliAuth, s, "", "my-org")
+	if err != nil || org != "my-org" || output != "" {
+		t.Errorf("unexpected result %q %q %v", org, output, err)
+	}
+	if _, _, err := resolveOutputFlag(term, cliAuth, s, "my-org", "my-other-org"); err == nil {
+		t.Error("expected an error for an unknown format with --org")
+	}
+}
+
+func TestResolveOutputFlag_AmbiguousWithOrgName(t *testing.T) {
+	term := terminal.New()
+	s := newTestStore()
+	s.orgs = append(s.orgs, entity.Organization{ID: "org2", Name: "json"})
+	cliAuth := resolveTestCLIAuth(t, s)
+
+	_, _, err := resolveOutputFlag(term, cliAuth, s, "", "json")
+	if err == nil || !strings.Contains(err.Error(), "--org json") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+	// --org says which org, so -o is the format
+	org, output, err := resolveOutputFlag(term, cliAuth, s, "test-org", "json")
+	if err != nil || org != "test-org" || output != "json" {
+		t.Errorf("unexpected result %q %q %v", org, output, err)
+	}
+}
+
+func TestRunLs_OutputWithSelectors(t *testing.T) {
+	s := newTestStore()
+	s.workspaces = selectorTestWorkspaces()
+	out := captureStdout(t, func() {
+		err := RunLs(terminal.New(), resolveTestCLIAuth(t, s), s, []string{"instances"}, "", false, false, LsOptions{
+			Selector: SelectorFlags{Labels: []string{"team=ml"}},
+			Output:   "name",
+		})
+		if err != nil {
+			t.Fatalf("RunLs returned error: %v", err)
+		}
+	})
+	if out != "train-a\ntrain-b\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		json bool
+		opts LsOptions
+	}{
+		{nil, true, LsOptions{Output: "name"}},
+		{[]string{"orgs"}, false, LsOptions{Output: "name"}},
+		{nil, false, LsOptions{Output: "name", Watch: true}},
+	} {
+		if err := RunLs(terminal.New(), resolveTestCLIAuth(t, s), s, tc.args, "", false, tc.json, tc.opts); err == nil {
+			t.Errorf("expected an error for %+v", tc)
+		}
+	}
+}
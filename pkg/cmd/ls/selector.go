@@ -0,0 +1,284 @@
+package ls
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// createdByMe selects the current user's instances with --created-by
+const createdByMe = "me"
+
+// SelectorFlags holds the flags that narrow the instances 'brev ls' shows
+type SelectorFlags struct {
+	Statuses  []string
+	CreatedBy string
+	GPUs      []string
+	Types     []string
+	NameRegex string
+	OlderThan string
+	Labels    []string
+}
+
+// AddSelectorFlags registers the selector flags on a command
+func AddSelectorFlags(cmd *cobra.Command, f *SelectorFlags) {
+	AddInstanceSelectorFlags(cmd, f)
+	cmd.Flags().StringSliceVar(&f.GPUs, "gpu", nil, "Only instances with these GPUs, e.g. H100,A100")
+	cmd.Flags().StringSliceVar(&f.Types, "type", nil, "Only instances of these instance types")
+}
+
+// AddInstanceSelectorFlags registers the selector flags that need no instance
+// type catalog, for commands where --gpu and --type mean something else
+func AddInstanceSelectorFlags(cmd *cobra.Command, f *SelectorFlags) {
+	cmd.Flags().StringSliceVar(&f.Statuses, "status", nil, "Only instances with these statuses, e.g. RUNNING,STOPPED")
+	cmd.Flags().StringVar(&f.CreatedBy, "created-by", "", "Only instances created by this user: me, an email or a user ID (searches the whole org)")
+	cmd.Flags().StringVar(&f.NameRegex, "name-regex", "", "Only instances whose name matches this regular expression")
+	cmd.Flags().StringVar(&f.OlderThan, "older-than", "", "Only instances created longer ago than this, e.g. 48h or 7d")
+	cmd.Flags().StringSliceVarP(&f.Labels, "label", "l", nil, "Only instances with these labels: key=value, key!=value, key or !key")
+}
+
+// IsZero returns true if no selector flag is set
+func (f SelectorFlags) IsZero() bool {
+	return len(f.Statuses) == 0 && f.CreatedBy == "" && len(f.GPUs) == 0 && len(f.Types) == 0 &&
+		f.NameRegex == "" && f.OlderThan == "" && len(f.Labels) == 0
+}
+
+// SelectorStore is what the selectors need from a store. Stores that can also
+// look up users by ID resolve --created-by emails.
+type SelectorStore interface {
+	GetCurrentUser() (*entity.User, error)
+}
+
+// Select returns the workspaces that match the flags, or all of them when no
+// flag is set. gpuLookup maps instance types to GPUs for --gpu.
+func (f SelectorFlags) Select(cliAuth auth.CLIAuth, s SelectorStore, workspaces []entity.Workspace, gpuLookup map[string]string) ([]entity.Workspace, error) {
+	if f.IsZero() {
+		return workspaces, nil
+	}
+	sel, err := f.compile(cliAuth, s, workspaces)
+	if err != nil {
+		return nil, err
+	}
+	return sel.filter(workspaces, gpuLookup), nil
+}
+
+// labelRequirement is one --label selector
+type labelRequirement struct {
+	key     string
+	value   string
+	exists  bool // key or !key
+	negated bool // != or !key
+}
+
+func (r labelRequirement) matches(labels entity.Labels) bool {
+	value, ok := labels[r.key]
+	switch {
+	case r.exists:
+		return ok != r.negated
+	case r.negated:
+		return !ok || value != r.value
+	default:
+		return ok && value == r.value
+	}
+}
+
+func parseLabelRequirement(s string) (labelRequirement, error) {
+	s = strings.TrimSpace(s)
+	var r labelRequirement
+	switch {
+	case strings.Contains(s, "!="):
+		r.key, r.value, _ = strings.Cut(s, "!=")
+		r.negated = true
+	case strings.Contains(s, "=="):
+		r.key, r.value, _ = strings.Cut(s, "==")
+	case strings.Contains(s, "="):
+		r.key, r.value, _ = strings.Cut(s, "=")
+	case strings.HasPrefix(s, "!"):
+		r.key, r.exists, r.negated = s[1:], true, true
+	default:
+		r.key, r.exists = s, true
+	}
+	if err := entity.ValidateLabelKey(r.key); err != nil {
+		return r, breverrors.NewValidationError(fmt.Sprintf("invalid --label %q: %v", s, err))
+	}
+	return r, nil
+}
+
+// ParseAge parses a duration such as 48h or 30m, and also accepts days, e.g. 7d
+func ParseAge(s string) (time.Duration, error) {
+	var age time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n float64
+		n, err = strconv.ParseFloat(days, 64)
+		age = time.Duration(n * float64(24*time.Hour))
+	} else {
+		age, err = time.ParseDuration(s)
+	}
+	if err != nil || age < 0 {
+		return 0, breverrors.NewValidationError(fmt.Sprintf("invalid duration %q: use e.g. 48h, 90m or 7d", s))
+	}
+	return age, nil
+}
+
+// selector matches instances against the selector flags
+type selector struct {
+	statuses  map[string]bool
+	creatorID string
+	gpus      map[string]bool
+	types     map[string]bool
+	name      *regexp.Regexp
+	olderThan time.Duration
+	labels    []labelRequirement
+	now       time.Time
+}
+
+// compile checks the flags and resolves --created-by against the workspaces listed
+func (f SelectorFlags) compile(cliAuth auth.CLIAuth, lsStore SelectorStore, workspaces []entity.Workspace) (*selector, error) {
+	s := &selector{}
+	s.statuses = upperSet(f.Statuses)
+	s.gpus = upperSet(f.GPUs)
+	if len(f.Types) > 0 {
+		s.types = map[string]bool{}
+		for _, t := range f.Types {
+			s.types[strings.TrimSpace(t)] = true
+		}
+	}
+	if f.NameRegex != "" {
+		re, err := regexp.Compile(f.NameRegex)
+		if err != nil {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --name-regex: %v", err))
+		}
+		s.name = re
+	}
+	if f.OlderThan != "" {
+		age, err := ParseAge(f.OlderThan)
+		if err != nil {
+			return nil, err
+		}
+		s.olderThan = age
+	}
+	for _, l := range f.Labels {
+		r, err := parseLabelRequirement(l)
+		if err != nil {
+			return nil, err
+		}
+		s.labels = append(s.labels, r)
+	}
+	if f.CreatedBy != "" {
+		creatorID, err := resolveCreator(cliAuth, lsStore, f.CreatedBy, workspaces)
+		if err != nil {
+			return nil, err
+		}
+		s.creatorID = creatorID
+	}
+	return s, nil
+}
+
+func upperSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := map[string]bool{}
+	for _, v := range values {
+		set[strings.ToUpper(strings.TrimSpace(v))] = true
+	}
+	return set
+}
+
+// userLookup is implemented by stores that can look up other users
+type userLookup interface {
+	GetUserByID(userID string) (*entity.User, error)
+}
+
+// resolveCreator turns "me", an email or a user ID into a user ID. Emails are
+// matched against the creators of the listed workspaces.
+func resolveCreator(cliAuth auth.CLIAuth, lsStore SelectorStore, createdBy string, workspaces []entity.Workspace) (string, error) {
+	if createdBy == createdByMe {
+		if user := cliAuth.User(); user != nil {
+			return user.ID, nil
+		}
+		user, err := lsStore.GetCurrentUser()
+		if err != nil {
+			return "", breverrors.WrapAndTrace(err)
+		}
+		return user.ID, nil
+	}
+	if !strings.Contains(createdBy, "@") {
+		return createdBy, nil
+	}
+
+	if user := cliAuth.User(); user != nil && strings.EqualFold(user.Email, createdBy) {
+		return user.ID, nil
+	}
+	lookup, ok := lsStore.(userLookup)
+	if !ok {
+		return "", breverrors.NewValidationError("--created-by with an email is not supported here; pass a user ID")
+	}
+	checked := map[string]bool{}
+	for _, w := range workspaces {
+		if w.CreatedByUserID == "" || checked[w.CreatedByUserID] {
+			continue
+		}
+		checked[w.CreatedByUserID] = true
+		user, err := lookup.GetUserByID(w.CreatedByUserID)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(user.Email, createdBy) {
+			return user.ID, nil
+		}
+	}
+	// nobody with this email has instances in the org
+	return createdBy, nil
+}
+
+// matches returns true if w passes every selector. gpu is the GPU of its instance type.
+func (s *selector) matches(w entity.Workspace, gpu string) bool {
+	if s.statuses != nil && !s.statuses[strings.ToUpper(w.Status)] && !s.statuses[strings.ToUpper(getWorkspaceDisplayStatus(w))] {
+		return false
+	}
+	if s.creatorID != "" && w.CreatedByUserID != s.creatorID {
+		return false
+	}
+	if s.gpus != nil && !s.gpus[strings.ToUpper(gpu)] {
+		return false
+	}
+	if s.types != nil && !s.types[w.InstanceType] && !s.types[w.WorkspaceClassID] {
+		return false
+	}
+	if s.name != nil && !s.name.MatchString(w.Name) {
+		return false
+	}
+	if s.olderThan > 0 {
+		created, ok := w.GetCreatedAt()
+		if !ok || s.now.Sub(created) < s.olderThan {
+			return false
+		}
+	}
+	for _, r := range s.labels {
+		if !r.matches(w.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter returns the workspaces that match
+func (s *selector) filter(workspaces []entity.Workspace, gpuLookup map[string]string) []entity.Workspace {
+	s.now = time.Now()
+	var selected []entity.Workspace
+	for _, w := range workspaces {
+		if s.matches(w, getGPUForInstance(w, gpuLookup)) {
+			selected = append(selected, w)
+		}
+	}
+	return selected
+}
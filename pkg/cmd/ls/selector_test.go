@@ -0,0 +1,175 @@
+package ls
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+)
+
+// mockLsUserStore adds the optional user lookup used by --created-by <email>
+type mockLsUserStore struct {
+	*mockLsStore
+	users map[string]*entity.User
+}
+
+func (m *mockLsUserStore) GetUserByID(userID string) (*entity.User, error) {
+	if user, ok := m.users[userID]; ok {
+		return user, nil
+	}
+	return nil, nil
+}
+
+func selectorTestWorkspaces() []entity.Workspace {
+	old := time.Now().Add(-72 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	return []entity.Workspace{
+		{ID: "ws1", Name: "train-a", Status: entity.Running, CreatedByUserID: "u1", InstanceType: "p5.48xlarge", CreatedAt: old, Labels: entity.Labels{"team": "ml", "env": "prod"}},
+		{ID: "ws2", Name: "train-b", Status: entity.Stopped, CreatedByUserID: "u1", InstanceType: "g5.xlarge", CreatedAt: recent, Labels: entity.Labels{"team": "ml"}},
+		{ID: "ws3", Name: "notebook", Status: entity.Running, CreatedByUserID: "u2", InstanceType: "p5.48xlarge", CreatedAt: old},
+		{ID: "ws4", Name: "cpu-box", Status: entity.Running, CreatedByUserID: "u1", WorkspaceClassID: "2x8"},
+	}
+}
+
+func runLsJSONWithSelector(t *testing.T, s LsStore, cliStore *mockLsStore, showAll bool, flags SelectorFlags) []string {
+	t.Helper()
+	var names []string
+	out := captureStdout(t, func() {
+		err := RunLs(terminal.New(), resolveTestCLIAuth(t, cliStore), s, nil, "", showAll, true, LsOptions{Selector: flags})
+		if err != nil {
+			t.Fatalf("RunLs returned error: %v", err)
+		}
+	})
+	var parsed struct {
+		Workspaces []WorkspaceInfo `json:"workspaces"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\nraw output: %s", err, out)
+	}
+	for _, w := range parsed.Workspaces {
+		names = append(names, w.Name)
+	}
+	return names
+}
+
+func TestRunLs_SelectorsJSON(t *testing.T) {
+	cases := []struct {
+		name    string
+		showAll bool
+		flags   SelectorFlags
+		want    string
+	}{
+		{"no selectors", false, SelectorFlags{}, "train-a,train-b,cpu-box"},
+		{"status", false, SelectorFlags{Statuses: []string{"stopped"}}, "train-b"},
+		{"statuses", true, SelectorFlags{Statuses: []string{"RUNNING", "STOPPED"}}, "train-a,train-b,notebook,cpu-box"},
+		{"type", false, SelectorFlags{Types: []string{"g5.xlarge", "2x8"}}, "train-b,cpu-box"},
+		{"name regex", true, SelectorFlags{NameRegex: "^train-"}, "train-a,train-b"},
+		{"older than", true, SelectorFlags{OlderThan: "2d"}, "train-a,notebook"},
+		{"label", false, SelectorFlags{Labels: []string{"team=ml", "env!=prod"}}, "train-b"},
+		{"label exists", true, SelectorFlags{Labels: []string{"!team"}}, "notebook,cpu-box"},
+		{"created by me", false, SelectorFlags{CreatedBy: "me", Statuses: []string{"RUNNING"}}, "train-a,cpu-box"},
+		{"created by id widens scope", false, SelectorFlags{CreatedBy: "u2"}, "notebook"},
+		{"no match", false, SelectorFlags{NameRegex: "^nope$"}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestStore()
+			s.workspaces = selectorTestWorkspaces()
+			got := strings.Join(runLsJSONWithSelector(t, s, s, tc.showAll, tc.flags), ",")
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRunLs_CreatedByEmail(t *testing.T) {
+	s := newTestStore()
+	s.workspaces = selectorTestWorkspaces()
+	userStore := &mockLsUserStore{mockLsStore: s, users: map[string]*entity.User{
+		"u1": {ID: "u1", Email: "me@example.com"},
+		"u2": {ID: "u2", Email: "alice@example.com"},
+	}}
+
+	got := strings.Join(runLsJSONWithSelector(t, userStore, s, false, SelectorFlags{CreatedBy: "Alice@example.com"}), ",")
+	if got != "notebook" {
+		t.Errorf("expected notebook, got %q", got)
+	}
+	got = strings.Join(runLsJSONWithSelector(t, userStore, s, false, SelectorFlags{CreatedBy: "nobody@example.com"}), ",")
+	if got != "" {
+		t.Errorf("expected no instances, got %q", got)
+	}
+
+	// stores without a user lookup only accept IDs
+	err := RunLs(terminal.New(), resolveTestCLIAuth(t, s), s, nil, "", false, true, LsOptions{Selector: SelectorFlags{CreatedBy: "alice@example.com"}})
+	if err == nil || !strings.Contains(err.Error(), "user ID") {
+		t.Errorf("expected an error asking for a user ID, got %v", err)
+	}
+}
+
+func TestRunLs_SelectorJSONIncludesLabels(t *testing.T) {
+	s := newTestStore()
+	s.workspaces = selectorTestWorkspaces()[:1]
+	out := captureStdout(t, func() {
+		if err := runLs(t, terminal.New(), s, nil, false); err != nil {
+			t.Fatalf("RunLs returned error: %v", err)
+		}
+	})
+	var parsed struct {
+		Workspaces []WorkspaceInfo `json:"workspaces"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("failed to parse JSON output: %v", err)
+	}
+	if got := parsed.Workspaces[0].Labels["team"]; got != "ml" {
+		t.Errorf("expected label team=ml, got %q", got)
+	}
+	if parsed.Workspaces[0].CreatedAt == "" {
+		t.Errorf("expected created_at in JSON output")
+	}
+}
+
+func TestRunLs_InvalidSelectors(t *testing.T) {
+	for _, flags := range []SelectorFlags{
+		{NameRegex: "("},
+		{OlderThan: "soon"},
+		{OlderThan: "-1h"},
+		{Labels: []string{"bad key=x"}},
+	} {
+		s := newTestStore()
+		err := RunLs(terminal.New(), resolveTestCLIAuth(t, s), s, nil, "", false, true, LsOptions{Selector: flags})
+		if err == nil {
+			t.Errorf("expected an error for %+v", flags)
+		}
+	}
+}
+
+func TestSelectorGPU(t *testing.T) {
+	s := &selector{gpus: upperSet([]string{"h100"})}
+	lookup := map[string]string{"p5.48xlarge": "H100", "g5.xlarge": "A10G"}
+	var names []string
+	for _, w := range s.filter(selectorTestWorkspaces(), lookup) {
+		names = append(names, w.Name)
+	}
+	if got := strings.Join(names, ","); got != "train-a,notebook" {
+		t.Errorf("expected train-a,notebook, got %q", got)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	cases := map[string]time.Duration{
+		"48h":  48 * time.Hour,
+		"90m":  90 * time.Minute,
+		"7d":   7 * 24 * time.Hour,
+		"1.5d": 36 * time.Hour,
+	}
+	for in, want := range cases {
+		got, err := ParseAge(in)
+		if err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+}
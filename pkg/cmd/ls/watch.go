@@ -0,0 +1,216 @@
+package ls
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/cenkalti/backoff/v4"
+)
+
+const (
+	watchInitialInterval = 2 * time.Second
+	watchMaxInterval     = 30 * time.Second
+	// watchMaxPollErrors is how many GetWorkspaces calls in a row may fail
+	watchMaxPollErrors = 5
+	// clearScreen moves the cursor home and clears the terminal
+	clearScreen = "\033[H\033[2J"
+)
+
+// watchSleep is replaced in tests
+var watchSleep = time.Sleep
+
+// untilCondition is a parsed --until: all, any or none of the selected
+// instances in one of statuses
+type untilCondition struct {
+	quantifier string
+	statuses   map[string]bool
+}
+
+// parseUntil parses --until: STATUS (all selected instances), all=STATUS,
+// any=STATUS or none (no instance is selected any more). Several statuses
+// are separated by '|', e.g. all=RUNNING|STOPPED.
+func parseUntil(s string) (*untilCondition, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if strings.EqualFold(s, "none") {
+		return &untilCondition{quantifier: "none"}, nil
+	}
+	quantifier, statuses, found := strings.Cut(s, "=")
+	if !found {
+		quantifier, statuses = "all", s
+	}
+	quantifier = strings.ToLower(quantifier)
+	if quantifier != "all" && quantifier != "any" {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --until %q: use STATUS, all=STATUS, any=STATUS or none", s))
+	}
+	c := &untilCondition{quantifier: quantifier, statuses: map[string]bool{}}
+	for _, status := range strings.Split(statuses, "|") {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if status == "" {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --until %q: missing status", s))
+		}
+		c.statuses[status] = true
+	}
+	return c, nil
+}
+
+// holds returns true once the condition is met
+func (c *untilCondition) holds(workspaces []entity.Workspace) bool {
+	switch c.quantifier {
+	case "none":
+		return len(workspaces) == 0
+	case "any":
+		for _, w := range workspaces {
+			if c.statuses[strings.ToUpper(getWorkspaceDisplayStatus(w))] {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, w := range workspaces {
+			if !c.statuses[strings.ToUpper(getWorkspaceDisplayStatus(w))] {
+				return false
+			}
+		}
+		return len(workspaces) > 0
+	}
+}
+
+func (c *untilCondition) String() string {
+	if c.quantifier == "none" {
+		return "no instances are selected"
+	}
+	statuses := make([]string, 0, len(c.statuses))
+	for status := range c.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	return fmt.Sprintf("%s selected instances are %s", c.quantifier, strings.Join(statuses, " or "))
+}
+
+// WatchWorkspaces polls the selected workspaces until the --until condition
+// holds. On a terminal it redraws the table in place; when piped it prints a
+// line for each status change. Polling backs off while nothing changes.
+func (ls Ls) WatchWorkspaces(cliAuth auth.CLIAuth, org *entity.Organization, showAll bool, until *untilCondition) error {
+	gpuLookup, prices := buildCatalogLookups(ls.lsStore)
+
+	b := backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(watchInitialInterval),
+		backoff.WithMaxInterval(watchMaxInterval),
+		backoff.WithMaxElapsedTime(0),
+		backoff.WithRandomizationFactor(0),
+	)
+	var sel *selector
+	var previous map[string]string
+	pollErrors := 0
+	for {
+		allWorkspaces, err := ls.lsStore.GetWorkspaces(org.ID, nil)
+		if err != nil {
+			pollErrors++
+			if pollErrors >= watchMaxPollErrors {
+				return breverrors.WrapAndTrace(err)
+			}
+			ls.terminal.Eprintf("failed to list instances, retrying: %v\n", err)
+			watchSleep(b.NextBackOff())
+			continue
+		}
+		pollErrors = 0
+
+		if previous == nil {
+			sel, err = ls.compileSelector(cliAuth, allWorkspaces)
+			if err != nil {
+				return err
+			}
+		}
+		workspaces, err := selectWorkspaces(cliAuth, allWorkspaces, showAll, sel, gpuLookup)
+		if err != nil {
+			return err
+		}
+
+		current := workspaceStatuses(workspaces)
+		changed := previous == nil || !statusesEqual(previous, current)
+		if ls.piped {
+			if previous != nil {
+				printTransitions(workspaces, previous, current)
+			}
+		} else if changed {
+			ls.drawWatch(org, workspaces, gpuLookup, prices, until)
+		}
+		previous = current
+
+		if until != nil && until.holds(workspaces) {
+			if !ls.piped {
+				ls.terminal.Vprint(ls.terminal.Green("Done: %s\n", until))
+			}
+			return nil
+		}
+		if changed {
+			b.Reset()
+		}
+		watchSleep(b.NextBackOff())
+	}
+}
+
+// drawWatch redraws the table in place
+func (ls Ls) drawWatch(org *entity.Organization, workspaces []entity.Workspace, gpuLookup map[string]string, prices map[string]float64, until *untilCondition) {
+	fmt.Print(clearScreen)
+	ls.terminal.Vprintf("%d instances in Org %s, updated %s (Ctrl-C to stop)\n", len(workspaces), ls.terminal.Yellow(org.Name), time.Now().Format("15:04:05"))
+	if until != nil {
+		ls.terminal.Vprintf("Waiting until %s\n", until)
+	}
+	if len(workspaces) > 0 {
+		displayWorkspacesTable(ls.terminal, workspaces, gpuLookup, prices)
+	}
+}
+
+// workspaceStatuses maps each workspace name to its display status
+func workspaceStatuses(workspaces []entity.Workspace) map[string]string {
+	statuses := make(map[string]string, len(workspaces))
+	for _, w := range workspaces {
+		statuses[w.Name] = getWorkspaceDisplayStatus(w)
+	}
+	return statuses
+}
+
+func statusesEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, status := range a {
+		if other, ok := b[name]; !ok || other != status {
+			return false
+		}
+	}
+	return true
+}
+
+// printTransitions prints "name OLD → NEW" for every change, with new
+// instances coming from nothing and removed ones going to "gone"
+func printTransitions(workspaces []entity.Workspace, previous, current map[string]string) {
+	for _, w := range workspaces {
+		status := current[w.Name]
+		old, seen := previous[w.Name]
+		switch {
+		case !seen:
+			fmt.Printf("%s → %s\n", w.Name, status)
+		case old != status:
+			fmt.Printf("%s %s → %s\n", w.Name, old, status)
+		}
+	}
+	var removed []string
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		fmt.Printf("%s %s → gone\n", name, previous[name])
+	}
+}
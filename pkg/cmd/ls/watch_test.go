@@ -0,0 +1,186 @@
+package ls
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+)
+
+// mockWatchStore returns one workspace list per poll, repeating the last
+type mockWatchStore struct {
+	*mockLsStore
+	polls [][]entity.Workspace
+	errs  []error
+	calls int
+}
+
+func (m *mockWatchStore) GetWorkspaces(_ string, _ *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	i := m.calls
+	m.calls++
+	if i < len(m.errs) && m.errs[i] != nil {
+		return nil, m.errs[i]
+	}
+	if i >= len(m.polls) {
+		i = len(m.polls) - 1
+	}
+	return m.polls[i], nil
+}
+
+func stubWatchSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var slept []time.Duration
+	orig := watchSleep
+	watchSleep = func(d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { watchSleep = orig })
+	return &slept
+}
+
+func trainWorkspaces(statuses ...string) []entity.Workspace {
+	var workspaces []entity.Workspace
+	for i, status := range statuses {
+		workspaces = append(workspaces, entity.Workspace{
+			ID:              "ws" + string(rune('1'+i)),
+			Name:            "train-0" + string(rune('1'+i)),
+			Status:          status,
+			CreatedByUserID: "u1",
+		})
+	}
+	return workspaces
+}
+
+func TestParseUntil(t *testing.T) {
+	running := trainWorkspaces(entity.Running, entity.Running)
+	mixed := trainWorkspaces(entity.Running, entity.Deploying)
+	cases := []struct {
+		until      string
+		workspaces []entity.Workspace
+		want       bool
+	}{
+		{"RUNNING", running, true},
+		{"running", mixed, false},
+		{"all=RUNNING|DEPLOYING", mixed, true},
+		{"any=DEPLOYING", mixed, true},
+		{"any=FAILURE", mixed, false},
+		{"RUNNING", nil, false},
+		{"none", nil, true},
+		{"none", running, false},
+	}
+	for _, tc := range cases {
+		c, err := parseUntil(tc.until)
+		if err != nil {
+			t.Fatalf("parseUntil(%q): %v", tc.until, err)
+		}
+		if got := c.holds(tc.workspaces); got != tc.want {
+			t.Errorf("%q holds = %v, want %v", tc.until, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"some=RUNNING", "all=", "RUNNING||STOPPED"} {
+		if _, err := parseUntil(bad); err == nil {
+			t.Errorf("expected an error for --until %q", bad)
+		}
+	}
+}
+
+func TestWatchWorkspaces_PipedPrintsTransitions(t *testing.T) {
+	slept := stubWatchSleep(t)
+	s := &mockWatchStore{mockLsStore: newTestStore(), polls: [][]entity.Workspace{
+		trainWorkspaces(entity.Deploying, entity.Deploying),
+		trainWorkspaces(entity.Deploying, entity.Deploying),
+		trainWorkspaces(entity.Running, entity.Deploying),
+		append(trainWorkspaces(entity.Running, entity.Running), entity.Workspace{ID: "ws9", Name: "train-09", Status: entity.Deploying, CreatedByUserID: "u1"}),
+		trainWorkspaces(entity.Running, entity.Running),
+	}}
+	ls := NewLs(s, terminal.New(), false)
+	ls.piped = true
+	until, _ := parseUntil("RUNNING")
+
+	out := captureStdout(t, func() {
+		if err := ls.WatchWorkspaces(resolveTestCLIAuth(t, s.mockLsStore), s.org, false, until); err != nil {
+			t.Fatalf("WatchWorkspaces: %v", err)
+		}
+	})
+
+	want := "train-01 DEPLOYING → RUNNING\n" +
+		"train-02 DEPLOYING → RUNNING\n" +
+		"train-09 → DEPLOYING\n" +
+		"train-09 DEPLOYING → gone\n"
+	if out != want {
+		t.Errorf("unexpected transitions:\n%s\nwant:\n%s", out, want)
+	}
+	if s.calls != 5 {
+		t.Errorf("expected 5 polls, got %d", s.calls)
+	}
+	// unchanged polls back off, changes reset the interval
+	if len(*slept) != 4 || (*slept)[1] <= (*slept)[0] || (*slept)[2] > (*slept)[1] {
+		t.Errorf("unexpected poll intervals %v", *slept)
+	}
+}
+
+func TestWatchWorkspaces_TTYRedraws(t *testing.T) {
+	stubWatchSleep(t)
+	s := &mockWatchStore{mockLsStore: newTestStore(), polls: [][]entity.Workspace{
+		trainWorkspaces(entity.Deploying),
+		trainWorkspaces(entity.Running),
+	}}
+	ls := NewLs(s, terminal.New(), false)
+	ls.piped = false
+	until, _ := parseUntil("RUNNING")
+
+	out := captureStdout(t, func() {
+		if err := ls.WatchWorkspaces(resolveTestCLIAuth(t, s.mockLsStore), s.org, false, until); err != nil {
+			t.Fatalf("WatchWorkspaces: %v", err)
+		}
+	})
+	if strings.Count(out, clearScreen) != 2 {
+		t.Errorf("expected the table to be drawn twice, got:\n%q", out)
+	}
+}
+
+func TestWatchWorkspaces_RetriesPollErrors(t *testing.T) {
+	stubWatchSleep(t)
+	s := &mockWatchStore{
+		mockLsStore: newTestStore(),
+		polls:       [][]entity.Workspace{trainWorkspaces(entity.Running)},
+		errs:        []error{errors.New("timeout"), errors.New("timeout")},
+	}
+	ls := NewLs(s, terminal.New(), false)
+	ls.piped = true
+	until, _ := parseUntil("RUNNING")
+	if err := ls.WatchWorkspaces(resolveTestCLIAuth(t, s.mockLsStore), s.org, false, until); err != nil {
+		t.Fatalf("WatchWorkspaces: %v", err)
+	}
+
+	s = &mockWatchStore{mockLsStore: newTestStore(), errs: make([]error, watchMaxPollErrors)}
+	for i := range s.errs {
+		s.errs[i] = errors.New("down")
+	}
+	ls = NewLs(s, terminal.New(), false)
+	if err := ls.WatchWorkspaces(resolveTestCLIAuth(t, s.mockLsStore), s.org, false, until); err == nil {
+		t.Fatal("expected an error after repeated poll failures")
+	}
+}
+
+func TestRunLs_WatchFlagConflicts(t *testing.T) {
+	s := newTestStore()
+	cliAuth := resolveTestCLIAuth(t, s)
+	for _, tc := range []struct {
+		args []string
+		json bool
+		opts LsOptions
+	}{
+		{nil, true, LsOptions{Watch: true}},
+		{nil, false, LsOptions{Until: "RUNNING"}},
+		{[]string{"orgs"}, false, LsOptions{Watch: true}},
+		{nil, false, LsOptions{Watch: true, Until: "maybe=RUNNING"}},
+	} {
+		if err := RunLs(terminal.New(), cliAuth, s, tc.args, "", false, tc.json, tc.opts); err == nil {
+			t.Errorf("expected an error for %+v", tc)
+		}
+	}
+}
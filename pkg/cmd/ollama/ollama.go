@@ -2,7 +2,9 @@
 package ollama
 
 import (
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -173,7 +175,7 @@ func runOllamaWorkspace(t *terminal.Terminal, opts RunOptions, ollamaStore Ollam
 	}
 
 	var vmStatus bool
-	vmStatus, err = pollInstanceUntilVMReady(w, time.Second, time.Minute*5, ollamaStore)
+	vmStatus, err = pollInstanceUntilVMReady(w, time.Minute*5, ollamaStore)
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
 	}
@@ -204,7 +206,7 @@ func runOllamaWorkspace(t *terminal.Terminal, opts RunOptions, ollamaStore Ollam
 
 	var vstatus bool
 	// TODO: 15 min for now because the image is not cached and takes a while to build. Remove this when the image is cached
-	vstatus, err = pollInstanceUntilVerbContainerReady(w, time.Second, time.Minute*20, ollamaStore)
+	vstatus, err = pollInstanceUntilVerbContainerReady(w, time.Minute*20, ollamaStore)
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
 	}
@@ -259,38 +261,53 @@ func displayOllamaConnectBreadCrumb(t *terminal.Terminal, link string, model str
 	t.Vprintf("%s", t.Yellow(fmt.Sprintf("curl %s/api/chat -d '{\n  \"model\": \"%s\",\n  \"messages\": [\n    {\n      \"role\": \"user\",\n      \"content\": \"why is the sky blue?\"\n    }\n  ]\n}'\n", link, model)))
 }
 
-func pollInstanceUntilVMReady(workspace *entity.Workspace, interval time.Duration, timeout time.Duration, ollamaStore OllamaStore) (bool, error) {
-	elapsedTime := time.Duration(0)
-
-	for elapsedTime < timeout {
-		w, err := ollamaStore.GetWorkspace(workspace.ID)
-		if err != nil {
-			return false, breverrors.WrapAndTrace(err)
-		} else if w.Status == "RUNNING" {
-			// adding a slight delay to make sure the instance is ready
-			time.Sleep(time.Minute * 1)
-			return true, nil
-		}
-		time.Sleep(interval)
-		elapsedTime += interval
+func pollInstanceUntilVMReady(workspace *entity.Workspace, timeout time.Duration, ollamaStore OllamaStore) (bool, error) {
+	ready, err := pollInstanceUntil(workspace, timeout, ollamaStore, func(w *entity.Workspace) bool {
+		return w.Status == "RUNNING"
+	})
+	if err != nil {
+		return false, err
 	}
-	return false, breverrors.New("Timeout waiting for machine to start")
+	if !ready {
+		return false, breverrors.New("Timeout waiting for machine to start")
+	}
+	// adding a slight delay to make sure the instance is ready
+	time.Sleep(time.Minute * 1)
+	return true, nil
 }
 
-func pollInstanceUntilVerbContainerReady(workspace *entity.Workspace, interval time.Duration, timeout time.Duration, ollamaStore OllamaStore) (bool, error) {
-	elapsedTime := time.Duration(0)
+func pollInstanceUntilVerbContainerReady(workspace *entity.Workspace, timeout time.Duration, ollamaStore OllamaStore) (bool, error) {
+	ready, err := pollInstanceUntil(workspace, timeout, ollamaStore, func(w *entity.Workspace) bool {
+		return w.VerbBuildStatus == entity.Completed
+	})
+	if err != nil {
+		return false, err
+	}
+	if !ready {
+		return false, breverrors.New("Timeout waiting for container to build")
+	}
+	return true, nil
+}
 
-	for elapsedTime < timeout {
+// pollInstanceUntil polls the workspace until ready returns true, returning
+// false once timeout passes
+func pollInstanceUntil(workspace *entity.Workspace, timeout time.Duration, ollamaStore OllamaStore, ready func(*entity.Workspace) bool) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := util.Poll(ctx, util.DefaultPollOptions, func() (bool, error) {
 		w, err := ollamaStore.GetWorkspace(workspace.ID)
 		if err != nil {
 			return false, breverrors.WrapAndTrace(err)
-		} else if w.VerbBuildStatus == entity.Completed {
-			return true, nil
 		}
-		time.Sleep(interval)
-		elapsedTime += interval
+		return ready(w), nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, breverrors.WrapAndTrace(err)
 	}
-	return false, breverrors.New("Timeout waiting for container to build")
+	return true, nil
 }
 
 func getOllamaTunnelLink(workspace *entity.Workspace) (string, string, error) {
@@ -2,6 +2,7 @@ package open
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -266,9 +267,12 @@ func handleSetDefault(t *terminal.Terminal, editorType string) error {
 		return breverrors.WrapAndTrace(err)
 	}
 
-	settings := &files.PersonalSettings{
-		DefaultEditor: editorType,
+	// Keep the other settings (analytics, search presets, ...)
+	settings, err := files.ReadPersonalSettings(files.AppFs, homeDir)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
 	}
+	settings.DefaultEditor = editorType
 
 	err = files.WritePersonalSettings(files.AppFs, homeDir, settings)
 	if err != nil {
@@ -421,23 +425,22 @@ func pushOpenAnalytics(tstore OpenStore, workspace *entity.Workspace) error {
 
 func pollUntil(t *terminal.Terminal, wsid string, state string, openStore OpenStore) error {
 	s := t.NewSpinner()
-	isReady := false
 	s.Suffix = " hang tight 🤙"
 	s.Start()
-	for !isReady {
-		time.Sleep(5 * time.Second)
+	err := util.Poll(context.Background(), util.DefaultPollOptions, func() (bool, error) {
 		ws, err := openStore.GetWorkspace(wsid)
 		if err != nil {
-			return breverrors.WrapAndTrace(err)
+			return false, breverrors.WrapAndTrace(err)
 		}
 		s.Suffix = "  instance is currently " + strings.ToLower(ws.Status)
-		if ws.Status == state {
-			s.Suffix = "Instance is ready!"
-			s.Stop()
-			isReady = true
+		if ws.Status != state {
+			return false, nil
 		}
-	}
-	return nil
+		s.Suffix = "Instance is ready!"
+		s.Stop()
+		return true, nil
+	})
+	return breverrors.WrapAndTrace(err)
 }
 
 func startWorkspaceIfStopped(t *terminal.Terminal, tstore OpenStore, wsIDOrName string, workspace *entity.Workspace) error {
@@ -0,0 +1,157 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/alessio/shellescape"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+)
+
+const (
+	attemptTimeout = 10 * time.Second
+	tunnelWait     = 15 * time.Second
+	// killWait is how long to wait for ssh's output after killing it
+	killWait = time.Second
+)
+
+// sshBinary is the ssh client; a var so tests can stub it
+var sshBinary = "ssh"
+
+// SSHChecker runs probes with the local ssh client, using the Brev SSH config
+type SSHChecker struct{}
+
+// Check runs one attempt of the probe, killing ssh once ctx is done
+func (SSHChecker) Check(ctx context.Context, sshAlias string, probe Probe) error {
+	switch probe.Kind {
+	case KindSSH:
+		return runRemote(ctx, sshAlias, probe.Target)
+	case KindFile:
+		return runRemote(ctx, sshAlias, "test -e "+shellescape.Quote(probe.Target))
+	case KindTCP:
+		if strings.Contains(probe.Target, ":") {
+			return dialTCP(ctx, probe.Target)
+		}
+		return runRemote(ctx, sshAlias, fmt.Sprintf("timeout %d bash -c '</dev/tcp/127.0.0.1/%s'", int(attemptTimeout.Seconds()), probe.Target))
+	case KindHTTP:
+		if isURL(probe.Target) {
+			return httpGet(ctx, probe.Target)
+		}
+		return forwardedHTTPGet(ctx, sshAlias, probe.Target)
+	default:
+		return fmt.Errorf("unknown probe kind %q", probe.Kind)
+	}
+}
+
+// runRemote runs a command on the instance and returns the last line of output on failure
+func runRemote(ctx context.Context, sshAlias, command string) error {
+	cmd := exec.CommandContext(ctx, sshBinary, "-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10", "-o", "LogLevel=ERROR", sshAlias, command) //nolint:gosec // probe commands are user input
+	cmd.WaitDelay = killWait
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if msg := lastLine(string(out)); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+	return breverrors.WrapAndTrace(err)
+}
+
+func dialTCP(ctx context.Context, address string) error {
+	dialer := net.Dialer{Timeout: attemptTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+func httpGet(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	client := &http.Client{Timeout: attemptTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // body is not read
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// forwardedHTTPGet forwards the instance port to a free local port over SSH
+// for the duration of one request. target is <port>[/path].
+func forwardedHTTPGet(ctx context.Context, sshAlias, target string) error {
+	remotePort, path, _ := strings.Cut(target, "/")
+
+	localPort, err := freeLocalPort()
+	if err != nil {
+		return err
+	}
+	local := fmt.Sprintf("127.0.0.1:%d", localPort)
+
+	tunnel := exec.CommandContext(ctx, sshBinary, "-N", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10", "-o", "LogLevel=ERROR", "-o", "ExitOnForwardFailure=yes", //nolint:gosec // alias and port are validated
+		"-L", fmt.Sprintf("%s:localhost:%s", local, remotePort), sshAlias)
+	if err := tunnel.Start(); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- tunnel.Wait() }()
+	defer func() {
+		_ = tunnel.Process.Kill()
+	}()
+
+	if err := waitForListener(ctx, local, exited); err != nil {
+		return err
+	}
+	return httpGet(ctx, fmt.Sprintf("http://%s/%s", local, path))
+}
+
+// waitForListener waits until the tunnel accepts local connections, exits or
+// ctx is done
+func waitForListener(ctx context.Context, address string, exited <-chan error) error {
+	deadline := time.Now().Add(tunnelWait)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-exited:
+			if err == nil {
+				err = errors.New("ssh exited")
+			}
+			return fmt.Errorf("could not forward port: %w", err)
+		case <-ctx.Done():
+			return breverrors.WrapAndTrace(ctx.Err())
+		default:
+		}
+		if conn, err := net.DialTimeout("tcp", address, time.Second); err == nil {
+			_ = conn.Close()
+			return nil
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	return errors.New("timed out forwarding port over ssh")
+}
+
+func freeLocalPort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, breverrors.WrapAndTrace(err)
+	}
+	defer l.Close() //nolint:errcheck // only used to find a port
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,303 @@
+// Package readiness checks that an instance is usable, not only RUNNING
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/refresh"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Probe kinds
+const (
+	KindSSH  = "ssh"
+	KindTCP  = "tcp"
+	KindHTTP = "http"
+	KindFile = "file"
+)
+
+// Defaults for probes that do not set timeout or interval
+const (
+	DefaultTimeout  = 10 * time.Minute
+	DefaultInterval = 10 * time.Second
+)
+
+// MaxAttempt is the longest one attempt of a probe may run, e.g. an ssh
+// command that hangs, before it counts as failed
+const MaxAttempt = time.Minute
+
+// FlagHelp describes the --ready-when syntax
+const FlagHelp = `Wait until a probe passes before reporting the instance as ready (repeatable):
+  ssh:<command>        command over SSH exits 0
+  tcp:<port>           port accepts connections on the instance
+  tcp:<host>:<port>    host:port accepts connections from this machine
+  http:<port>[/path]   instance port, forwarded over SSH, returns 2xx
+  http(s)://<url>      URL returns 2xx
+  file:<path>          file exists on the instance
+Set timeout and interval per probe with kind[timeout=20m,interval=5s]:target,
+e.g. http[timeout=5m]:https://<url>`
+
+var (
+	now   = time.Now
+	sleep = time.Sleep
+)
+
+// Probe is one readiness check
+type Probe struct {
+	Kind     string
+	Target   string
+	Timeout  time.Duration
+	Interval time.Duration
+}
+
+// String returns the probe as written on the command line, without options
+func (p Probe) String() string {
+	if p.Kind == KindHTTP && isURL(p.Target) {
+		return p.Target
+	}
+	return p.Kind + ":" + p.Target
+}
+
+// AddFlags registers --ready-when on a command
+func AddFlags(cmd *cobra.Command, readyWhen *[]string) {
+	cmd.Flags().StringArrayVar(readyWhen, "ready-when", nil, FlagHelp)
+}
+
+// ParseProbes parses --ready-when values
+func ParseProbes(values []string) ([]Probe, error) {
+	probes := make([]Probe, 0, len(values))
+	for _, value := range values {
+		probe, err := ParseProbe(value)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
+// ParseProbe parses kind[options]:target, or an http(s) URL. An http probe
+// with options takes a URL as its target, e.g. http[timeout=5s]:https://x.
+func ParseProbe(value string) (Probe, error) {
+	probe := Probe{Timeout: DefaultTimeout, Interval: DefaultInterval}
+	value = strings.TrimSpace(value)
+	if isURL(value) {
+		probe.Kind = KindHTTP
+		probe.Target = value
+		return probe, nil
+	}
+
+	head, target, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(target) == "" {
+		return probe, invalidProbe(value, "expected kind:target, e.g. ssh:'test -f /tmp/done' or tcp:8888")
+	}
+	kind := head
+	if i := strings.Index(head, "["); i >= 0 {
+		if !strings.HasSuffix(head, "]") {
+			return probe, invalidProbe(value, "options must be written as kind[timeout=5m,interval=10s]")
+		}
+		kind = head[:i]
+		if err := parseOptions(&probe, head[i+1:len(head)-1]); err != nil {
+			return probe, invalidProbe(value, err.Error())
+		}
+	}
+	probe.Kind = kind
+	probe.Target = strings.TrimSpace(target)
+
+	if err := validateTarget(probe); err != nil {
+		return probe, invalidProbe(value, err.Error())
+	}
+	return probe, nil
+}
+
+func parseOptions(probe *Probe, options string) error {
+	for _, option := range strings.Split(options, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(option), "=")
+		if !ok {
+			return fmt.Errorf("option %q must be key=value", option)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%s must be a positive duration like 30s or 5m", key)
+		}
+		switch key {
+		case "timeout":
+			probe.Timeout = d
+		case "interval":
+			probe.Interval = d
+		default:
+			return fmt.Errorf("unknown option %q (use timeout or interval)", key)
+		}
+	}
+	return nil
+}
+
+func validateTarget(p Probe) error {
+	switch p.Kind {
+	case KindSSH, KindFile:
+		return nil
+	case KindTCP:
+		port := p.Target
+		if i := strings.LastIndex(p.Target, ":"); i >= 0 {
+			if i == 0 {
+				return errors.New("host must not be empty")
+			}
+			port = p.Target[i+1:]
+		}
+		return validatePort(port)
+	case KindHTTP:
+		if isURL(p.Target) {
+			return nil
+		}
+		port, _, _ := strings.Cut(p.Target, "/")
+		return validatePort(port)
+	default:
+		return fmt.Errorf("unknown probe kind %q (use ssh, tcp, http or file)", p.Kind)
+	}
+}
+
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+func invalidProbe(value, reason string) error {
+	return breverrors.NewValidationError(fmt.Sprintf("invalid --ready-when %q: %s", value, reason))
+}
+
+func isURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// Checker runs a single attempt of a probe against the instance at sshAlias.
+// The attempt must give up once ctx is done.
+type Checker interface {
+	Check(ctx context.Context, sshAlias string, probe Probe) error
+}
+
+// Result is the outcome of waiting for one probe
+type Result struct {
+	Probe    Probe
+	Passed   bool
+	Skipped  bool // an earlier probe failed
+	Attempts int
+	Elapsed  time.Duration
+	Err      error // last error, when not passed
+}
+
+// String formats the result for summaries
+func (r Result) String() string {
+	switch {
+	case r.Passed:
+		return fmt.Sprintf("passed %s (%d attempt(s), %s)", r.Probe, r.Attempts, r.Elapsed.Round(time.Second))
+	case r.Skipped:
+		return fmt.Sprintf("skipped %s (an earlier probe failed)", r.Probe)
+	default:
+		return fmt.Sprintf("failed %s after %s: %v", r.Probe, r.Elapsed.Round(time.Second), r.Err)
+	}
+}
+
+// Mark returns a check mark for the result
+func (r Result) Mark() string {
+	switch {
+	case r.Passed:
+		return "✓"
+	case r.Skipped:
+		return "-"
+	default:
+		return "✗"
+	}
+}
+
+// AllPassed returns true if every probe passed
+func AllPassed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// FirstFailure returns the first result that did not pass, or nil
+func FirstFailure(results []Result) *Result {
+	for i := range results {
+		if !results[i].Passed {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+// Wait runs the probes in order, retrying each every interval until it passes
+// or its timeout expires. Probes after a failed one are skipped.
+func Wait(checker Checker, sshAlias string, probes []Probe, logf func(format string, a ...interface{})) []Result {
+	results := make([]Result, 0, len(probes))
+	failed := false
+	for _, probe := range probes {
+		if failed {
+			results = append(results, Result{Probe: probe, Skipped: true})
+			continue
+		}
+		logf("  %s: waiting for %s\n", sshAlias, probe)
+		result := waitForProbe(checker, sshAlias, probe)
+		if !result.Passed {
+			failed = true
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func waitForProbe(checker Checker, sshAlias string, probe Probe) Result {
+	start := now()
+	deadline := start.Add(probe.Timeout)
+	result := Result{Probe: probe}
+	for {
+		result.Attempts++
+		err := checkAttempt(checker, sshAlias, probe, deadline.Sub(now()))
+		result.Elapsed = now().Sub(start)
+		if err == nil {
+			result.Passed = true
+			return result
+		}
+		result.Err = err
+		if !now().Add(probe.Interval).Before(deadline) {
+			return result
+		}
+		sleep(probe.Interval)
+	}
+}
+
+// checkAttempt runs one attempt, bounded by the time left for the probe and MaxAttempt
+func checkAttempt(checker Checker, sshAlias string, probe Probe, remaining time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), min(remaining, MaxAttempt))
+	defer cancel()
+	err := checker.Check(ctx, sshAlias, probe)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("attempt timed out after %s: %w", min(remaining, MaxAttempt).Round(time.Second), err)
+	}
+	return err
+}
+
+// RefreshSSHConfig updates the SSH config so new instances can be reached by
+// name. Stores that cannot refresh are left alone.
+func RefreshSSHConfig(s interface{}) error {
+	refreshStore, ok := s.(refresh.RefreshStore)
+	if !ok {
+		return nil
+	}
+	if err := refresh.RunRefreshAsync(refreshStore).Await(); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProbe(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Probe
+	}{
+		{"ssh:test -f /tmp/done", Probe{Kind: KindSSH, Target: "test -f /tmp/done", Timeout: DefaultTimeout, Interval: DefaultInterval}},
+		{"tcp:8888", Probe{Kind: KindTCP, Target: "8888", Timeout: DefaultTimeout, Interval: DefaultInterval}},
+		{"tcp:10.0.0.1:22", Probe{Kind: KindTCP, Target: "10.0.0.1:22", Timeout: DefaultTimeout, Interval: DefaultInterval}},
+		{"http:8888/api/status", Probe{Kind: KindHTTP, Target: "8888/api/status", Timeout: DefaultTimeout, Interval: DefaultInterval}},
+		{"https://example.com/health", Probe{Kind: KindHTTP, Target: "https://example.com/health", Timeout: DefaultTimeout, Interval: DefaultInterval}},
+		{"http[timeout=5s]:https://example.com/health", Probe{Kind: KindHTTP, Target: "https://example.com/health", Timeout: 5 * time.Second, Interval: DefaultInterval}},
+		{"file[timeout=20m,interval=5s]:/tmp/setup-done", Probe{Kind: KindFile, Target: "/tmp/setup-done", Timeout: 20 * time.Minute, Interval: 5 * time.Second}},
+		{"ssh[timeout=1m]:curl -fs localhost:8080", Probe{Kind: KindSSH, Target: "curl -fs localhost:8080", Timeout: time.Minute, Interval: DefaultInterval}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseProbe(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseProbeErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"ssh:",
+		"ftp:21",
+		"tcp:abc",
+		"tcp:70000",
+		"tcp::22",
+		"http:/health",
+		"tcp[timeout=5s]:https://example.com",
+		"file[timeout=soon]:/tmp/x",
+		"file[retries=3]:/tmp/x",
+		"file[timeout=1m:/tmp/x",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseProbe(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+type fakeChecker struct {
+	failures map[string]int // probe target -> attempts that fail before passing
+	calls    map[string]int
+}
+
+func (f *fakeChecker) Check(_ context.Context, _ string, probe Probe) error {
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[probe.Target]++
+	if f.calls[probe.Target] <= f.failures[probe.Target] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func withFakeClock(t *testing.T) {
+	t.Helper()
+	current := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+	sleep = func(d time.Duration) { current = current.Add(d) }
+	t.Cleanup(func() {
+		now = time.Now
+		sleep = time.Sleep
+	})
+}
+
+func nopLogf(string, ...interface{}) {}
+
+func TestWaitRetriesUntilPassed(t *testing.T) {
+	withFakeClock(t)
+	checker := &fakeChecker{failures: map[string]int{"/tmp/done": 2}}
+	probes := []Probe{{Kind: KindFile, Target: "/tmp/done", Timeout: time.Minute, Interval: 10 * time.Second}}
+
+	results := Wait(checker, "box", probes, nopLogf)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, 3, results[0].Attempts)
+	assert.Equal(t, 20*time.Second, results[0].Elapsed)
+	assert.True(t, AllPassed(results))
+	assert.Contains(t, results[0].String(), "passed file:/tmp/done (3 attempt(s), 20s)")
+}
+
+func TestWaitTimesOutAndSkipsLaterProbes(t *testing.T) {
+	withFakeClock(t)
+	checker := &fakeChecker{failures: map[string]int{"8888": 100}}
+	probes := []Probe{
+		{Kind: KindTCP, Target: "8888", Timeout: 30 * time.Second, Interval: 10 * time.Second},
+		{Kind: KindFile, Target: "/tmp/done", Timeout: time.Minute, Interval: time.Second},
+	}
+
+	results := Wait(checker, "box", probes, nopLogf)
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Passed)
+	assert.Equal(t, 3, results[0].Attempts)
+	assert.EqualError(t, results[0].Err, "connection refused")
+	assert.True(t, results[1].Skipped)
+	assert.Zero(t, checker.calls["/tmp/done"])
+
+	failure := FirstFailure(results)
+	require.NotNil(t, failure)
+	assert.Equal(t, "failed tcp:8888 after 20s: connection refused", failure.String())
+	assert.Equal(t, "✗", results[0].Mark())
+	assert.Equal(t, "-", results[1].Mark())
+}
+
+// hangingChecker never finishes an attempt on its own
+type hangingChecker struct{}
+
+func (hangingChecker) Check(ctx context.Context, _ string, _ Probe) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWaitBoundsHungAttempts(t *testing.T) {
+	probe := Probe{Kind: KindSSH, Target: "sleep infinity", Timeout: 100 * time.Millisecond, Interval: 10 * time.Millisecond}
+	start := time.Now()
+	results := Wait(hangingChecker{}, "inst", []Probe{probe}, nopLogf)
+	require.Len(t, results, 1)
+	assert.False(t, results[0].Passed)
+	assert.ErrorContains(t, results[0].Err, "attempt timed out")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestSSHCheckerKillsHungCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as ssh")
+	}
+	// an ssh whose remote command never returns; sleep keeps the output
+	// open after the shell is killed
+	fakeSSH := filepath.Join(t.TempDir(), "ssh")
+	require.NoError(t, os.WriteFile(fakeSSH, []byte("#!/bin/sh\nsleep 600\n"), 0o700)) //nolint:gosec // test script
+	orig := sshBinary
+	sshBinary = fakeSSH
+	t.Cleanup(func() { sshBinary = orig })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := SSHChecker{}.Check(ctx, "inst", Probe{Kind: KindSSH, Target: "true"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
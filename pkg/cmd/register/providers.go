@@ -18,22 +18,7 @@ type LinuxPlatform struct{}
 func (LinuxPlatform) IsCompatible() bool { return runtime.GOOS == "linux" }
 
 // TerminalPrompter wraps terminal.PromptSelectInput for interactive prompts.
-type TerminalPrompter struct{}
-
-func (TerminalPrompter) ConfirmYesNo(label string) bool {
-	result := terminal.PromptSelectInput(terminal.PromptSelectContent{
-		Label: label,
-		Items: []string{"Yes, proceed", "No, cancel"},
-	})
-	return result == "Yes, proceed"
-}
-
-func (TerminalPrompter) Select(label string, items []string) string {
-	return terminal.PromptSelectInput(terminal.PromptSelectContent{
-		Label: label,
-		Items: items,
-	})
-}
+type TerminalPrompter = terminal.Prompter
 
 // Netbird handles NetBird installation and uninstallation.
 type Netbird struct{}
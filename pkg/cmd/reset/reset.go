@@ -3,6 +3,7 @@ package reset
 import (
 	_ "embed"
 
+	"github.com/brevdev/brev-cli/pkg/cmd/bulk"
 	"github.com/brevdev/brev-cli/pkg/cmd/completions"
 	"github.com/brevdev/brev-cli/pkg/cmd/util"
 	"github.com/brevdev/brev-cli/pkg/entity"
@@ -16,7 +17,9 @@ import (
 var (
 	//go:embed doc.md
 	long         string
-	startExample = `brev reset <ws_name>...`
+	startExample = `brev reset <ws_name>...
+echo instance-name | brev reset
+brev reset --status FAILURE --parallel 2`
 )
 
 type ResetStore interface {
@@ -26,6 +29,8 @@ type ResetStore interface {
 }
 
 func NewCmdReset(t *terminal.Terminal, loginResetStore ResetStore, noLoginResetStore ResetStore) *cobra.Command {
+	var flags bulk.Flags
+
 	cmd := &cobra.Command{
 		Annotations:           map[string]string{"provider-dependent": ""},
 		Use:                   "reset",
@@ -35,18 +40,47 @@ func NewCmdReset(t *terminal.Terminal, loginResetStore ResetStore, noLoginResetS
 		Example:               startExample,
 		ValidArgsFunction:     completions.GetAllWorkspaceNameCompletionHandler(noLoginResetStore, t),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			for _, arg := range args {
-				err := resetWorkspace(arg, t, loginResetStore)
-				if err != nil {
-					return breverrors.WrapAndTrace(err)
-				}
+			names, stdinPiped := util.GetInstanceNamesWithPipeInfo(args)
+			opts := bulk.Options{Names: names, Confirmer: bulk.Prompter(stdinPiped), Piped: util.IsStdoutPiped()}
+			if bulk.UseBulk(flags, opts) {
+				return bulk.Run(t, loginResetStore, resetOperation(loginResetStore), flags, opts)
+			}
+			if len(names) == 0 {
+				return breverrors.NewValidationError("instance name required: provide as argument or pipe from another command")
+			}
+			err := resetWorkspace(names[0], t, loginResetStore)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
 			}
 			return nil
 		},
 	}
+	bulk.AddFlags(cmd, &flags)
 	return cmd
 }
 
+// resetOperation resets instances
+func resetOperation(resetStore ResetStore) bulk.Operation {
+	return bulk.Operation{
+		Verb: "reset",
+		Done: "reset",
+		Resolve: func(name string) (*entity.Workspace, error) {
+			workspace, err := util.GetUserWorkspaceByNameOrIDErr(resetStore, name)
+			if err != nil {
+				return nil, breverrors.WrapAndTrace(err)
+			}
+			return workspace, nil
+		},
+		Do: func(w entity.Workspace) error {
+			_, err := resetStore.ResetWorkspace(w.ID)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			return nil
+		},
+	}
+}
+
 func resetWorkspace(workspaceName string, t *terminal.Terminal, resetStore ResetStore) error {
 	workspace, err := util.GetUserWorkspaceByNameOrIDErr(resetStore, workspaceName)
 	if err != nil {
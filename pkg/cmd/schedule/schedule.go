@@ -0,0 +1,513 @@
+// Package schedule starts and stops instances on cron schedules kept in
+// ~/.brev/schedules.json and run by a local daemon
+package schedule
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/autostartconf"
+	"github.com/brevdev/brev-cli/pkg/cmd/cmderrors"
+	"github.com/brevdev/brev-cli/pkg/cmd/completions"
+	"github.com/brevdev/brev-cli/pkg/cmd/ls"
+	cmdutil "github.com/brevdev/brev-cli/pkg/cmd/util"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/tasks"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleLong = `Start and stop instances on cron schedules.
+
+Rules are kept in ~/.brev/schedules.json and run by a daemon on this machine,
+so they only run while it is up. Install it as a systemd service with
+'sudo brev schedule install', or run 'brev schedule daemon --background'.
+
+Rules are standard five field cron expressions (minute hour day month weekday)
+in the daemon's local time, or in --timezone.
+
+Runs missed while the daemon was not running are caught up by --catch-up:
+  last  run the most recent missed start or stop once the daemon is back (default)
+  skip  only record the missed run in the history
+
+Commands:
+  add        Add a rule that starts and stops instances
+  ls         List schedules (the default)
+  history    Show the starts and stops schedules ran
+  rm         Remove schedules
+  daemon     Run the schedules until stopped
+  install    Install the schedule daemon as a systemd service (needs sudo)
+  uninstall  Remove the schedule daemon's systemd service (needs sudo)`
+	scheduleExample = `  brev schedule add my-instance --stop "0 20 * * 1-5" --start "0 8 * * 1-5"
+  brev schedule add --name-regex '^train-' --stop "0 20 * * *" --timezone Europe/Berlin
+  brev schedule ls
+  brev schedule history 1
+  brev schedule rm 1
+  sudo brev schedule install`
+)
+
+// ScheduleStore is what the schedule commands and the daemon need
+type ScheduleStore interface {
+	SchedulerStore
+	GetActiveOrganizationOrDefault() (*entity.Organization, error)
+	autostartconf.AutoStartStore
+	tasks.RunTaskAsDaemonStore
+}
+
+func NewCmdSchedule(t *terminal.Terminal, loginScheduleStore ScheduleStore, noLoginScheduleStore completions.CompletionStore) *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations:           map[string]string{"workspace": ""},
+		Use:                   "schedule",
+		DisableFlagsInUseLine: true,
+		Short:                 "Start and stop instances on a schedule",
+		Long:                  scheduleLong,
+		Example:               scheduleExample,
+		Args:                  cmderrors.TransformToValidationError(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listSchedules(t, loginScheduleStore)
+		},
+	}
+	cmd.AddCommand(newCmdAdd(t, loginScheduleStore, noLoginScheduleStore))
+	cmd.AddCommand(newCmdLs(t, loginScheduleStore))
+	cmd.AddCommand(newCmdHistory(t, loginScheduleStore))
+	cmd.AddCommand(newCmdRm(t, loginScheduleStore))
+	cmd.AddCommand(newCmdDaemon(loginScheduleStore))
+	cmd.AddCommand(newCmdInstall(t, loginScheduleStore))
+	cmd.AddCommand(newCmdUninstall(t, loginScheduleStore))
+	return cmd
+}
+
+// addStore is what brev schedule add needs
+type addStore interface {
+	auth.CurrentUserAuthStore
+	GetActiveOrganizationOrDefault() (*entity.Organization, error)
+	GetSchedules() (*files.Schedules, error)
+	SaveSchedules(schedules *files.Schedules) error
+}
+
+// addOptions are the flags of brev schedule add
+type addOptions struct {
+	selector ls.SelectorFlags
+	stop     string
+	start    string
+	timezone string
+	catchUp  string
+}
+
+func newCmdAdd(t *terminal.Terminal, s ScheduleStore, noLoginStore completions.CompletionStore) *cobra.Command {
+	var opts addOptions
+	cmd := &cobra.Command{
+		Use:               "add [instance...]",
+		Short:             "Add a rule that starts and stops instances",
+		Example:           `  brev schedule add my-instance --stop "0 20 * * 1-5" --start "0 8 * * 1-5"`,
+		ValidArgsFunction: completions.GetAllWorkspaceNameCompletionHandler(noLoginStore, t),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, _ := cmdutil.GetInstanceNamesWithPipeInfo(args)
+			schedule, err := addSchedule(s, names, opts, time.Now())
+			if err != nil {
+				return err
+			}
+			t.Vprint(t.Green("Added schedule %s for %s\n", schedule.ID, describeTarget(*schedule)))
+			printNextRuns(t, *schedule, time.Now())
+			t.Vprintf("The rules run while the schedule daemon is up: %s or %s\n",
+				t.Yellow("sudo brev schedule install"), t.Yellow("brev schedule daemon --background"))
+			return nil
+		},
+	}
+	ls.AddInstanceSelectorFlags(cmd, &opts.selector)
+	cmd.Flags().StringVar(&opts.stop, "stop", "", `Cron rule for stopping the instances, e.g. "0 20 * * 1-5"`)
+	cmd.Flags().StringVar(&opts.start, "start", "", `Cron rule for starting the instances, e.g. "0 8 * * 1-5"`)
+	cmd.Flags().StringVar(&opts.timezone, "timezone", "", "Timezone of the rules, e.g. Europe/Berlin (default the daemon's local time)")
+	cmd.Flags().StringVar(&opts.catchUp, "catch-up", files.CatchUpLast, "What to do with runs missed while the daemon was down: last or skip")
+	err := cmd.RegisterFlagCompletionFunc("catch-up", cobra.FixedCompletions([]string{files.CatchUpLast, files.CatchUpSkip}, cobra.ShellCompDirectiveNoFileComp))
+	if err != nil {
+		breverrors.GetDefaultErrorReporter().ReportError(breverrors.WrapAndTrace(err))
+	}
+	return cmd
+}
+
+// addSchedule validates the rules and saves a new schedule for the active org
+func addSchedule(s addStore, names []string, opts addOptions, now time.Time) (*files.Schedule, error) {
+	if opts.stop == "" && opts.start == "" {
+		return nil, breverrors.NewValidationError("set --stop, --start or both")
+	}
+	if len(names) == 0 && opts.selector.IsZero() {
+		return nil, breverrors.NewValidationError("name instances or select them, e.g. with --name-regex or --label")
+	}
+	if opts.catchUp != files.CatchUpLast && opts.catchUp != files.CatchUpSkip {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --catch-up %q: use %s or %s", opts.catchUp, files.CatchUpLast, files.CatchUpSkip))
+	}
+	for _, expr := range []string{opts.stop, opts.start} {
+		if expr == "" {
+			continue
+		}
+		if _, err := ParseRule(expr, opts.timezone); err != nil {
+			return nil, err
+		}
+	}
+	// catch invalid selectors now rather than in the daemon's log
+	cliAuth, err := auth.ResolveCLIAuth(s)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if _, err := opts.selector.Select(cliAuth, s, nil, nil); err != nil {
+		return nil, err
+	}
+	org, err := s.GetActiveOrganizationOrDefault()
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if org == nil {
+		return nil, breverrors.NewValidationError("no orgs exist")
+	}
+
+	schedules, err := s.GetSchedules()
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	schedule := files.Schedule{
+		ID:        nextID(schedules.Schedules),
+		OrgID:     org.ID,
+		Instances: names,
+		Selector: files.ScheduleSelector{
+			Statuses:  opts.selector.Statuses,
+			CreatedBy: opts.selector.CreatedBy,
+			NameRegex: opts.selector.NameRegex,
+			OlderThan: opts.selector.OlderThan,
+			Labels:    opts.selector.Labels,
+		},
+		Stop:      opts.stop,
+		Start:     opts.start,
+		Timezone:  opts.timezone,
+		CatchUp:   opts.catchUp,
+		CreatedAt: now,
+	}
+	schedules.Schedules = append(schedules.Schedules, schedule)
+	if err := s.SaveSchedules(schedules); err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	return &schedule, nil
+}
+
+// nextID returns one more than the highest numeric ID
+func nextID(schedules []files.Schedule) string {
+	highest := 0
+	for _, s := range schedules {
+		if id, err := strconv.Atoi(s.ID); err == nil && id > highest {
+			highest = id
+		}
+	}
+	return strconv.Itoa(highest + 1)
+}
+
+func newCmdLs(t *terminal.Terminal, s ScheduleStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List schedules",
+		Args:  cmderrors.TransformToValidationError(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listSchedules(t, s)
+		},
+	}
+}
+
+func listSchedules(t *terminal.Terminal, s ScheduleStore) error {
+	schedules, err := s.GetSchedules()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	if len(schedules.Schedules) == 0 {
+		t.Vprintf("No schedules. Add one with %s\n", t.Yellow(`brev schedule add <instance> --stop "0 20 * * 1-5"`))
+		return nil
+	}
+	renderSchedules(os.Stdout, schedules.Schedules, time.Now())
+	return nil
+}
+
+func renderSchedules(out io.Writer, schedules []files.Schedule, now time.Time) {
+	ta := newTable(out)
+	ta.AppendHeader(table.Row{"ID", "Target", "Stop", "Start", "Catch-Up", "Next", "Last Run"})
+	for _, s := range schedules {
+		next := ""
+		if action, at := nextRun(s, now); action != "" {
+			next = fmt.Sprintf("%s %s", action, formatTime(at))
+		}
+		last := ""
+		if run := s.LastRun(); run != nil {
+			last = fmt.Sprintf("%s %s: %s", run.Action, formatTime(run.RanAt), describeRun(*run))
+		}
+		ta.AppendRow(table.Row{s.ID, describeTarget(s), dashIfEmpty(withTimezone(s.Stop, s.Timezone)),
+			dashIfEmpty(withTimezone(s.Start, s.Timezone)), catchUpPolicy(s), next, last})
+	}
+	ta.Render()
+}
+
+func newCmdHistory(t *terminal.Terminal, s ScheduleStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "history [id...]",
+		Short: "Show the starts and stops schedules ran",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schedules, err := s.GetSchedules()
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			selected, err := findSchedules(schedules.Schedules, args)
+			if err != nil {
+				return err
+			}
+			var runs []historyRow
+			for _, schedule := range selected {
+				for _, run := range schedule.History {
+					runs = append(runs, historyRow{id: schedule.ID, run: run})
+				}
+			}
+			if len(runs) == 0 {
+				t.Vprint("No runs yet\n")
+				return nil
+			}
+			renderHistory(os.Stdout, runs)
+			return nil
+		},
+	}
+}
+
+type historyRow struct {
+	id  string
+	run files.ScheduleRun
+}
+
+func renderHistory(out io.Writer, rows []historyRow) {
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].run.RanAt.Before(rows[j].run.RanAt) })
+	ta := newTable(out)
+	ta.AppendHeader(table.Row{"Schedule", "Action", "Due", "Ran", "Result"})
+	for _, r := range rows {
+		ta.AppendRow(table.Row{r.id, r.run.Action, formatTime(r.run.Due), formatTime(r.run.RanAt), describeRun(r.run)})
+	}
+	ta.Render()
+}
+
+func newCmdRm(t *terminal.Terminal, s ScheduleStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <id...>",
+		Short: "Remove schedules",
+		Args:  cmderrors.TransformToValidationError(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schedules, err := s.GetSchedules()
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			if _, err := findSchedules(schedules.Schedules, args); err != nil {
+				return err
+			}
+			remove := map[string]bool{}
+			for _, id := range args {
+				remove[id] = true
+			}
+			var kept []files.Schedule
+			for _, schedule := range schedules.Schedules {
+				if !remove[schedule.ID] {
+					kept = append(kept, schedule)
+				}
+			}
+			schedules.Schedules = kept
+			if err := s.SaveSchedules(schedules); err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			t.Vprintf("Removed schedule %s\n", strings.Join(args, ", "))
+			return nil
+		},
+	}
+}
+
+// findSchedules returns the schedules with the IDs, or all of them for no IDs
+func findSchedules(schedules []files.Schedule, ids []string) ([]files.Schedule, error) {
+	if len(ids) == 0 {
+		return schedules, nil
+	}
+	byID := map[string]files.Schedule{}
+	for _, s := range schedules {
+		byID[s.ID] = s
+	}
+	var found []files.Schedule
+	for _, id := range ids {
+		s, ok := byID[id]
+		if !ok {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("no schedule with ID %s; see brev schedule ls", id))
+		}
+		found = append(found, s)
+	}
+	return found, nil
+}
+
+func newCmdDaemon(s ScheduleStore) *cobra.Command {
+	var background bool
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run the schedules until stopped",
+		Long: `Evaluate the schedules every minute, starting and stopping their instances.
+This is what 'brev schedule install' runs as a systemd service.`,
+		Args: cmderrors.TransformToValidationError(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ts := []tasks.Task{&SchedulerTask{Store: s}}
+			if background {
+				err := tasks.RunTaskAsDaemon(ts, s)
+				if err != nil {
+					return breverrors.WrapAndTrace(err)
+				}
+				return nil
+			}
+			err := tasks.RunTasks(ts)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&background, "background", false, "Run detached, logging to ~/.brev/task_daemon.log")
+	return cmd
+}
+
+func newCmdInstall(t *terminal.Terminal, s ScheduleStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "install",
+		Short: "Install the schedule daemon as a systemd service (needs sudo)",
+		Args:  cmderrors.TransformToValidationError(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task := &SchedulerTask{Store: s, AutoStartStore: s, User: daemonUser(s)}
+			if err := task.Configure(); err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			t.Vprint(t.Green("Installed brevschedule.service\n"))
+			return nil
+		},
+	}
+}
+
+func newCmdUninstall(t *terminal.Terminal, s ScheduleStore) *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the schedule daemon's systemd service (needs sudo)",
+		Args:  cmderrors.TransformToValidationError(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configurer := autostartconf.NewScheduleConfigurer(s, daemonUser(s))
+			if configurer == nil {
+				return breverrors.NewValidationError("the schedule daemon is only installed as a service where systemd is available")
+			}
+			if err := configurer.UnInstall(); err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			t.Vprint("Removed brevschedule.service\n")
+			return nil
+		},
+	}
+}
+
+// daemonUser is the user the service runs as: the one who ran sudo, so the
+// daemon reads their ~/.brev, or else the current user
+func daemonUser(s autostartconf.AutoStartStore) string {
+	if uid := os.Getenv("SUDO_UID"); uid != "" {
+		return uid
+	}
+	return s.GetOSUser()
+}
+
+// nextRun returns the schedule's next start or stop
+func nextRun(s files.Schedule, now time.Time) (string, time.Time) {
+	var action string
+	var at time.Time
+	for _, rule := range []struct{ action, expr string }{{actionStop, s.Stop}, {actionStart, s.Start}} {
+		if rule.expr == "" {
+			continue
+		}
+		sched, err := ParseRule(rule.expr, s.Timezone)
+		if err != nil {
+			continue
+		}
+		if next := sched.Next(now); !next.IsZero() && (at.IsZero() || next.Before(at)) {
+			action, at = rule.action, next
+		}
+	}
+	return action, at
+}
+
+func printNextRuns(t *terminal.Terminal, s files.Schedule, now time.Time) {
+	for _, rule := range []struct{ action, expr string }{{actionStop, s.Stop}, {actionStart, s.Start}} {
+		if rule.expr == "" {
+			continue
+		}
+		sched, err := ParseRule(rule.expr, s.Timezone)
+		if err != nil {
+			continue
+		}
+		t.Vprintf("  next %s: %s\n", rule.action, formatTime(sched.Next(now)))
+	}
+}
+
+func describeTarget(s files.Schedule) string {
+	var parts []string
+	if len(s.Instances) > 0 {
+		parts = append(parts, strings.Join(s.Instances, ", "))
+	}
+	sel := s.Selector
+	if len(sel.Statuses) > 0 {
+		parts = append(parts, "status="+strings.Join(sel.Statuses, ","))
+	}
+	if sel.CreatedBy != "" {
+		parts = append(parts, "created-by="+sel.CreatedBy)
+	}
+	if sel.NameRegex != "" {
+		parts = append(parts, "name-regex="+sel.NameRegex)
+	}
+	if sel.OlderThan != "" {
+		parts = append(parts, "older-than="+sel.OlderThan)
+	}
+	if len(sel.Labels) > 0 {
+		parts = append(parts, "label="+strings.Join(sel.Labels, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+func withTimezone(expr, timezone string) string {
+	if expr == "" || timezone == "" {
+		return expr
+	}
+	return expr + " " + timezone
+}
+
+func catchUpPolicy(s files.Schedule) string {
+	if s.CatchUp == "" {
+		return files.CatchUpLast
+	}
+	return s.CatchUp
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("Mon Jan 2 15:04")
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func newTable(out io.Writer) table.Writer {
+	ta := table.NewWriter()
+	ta.SetOutputMirror(out)
+	ta.Style().Options = cmdutil.BrevTableOptions()
+	return ta
+}
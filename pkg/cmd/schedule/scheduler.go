@@ -0,0 +1,338 @@
+package schedule
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/autostartconf"
+	"github.com/brevdev/brev-cli/pkg/cmd/budget"
+	"github.com/brevdev/brev-cli/pkg/cmd/ls"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/tasks"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	actionStop  = "stop"
+	actionStart = "start"
+)
+
+// missedAfter is how late a run may be before it counts as missed, e.g.
+// because the daemon was not running or the machine was asleep
+const missedAfter = 5 * time.Minute
+
+// SchedulerStore is what the daemon needs to run the rules
+type SchedulerStore interface {
+	auth.CurrentUserAuthStore
+	GetWorkspaces(organizationID string, options *store.GetWorkspacesOptions) ([]entity.Workspace, error)
+	StartWorkspace(workspaceID string) (*entity.Workspace, error)
+	StopWorkspace(workspaceID string) (*entity.Workspace, error)
+	GetSchedules() (*files.Schedules, error)
+	SaveSchedules(schedules *files.Schedules) error
+}
+
+// SchedulerTask evaluates the schedules every minute and starts or stops
+// their instances
+type SchedulerTask struct {
+	Store SchedulerStore
+	// AutoStartStore and User install the daemon with Configure
+	AutoStartStore autostartconf.AutoStartStore
+	User           string
+
+	now func() time.Time // replaced in tests
+	mu  sync.Mutex       // runs do not overlap when the API is slow
+}
+
+var _ tasks.Task = &SchedulerTask{}
+
+func (st *SchedulerTask) GetTaskSpec() tasks.TaskSpec {
+	return tasks.TaskSpec{RunCronImmediately: true, Cron: "@every 1m"}
+}
+
+// Configure installs the daemon as a systemd service
+func (st *SchedulerTask) Configure() error {
+	configurer := autostartconf.NewScheduleConfigurer(st.AutoStartStore, st.User)
+	if configurer == nil {
+		return breverrors.NewValidationError("installing the schedule daemon needs systemd; run 'brev schedule daemon --background' instead")
+	}
+	err := configurer.Install()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	return nil
+}
+
+// Run evaluates every schedule once and records what it did
+func (st *SchedulerTask) Run() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	schedules, err := st.Store.GetSchedules()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	if len(schedules.Schedules) == 0 {
+		return nil
+	}
+	now := time.Now()
+	if st.now != nil {
+		now = st.now()
+	}
+	for i := range schedules.Schedules {
+		st.runSchedule(&schedules.Schedules[i], now)
+	}
+	return st.save(schedules.Schedules)
+}
+
+// save writes back the history of the evaluated schedules. The file is read
+// again first so schedules added or removed meanwhile are kept as they are.
+func (st *SchedulerTask) save(evaluated []files.Schedule) error {
+	current, err := st.Store.GetSchedules()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	byID := map[string]files.Schedule{}
+	for _, s := range evaluated {
+		byID[s.ID] = s
+	}
+	for i, s := range current.Schedules {
+		if e, ok := byID[s.ID]; ok {
+			current.Schedules[i].CheckedAt = e.CheckedAt
+			current.Schedules[i].History = e.History
+		}
+	}
+	err = st.Store.SaveSchedules(current)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	return nil
+}
+
+func (st *SchedulerTask) runSchedule(s *files.Schedule, now time.Time) {
+	d, err := due(*s, now)
+	if err != nil {
+		log.Printf("schedule %s: %v", s.ID, err)
+		return
+	}
+	s.CheckedAt = now
+	if d == nil {
+		return
+	}
+
+	run := files.ScheduleRun{Action: d.action, Due: d.at, RanAt: now, CatchUp: d.missed}
+	if d.missed && s.CatchUp == files.CatchUpSkip {
+		run.Skipped = true
+		log.Printf("schedule %s: skipped the %s due %s", s.ID, d.action, d.at.Format(time.RFC3339))
+		s.AddRun(run)
+		return
+	}
+	run.Succeeded, err = st.act(*s, d.action)
+	if err != nil {
+		run.Error = err.Error()
+	}
+	log.Printf("schedule %s: %s due %s: %s", s.ID, d.action, d.at.Format(time.RFC3339), describeRun(run))
+	s.AddRun(run)
+}
+
+// act starts or stops the schedule's instances, returning the ones it acted on
+func (st *SchedulerTask) act(s files.Schedule, action string) ([]string, error) {
+	workspaces, missing, err := st.targets(s)
+	if err != nil {
+		return nil, err
+	}
+	// named instances that no longer exist are reported after acting on the rest
+	var notFound error
+	if len(missing) > 0 {
+		notFound = fmt.Errorf("not found: %s", strings.Join(missing, ", "))
+	}
+	from, do := entity.Running, st.Store.StopWorkspace
+	if action == actionStart {
+		from, do = entity.Stopped, st.Store.StartWorkspace
+	}
+	var pending []entity.Workspace
+	for _, w := range workspaces {
+		if w.Status == from {
+			pending = append(pending, w)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, notFound
+	}
+
+	if action == actionStart {
+		planned := make([]budget.Planned, 0, len(pending))
+		for _, w := range pending {
+			planned = append(planned, budget.Planned{InstanceTypes: []string{w.InstanceType}, Count: 1})
+		}
+		// no Confirmer: over budget refuses, as nobody can answer
+		_, err = budget.Check(st.Store, s.OrgID, planned, budget.Options{Logf: log.Printf})
+		if err != nil {
+			return nil, breverrors.WrapAndTrace(err)
+		}
+	}
+
+	var done, failed []string
+	for _, w := range pending {
+		if _, err := do(w.ID); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", w.Name, err))
+			continue
+		}
+		done = append(done, w.Name)
+	}
+	if len(failed) > 0 {
+		failedErr := fmt.Errorf("failed to %s %s", action, strings.Join(failed, "; "))
+		if notFound != nil {
+			return done, fmt.Errorf("%v; %w", notFound, failedErr)
+		}
+		return done, failedErr
+	}
+	return done, notFound
+}
+
+// targets returns the instances the schedule names or selects: the user's own,
+// or every instance in the org with API key auth or a created-by selector. It
+// also returns the named instances that do not exist.
+func (st *SchedulerTask) targets(s files.Schedule) ([]entity.Workspace, []string, error) {
+	cliAuth, err := auth.ResolveCLIAuth(st.Store)
+	if err != nil {
+		return nil, nil, breverrors.WrapAndTrace(err)
+	}
+	workspaces, err := st.Store.GetWorkspaces(s.OrgID, nil)
+	if err != nil {
+		return nil, nil, breverrors.WrapAndTrace(err)
+	}
+	if !cliAuth.IsAPIKey() && s.Selector.CreatedBy == "" {
+		if cliAuth.User() == nil {
+			return nil, nil, breverrors.NewValidationError("user is required")
+		}
+		workspaces = store.FilterForUserWorkspaces(workspaces, cliAuth.User().ID)
+	}
+
+	var missing []string
+	if len(s.Instances) > 0 {
+		var named []entity.Workspace
+		for _, name := range s.Instances {
+			found := false
+			for _, w := range workspaces {
+				if w.Name == name || w.ID == name {
+					named = append(named, w)
+					found = true
+				}
+			}
+			if !found {
+				missing = append(missing, name)
+			}
+		}
+		workspaces = named
+	}
+	workspaces, err = selectorFlags(s.Selector).Select(cliAuth, st.Store, workspaces, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return workspaces, missing, nil
+}
+
+func selectorFlags(s files.ScheduleSelector) ls.SelectorFlags {
+	return ls.SelectorFlags{
+		Statuses:  s.Statuses,
+		CreatedBy: s.CreatedBy,
+		NameRegex: s.NameRegex,
+		OlderThan: s.OlderThan,
+		Labels:    s.Labels,
+	}
+}
+
+// dueRun is the action a schedule should run now
+type dueRun struct {
+	action string
+	at     time.Time
+	missed bool // due more than missedAfter ago
+}
+
+// due returns the latest start or stop due since the schedule was last
+// checked, or nil. Only the latest runs: after a stop and a start were both
+// missed, the instances should be in the state of whichever came last.
+func due(s files.Schedule, now time.Time) (*dueRun, error) {
+	since := s.CheckedAt
+	if since.IsZero() {
+		since = s.CreatedAt
+	}
+	var latest *dueRun
+	for _, rule := range []struct{ action, expr string }{{actionStop, s.Stop}, {actionStart, s.Start}} {
+		if rule.expr == "" {
+			continue
+		}
+		sched, err := ParseRule(rule.expr, s.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		at := lastBetween(sched, since, now)
+		if !at.IsZero() && (latest == nil || at.After(latest.at)) {
+			latest = &dueRun{action: rule.action, at: at}
+		}
+	}
+	if latest != nil {
+		latest.missed = now.Sub(latest.at) > missedAfter
+	}
+	return latest, nil
+}
+
+// lastBetween returns the last time sched fires after since and at or before
+// now, or the zero time
+func lastBetween(sched cron.Schedule, since, now time.Time) time.Time {
+	var last time.Time
+	for next := sched.Next(since); !next.IsZero() && !next.After(now); next = sched.Next(next) {
+		last = next
+	}
+	return last
+}
+
+// ParseRule parses a standard five field cron expression, in timezone when set
+func ParseRule(expr, timezone string) (cron.Schedule, error) {
+	spec := expr
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("invalid timezone %q: %v", timezone, err))
+		}
+		spec = "CRON_TZ=" + timezone + " " + expr
+	}
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("invalid cron rule %q: %v", expr, err))
+	}
+	return sched, nil
+}
+
+// describeRun summarizes a run for the log and brev schedule history
+func describeRun(run files.ScheduleRun) string {
+	var parts []string
+	switch {
+	case run.Skipped:
+		parts = append(parts, "skipped (missed)")
+	case len(run.Succeeded) > 0:
+		names := append([]string(nil), run.Succeeded...)
+		sort.Strings(names)
+		verb := "stopped"
+		if run.Action == actionStart {
+			verb = "started"
+		}
+		parts = append(parts, verb+" "+strings.Join(names, ", "))
+	case run.Error == "":
+		parts = append(parts, "nothing to "+run.Action)
+	}
+	if run.CatchUp && !run.Skipped {
+		parts = append(parts, "caught up")
+	}
+	if run.Error != "" {
+		parts = append(parts, run.Error)
+	}
+	return strings.Join(parts, "; ")
+}
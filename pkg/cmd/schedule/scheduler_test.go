@@ -0,0 +1,245 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/ls"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockScheduleStore struct {
+	workspaces []entity.Workspace
+	schedules  files.Schedules
+	started    []string
+	stopped    []string
+	failIDs    map[string]bool
+	// afterRead runs after the schedules are read, e.g. to add one meanwhile
+	afterRead func()
+}
+
+func (m *mockScheduleStore) GetAuthTokens() (*entity.AuthTokens, error) {
+	return &entity.AuthTokens{}, nil
+}
+
+func (m *mockScheduleStore) GetCurrentUser() (*entity.User, error) {
+	return &entity.User{ID: "u1"}, nil
+}
+
+func (m *mockScheduleStore) GetActiveOrganizationOrDefault() (*entity.Organization, error) {
+	return &entity.Organization{ID: "org1"}, nil
+}
+
+func (m *mockScheduleStore) GetWorkspaces(_ string, _ *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	return m.workspaces, nil
+}
+
+func (m *mockScheduleStore) StartWorkspace(workspaceID string) (*entity.Workspace, error) {
+	if m.failIDs[workspaceID] {
+		return nil, errors.New("api error")
+	}
+	m.started = append(m.started, workspaceID)
+	return &entity.Workspace{ID: workspaceID}, nil
+}
+
+func (m *mockScheduleStore) StopWorkspace(workspaceID string) (*entity.Workspace, error) {
+	if m.failIDs[workspaceID] {
+		return nil, errors.New("api error")
+	}
+	m.stopped = append(m.stopped, workspaceID)
+	return &entity.Workspace{ID: workspaceID}, nil
+}
+
+func (m *mockScheduleStore) GetSchedules() (*files.Schedules, error) {
+	// a copy, as the file would be
+	copied := files.Schedules{Schedules: append([]files.Schedule(nil), m.schedules.Schedules...)}
+	if m.afterRead != nil {
+		m.afterRead()
+	}
+	return &copied, nil
+}
+
+func (m *mockScheduleStore) SaveSchedules(schedules *files.Schedules) error {
+	m.schedules = *schedules
+	return nil
+}
+
+func newScheduleStore(schedules ...files.Schedule) *mockScheduleStore {
+	return &mockScheduleStore{
+		workspaces: []entity.Workspace{
+			{ID: "ws1", Name: "train-a", Status: entity.Running, CreatedByUserID: "u1"},
+			{ID: "ws2", Name: "train-b", Status: entity.Stopped, CreatedByUserID: "u1"},
+			{ID: "ws3", Name: "notebook", Status: entity.Running, CreatedByUserID: "u1"},
+			{ID: "ws4", Name: "train-c", Status: entity.Running, CreatedByUserID: "u2"},
+		},
+		schedules: files.Schedules{Schedules: schedules},
+	}
+}
+
+// weekdays stops at 20:00 and starts at 08:00 on weekdays, in UTC
+func weekdays(checkedAt time.Time) files.Schedule {
+	return files.Schedule{
+		ID:        "1",
+		OrgID:     "org1",
+		Selector:  files.ScheduleSelector{NameRegex: "^train-"},
+		Stop:      "0 20 * * 1-5",
+		Start:     "0 8 * * 1-5",
+		Timezone:  "UTC",
+		CreatedAt: checkedAt.Add(-time.Hour),
+		CheckedAt: checkedAt,
+	}
+}
+
+// Monday, March 2 2026
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+}
+
+func runAt(t *testing.T, s *mockScheduleStore, now time.Time) {
+	t.Helper()
+	task := &SchedulerTask{Store: s, now: func() time.Time { return now }}
+	require.NoError(t, task.Run())
+}
+
+func TestDue(t *testing.T) {
+	s := weekdays(at(2, 19, 59))
+
+	d, err := due(s, at(2, 19, 59))
+	require.NoError(t, err)
+	assert.Nil(t, d)
+
+	d, err = due(s, at(2, 20, 1))
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	assert.Equal(t, actionStop, d.action)
+	assert.Equal(t, at(2, 20, 0), d.at)
+	assert.False(t, d.missed)
+
+	// down from Monday evening to Thursday noon: the last run, Thursday's
+	// start, is due and was missed
+	d, err = due(s, at(5, 12, 0))
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	assert.Equal(t, actionStart, d.action)
+	assert.Equal(t, at(5, 8, 0), d.at)
+	assert.True(t, d.missed)
+
+	// rules are evaluated in their timezone
+	s.Timezone = "America/New_York"
+	d, err = due(s, at(3, 1, 1))
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	assert.Equal(t, actionStop, d.action)
+	assert.True(t, d.at.Equal(at(3, 1, 0)))
+}
+
+func TestRun_StopsSelectedRunningInstances(t *testing.T) {
+	s := newScheduleStore(weekdays(at(2, 19, 59)))
+	runAt(t, s, at(2, 20, 0))
+
+	// train-b is already stopped and train-c belongs to someone else
+	assert.Equal(t, []string{"ws1"}, s.stopped)
+	schedule := s.schedules.Schedules[0]
+	assert.Equal(t, at(2, 20, 0), schedule.CheckedAt)
+	require.Len(t, schedule.History, 1)
+	assert.Equal(t, files.ScheduleRun{Action: actionStop, Due: at(2, 20, 0), RanAt: at(2, 20, 0), Succeeded: []string{"train-a"}}, schedule.History[0])
+
+	// a minute later nothing is due
+	runAt(t, s, at(2, 20, 1))
+	assert.Len(t, s.stopped, 1)
+	assert.Len(t, s.schedules.Schedules[0].History, 1)
+}
+
+func TestRun_CatchUpLast(t *testing.T) {
+	s := newScheduleStore(weekdays(at(2, 19, 0)))
+	// back on Tuesday at 09:00, after a missed stop and start
+	runAt(t, s, at(3, 9, 0))
+
+	assert.Empty(t, s.stopped)
+	assert.Equal(t, []string{"ws2"}, s.started)
+	run := s.schedules.Schedules[0].LastRun()
+	require.NotNil(t, run)
+	assert.True(t, run.CatchUp)
+	assert.False(t, run.Skipped)
+	assert.Equal(t, at(3, 8, 0), run.Due)
+}
+
+func TestRun_CatchUpSkip(t *testing.T) {
+	schedule := weekdays(at(2, 19, 0))
+	schedule.CatchUp = files.CatchUpSkip
+	s := newScheduleStore(schedule)
+	runAt(t, s, at(3, 9, 0))
+
+	assert.Empty(t, s.stopped)
+	assert.Empty(t, s.started)
+	run := s.schedules.Schedules[0].LastRun()
+	require.NotNil(t, run)
+	assert.True(t, run.Skipped)
+	assert.Equal(t, actionStart, run.Action)
+	assert.Equal(t, at(3, 9, 0), s.schedules.Schedules[0].CheckedAt)
+}
+
+func TestRun_NamedInstancesAndFailures(t *testing.T) {
+	schedule := weekdays(at(2, 19, 59))
+	schedule.Selector = files.ScheduleSelector{}
+	schedule.Instances = []string{"train-a", "ws3", "gone"}
+	s := newScheduleStore(schedule)
+	s.failIDs = map[string]bool{"ws3": true}
+	runAt(t, s, at(2, 20, 0))
+
+	assert.Equal(t, []string{"ws1"}, s.stopped)
+	run := s.schedules.Schedules[0].LastRun()
+	require.NotNil(t, run)
+	assert.Equal(t, []string{"train-a"}, run.Succeeded)
+	assert.Contains(t, run.Error, "not found: gone")
+	assert.Contains(t, run.Error, "notebook: api error")
+}
+
+func TestRun_KeepsSchedulesChangedMeanwhile(t *testing.T) {
+	s := newScheduleStore(weekdays(at(2, 19, 59)))
+	s.afterRead = func() {
+		s.afterRead = nil
+		s.schedules.Schedules = append(s.schedules.Schedules, files.Schedule{ID: "2", Stop: "0 0 * * *"})
+	}
+	runAt(t, s, at(2, 20, 0))
+
+	require.Len(t, s.schedules.Schedules, 2)
+	assert.Len(t, s.schedules.Schedules[0].History, 1)
+	assert.Equal(t, "2", s.schedules.Schedules[1].ID)
+}
+
+func TestAddSchedule(t *testing.T) {
+	s := newScheduleStore(files.Schedule{ID: "4"})
+	opts := addOptions{stop: "0 20 * * 1-5", start: "0 8 * * 1-5", timezone: "UTC", catchUp: files.CatchUpLast}
+	schedule, err := addSchedule(s, []string{"train-a"}, opts, at(2, 12, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "5", schedule.ID)
+	assert.Equal(t, "org1", schedule.OrgID)
+	require.Len(t, s.schedules.Schedules, 2)
+
+	action, next := nextRun(*schedule, at(2, 12, 0))
+	assert.Equal(t, actionStop, action)
+	assert.Equal(t, at(2, 20, 0), next)
+
+	bad := []struct {
+		names []string
+		opts  addOptions
+	}{
+		{[]string{"a"}, addOptions{catchUp: files.CatchUpLast}},
+		{nil, addOptions{stop: "0 20 * * *", catchUp: files.CatchUpLast}},
+		{[]string{"a"}, addOptions{stop: "0 20 * *", catchUp: files.CatchUpLast}},
+		{[]string{"a"}, addOptions{stop: "0 20 * * *", timezone: "Mars/Base", catchUp: files.CatchUpLast}},
+		{[]string{"a"}, addOptions{stop: "0 20 * * *", catchUp: "all"}},
+		{nil, addOptions{stop: "0 20 * * *", catchUp: files.CatchUpLast, selector: ls.SelectorFlags{NameRegex: "("}}},
+	}
+	for _, b := range bad {
+		_, err := addSchedule(s, b.names, b.opts, at(2, 12, 0))
+		assert.Error(t, err, b.opts)
+	}
+	assert.Len(t, s.schedules.Schedules, 2)
+}
@@ -2,15 +2,17 @@
 package start
 
 import (
+	"context"
 	"fmt"
 	"net/url"
-	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/budget"
+	"github.com/brevdev/brev-cli/pkg/cmd/bulk"
 	"github.com/brevdev/brev-cli/pkg/cmd/completions"
+	"github.com/brevdev/brev-cli/pkg/cmd/readiness"
 	cmdutil "github.com/brevdev/brev-cli/pkg/cmd/util"
 	"github.com/brevdev/brev-cli/pkg/config"
 	"github.com/brevdev/brev-cli/pkg/entity"
@@ -21,7 +23,6 @@ import (
 	"github.com/brevdev/brev-cli/pkg/store"
 	"github.com/brevdev/brev-cli/pkg/terminal"
 	"github.com/brevdev/brev-cli/pkg/util"
-	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 )
 
@@ -32,6 +33,9 @@ var (
   brev start <git url>
   brev start <git url> --org myFancyOrg
   echo instance-name | brev start
+  brev start --status STOPPED --label team=ml --parallel 8
+  brev start <existing_ws_name> --ready-when 'ssh:systemctl is-active my-service'
+  brev start <existing_ws_name> --allow-over-budget
 	`
 )
 
@@ -59,6 +63,9 @@ func NewCmdStart(t *terminal.Terminal, startStore StartStore, noLoginStartStore
 	var setupPath string
 	var gpu string
 	var cpu string
+	var readyWhen []string
+	var allowOverBudget bool
+	var flags bulk.Flags
 
 	cmd := &cobra.Command{
 		Annotations:           map[string]string{"provider-dependent": ""},
@@ -80,13 +87,23 @@ func NewCmdStart(t *terminal.Terminal, startStore StartStore, noLoginStartStore
 				}
 			}
 
-			// If stdin is piped, handle multiple instances (only start existing stopped instances)
-			if stdinPiped && len(names) > 0 {
-				return runBatchStart(t, names, org, setupScript, setupRepo, setupPath, cpu, gpu, piped, startStore)
+			probes, err := readiness.ParseProbes(readyWhen)
+			if err != nil {
+				return err
+			}
+			opts := bulk.Options{Names: names, Confirmer: bulk.Prompter(stdinPiped), Piped: piped}
+			useBulk := (stdinPiped && len(names) > 0) || bulk.UseBulk(flags, opts)
+			if len(probes) > 0 && (detached || useBulk) {
+				return breverrors.NewValidationError("--ready-when cannot be used with --detached or when starting several instances")
+			}
+
+			// Several instances, from stdin or selectors: only start existing stopped instances
+			if useBulk {
+				return bulk.Run(t, startStore, startOperation(t, startStore, allowOverBudget, opts.Confirmer), flags, opts)
 			}
 
 			// Single instance mode (original behavior)
-			return runSingleStart(t, names, name, org, setupScript, setupRepo, setupPath, cpu, gpu, detached, piped, startStore)
+			return runSingleStart(t, names, name, org, setupScript, setupRepo, setupPath, cpu, gpu, detached, piped, allowOverBudget, probes, startStore)
 		},
 	}
 	cmd.Flags().BoolVarP(&detached, "detached", "d", false, "run the command in the background instead of blocking the shell")
@@ -99,6 +116,9 @@ func NewCmdStart(t *terminal.Terminal, startStore StartStore, noLoginStartStore
 	cmd.Flags().StringVarP(&setupPath, "setup-path", "p", "", "path to env setup script. If you include --setup-repo we will apply this argument to that repo")
 	cmd.Flags().StringVarP(&org, "org", "o", "", "organization (will override active org if creating a workspace)")
 	// GPU options
+	readiness.AddFlags(cmd, &readyWhen)
+	budget.AddFlags(cmd, &allowOverBudget)
+	bulk.AddFlags(cmd, &flags)
 	cmd.Flags().StringVarP(&gpu, "gpu", "g", "n1-highmem-4:nvidia-tesla-t4:1", "GPU instance type. Refer to https://docs.nvidia.com/brev/latest/quick-start.html#select-your-compute for more information")
 	err := cmd.RegisterFlagCompletionFunc("org", completions.GetOrgsNameCompletionHandler(noLoginStartStore, t))
 	if err != nil {
@@ -118,6 +138,9 @@ type StartOptions struct {
 	WorkspaceClass       string
 	Detached             bool
 	InstanceType         string
+	ReadyWhen            []readiness.Probe
+	AllowOverBudget      bool
+	Confirmer            terminal.Confirmer // asks before exceeding a spending cap; nil refuses
 }
 
 func runStartWorkspace(t *terminal.Terminal, options StartOptions, startStore StartStore) error {
@@ -362,6 +385,11 @@ func createEmptyWorkspace(user *entity.User, apiKeyAuth bool, t *terminal.Termin
 	}
 	t.Vprintf("\tCloud %s\n\n", t.Green(cwOptions.WorkspaceGroupID))
 
+	err = checkBudget(t, startStore, orgID, cwOptions.InstanceType, options)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
 	s := t.NewSpinner()
 	s.Suffix = " Creating your instance. Hang tight 🤙"
 	s.Start()
@@ -378,6 +406,10 @@ func createEmptyWorkspace(user *entity.User, apiKeyAuth bool, t *terminal.Termin
 		if err != nil {
 			return breverrors.WrapAndTrace(err)
 		}
+		err = waitForReadyProbes(t, startStore, w, options.ReadyWhen)
+		if err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
 
 		fmt.Print("\n")
 		t.Vprint(t.Green("Your instance is ready!\n"))
@@ -418,6 +450,11 @@ func startStopppedWorkspace(workspace *entity.Workspace, startStore StartStore,
 		t.Vprint("Existing instance found. Name flag ignored.")
 	}
 
+	err := checkBudget(t, startStore, workspace.OrganizationID, workspace.InstanceType, startOptions)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
 	startedWorkspace, err := startStore.StartWorkspace(workspace.ID)
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
@@ -434,6 +471,10 @@ func startStopppedWorkspace(workspace *entity.Workspace, startStore StartStore,
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
 	}
+	err = waitForReadyProbes(t, startStore, startedWorkspace, startOptions.ReadyWhen)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
 
 	fmt.Print("\n")
 	t.Vprint(t.Green("Your instance is ready!\n"))
@@ -469,6 +510,11 @@ func joinProjectWithNewWorkspace(t *terminal.Terminal, templateWorkspace entity.
 	}
 	t.Vprintf("\tCloud %s\n", cwOptions.WorkspaceGroupID)
 
+	err := checkBudget(t, startStore, orgID, cwOptions.InstanceType, startOptions)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
 	s := t.NewSpinner()
 	s.Suffix = " Creating your instance. Hang tight 🤙"
 	s.Start()
@@ -482,6 +528,10 @@ func joinProjectWithNewWorkspace(t *terminal.Terminal, templateWorkspace entity.
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
 	}
+	err = waitForReadyProbes(t, startStore, w, startOptions.ReadyWhen)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
 
 	displayConnectBreadCrumb(t, w)
 
@@ -637,6 +687,11 @@ func createWorkspace(user *entity.User, apiKeyAuth bool, t *terminal.Terminal, w
 	}
 	t.Vprintf("\tCloud %s\n", options.WorkspaceGroupID)
 
+	err := checkBudget(t, startStore, orgID, options.InstanceType, startOptions)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
 	s := t.NewSpinner()
 	s.Suffix = " Creating your instance. Hang tight 🤙"
 	s.Start()
@@ -650,6 +705,10 @@ func createWorkspace(user *entity.User, apiKeyAuth bool, t *terminal.Terminal, w
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
 	}
+	err = waitForReadyProbes(t, startStore, w, startOptions.ReadyWhen)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
 	fmt.Print("\n")
 	t.Vprint(t.Green("Your instance is ready!\n"))
 
@@ -665,68 +724,114 @@ func displayConnectBreadCrumb(t *terminal.Terminal, workspace *entity.Workspace)
 	// t.Vprintf(t.Yellow(fmt.Sprintf("\tssh %s\t# ssh <SSH-NAME> -> ssh directly to instance\n", workspace.GetLocalIdentifier())))
 }
 
+// waitForReadyProbes runs the --ready-when probes of a running instance and prints their results
+func waitForReadyProbes(t *terminal.Terminal, startStore StartStore, workspace *entity.Workspace, probes []readiness.Probe) error {
+	if len(probes) == 0 {
+		return nil
+	}
+	if err := readiness.RefreshSSHConfig(startStore); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+
+	results := readiness.Wait(readiness.SSHChecker{}, string(workspace.GetLocalIdentifier()), probes, t.Vprintf)
+	t.Vprintf("Ready checks:\n")
+	for _, r := range results {
+		t.Vprintf("  %s %s\n", r.Mark(), r)
+	}
+	if failure := readiness.FirstFailure(results); failure != nil {
+		return breverrors.NewValidationError("ready check " + failure.String())
+	}
+	return nil
+}
+
 func pollUntil(t *terminal.Terminal, wsid string, state string, startStore StartStore, canSafelyExit bool) error { //nolint:unparam // TODO refactor
 	s := t.NewSpinner()
-	isReady := false
 	if canSafelyExit {
 		t.Vprintf("You can safely ctrl+c to exit\n")
 	}
 	s.Suffix = " hang tight 🤙"
 	s.Start()
-	for !isReady {
-		time.Sleep(5 * time.Second)
+	err := cmdutil.Poll(context.Background(), cmdutil.DefaultPollOptions, func() (bool, error) {
 		ws, err := startStore.GetWorkspace(wsid)
 		if err != nil {
-			return breverrors.WrapAndTrace(err)
+			return false, breverrors.WrapAndTrace(err)
 		}
 		s.Suffix = "  instance is " + strings.ToLower(ws.Status)
-		if ws.Status == state {
-			s.Suffix = "Instance is ready!"
-			s.Stop()
-			isReady = true
+		if ws.Status != state {
+			return false, nil
 		}
+		s.Suffix = "Instance is ready!"
+		s.Stop()
+		return true, nil
+	})
+	return breverrors.WrapAndTrace(err)
+}
+
+// checkBudget refuses, or asks before, starting or creating one instance of
+// instanceType when that would exceed a spending cap. CPU instances created
+// from a workspace class have no instance type and add nothing.
+func checkBudget(t *terminal.Terminal, startStore StartStore, orgID string, instanceType string, startOptions StartOptions) error {
+	planned := budget.Planned{Count: 1}
+	if instanceType != "" {
+		planned.InstanceTypes = []string{instanceType}
+	}
+	_, err := budget.Check(startStore, orgID, []budget.Planned{planned}, budget.Options{
+		AllowOverBudget: startOptions.AllowOverBudget,
+		Confirmer:       startOptions.Confirmer,
+		Logf:            t.Eprintf,
+	})
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
 	}
 	return nil
 }
 
-// runBatchStart handles starting multiple instances when stdin is piped.
-// Exit codes: 0 = all succeeded, 1 = all failed, 2 = partial failure.
-func runBatchStart(t *terminal.Terminal, names []string, org, setupScript, setupRepo, setupPath, cpu, gpu string, piped bool, startStore StartStore) error {
-	var startedNames []string
-	var errs error
-	for _, instanceName := range names {
-		err := runStartWorkspace(t, StartOptions{
-			RepoOrPathOrNameOrID: instanceName,
-			Name:                 "",
-			OrgName:              org,
-			SetupScript:          setupScript,
-			SetupRepo:            setupRepo,
-			SetupPath:            setupPath,
-			WorkspaceClass:       cpu,
-			Detached:             true, // Always detached when piping multiple
-			InstanceType:         gpu,
-		}, startStore)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error starting %s: %s\n", instanceName, err.Error())
-			errs = multierror.Append(errs, err)
-		} else {
-			startedNames = append(startedNames, instanceName)
-		}
-	}
-	if errs != nil {
-		return breverrors.WrapAndTrace(errs)
-	}
-	// Only output names for piping if all succeeded
-	if piped {
-		for _, n := range startedNames {
-			fmt.Println(n)
-		}
+// startOperation starts stopped instances. The budget is checked once for
+// all of them.
+func startOperation(t *terminal.Terminal, startStore StartStore, allowOverBudget bool, confirmer terminal.Confirmer) bulk.Operation {
+	return bulk.Operation{
+		Verb: "start",
+		Done: "started",
+		Resolve: func(name string) (*entity.Workspace, error) {
+			workspace, err := cmdutil.GetUserWorkspaceByNameOrIDErr(startStore, name)
+			if err != nil {
+				return nil, breverrors.WrapAndTrace(err)
+			}
+			return workspace, nil
+		},
+		Check: func(w entity.Workspace) error {
+			if w.Status != entity.Stopped {
+				return breverrors.NewValidationError(fmt.Sprintf("instance is %s, not %s", w.Status, entity.Stopped))
+			}
+			return nil
+		},
+		Prepare: func(workspaces []entity.Workspace) error {
+			planned := make([]budget.Planned, 0, len(workspaces))
+			for _, w := range workspaces {
+				planned = append(planned, budget.Planned{InstanceTypes: []string{w.InstanceType}, Count: 1})
+			}
+			_, err := budget.Check(startStore, workspaces[0].OrganizationID, planned, budget.Options{
+				AllowOverBudget: allowOverBudget,
+				Confirmer:       confirmer,
+				Logf:            t.Eprintf,
+			})
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			return nil
+		},
+		Do: func(w entity.Workspace) error {
+			_, err := startStore.StartWorkspace(w.ID)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			return nil
+		},
 	}
-	return nil
 }
 
 // runSingleStart handles starting a single instance (original behavior)
-func runSingleStart(t *terminal.Terminal, names []string, name, org, setupScript, setupRepo, setupPath, cpu, gpu string, detached, piped bool, startStore StartStore) error {
+func runSingleStart(t *terminal.Terminal, names []string, name, org, setupScript, setupRepo, setupPath, cpu, gpu string, detached, piped, allowOverBudget bool, probes []readiness.Probe, startStore StartStore) error {
 	repoOrPathOrNameOrID := ""
 	if len(names) > 0 {
 		repoOrPathOrNameOrID = names[0]
@@ -742,6 +847,9 @@ func runSingleStart(t *terminal.Terminal, names []string, name, org, setupScript
 		WorkspaceClass:       cpu,
 		Detached:             detached,
 		InstanceType:         gpu,
+		ReadyWhen:            probes,
+		AllowOverBudget:      allowOverBudget,
+		Confirmer:            terminal.Prompter{},
 	}, startStore)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate instance with name") {
@@ -3,10 +3,13 @@ package start
 import (
 	"testing"
 
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
 	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/brevdev/brev-cli/pkg/files"
 	"github.com/brevdev/brev-cli/pkg/store"
 	"github.com/brevdev/brev-cli/pkg/terminal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMakeNewWorkspaceFromURL(t *testing.T) {
@@ -75,3 +78,48 @@ func TestResolveWorkspaceUserOptions_AdminUserUsesDevDefaults(t *testing.T) {
 	assert.Equal(t, store.DevWorkspaceTemplateID, got.WorkspaceTemplateID)
 	assert.Equal(t, store.DevWorkspaceClassID, got.WorkspaceClassID)
 }
+
+// overBudgetStore has a $5/hr cap and one $4/hr instance running. Methods
+// the tests do not expect to be called panic through the nil StartStore.
+type overBudgetStore struct {
+	StartStore
+	created int
+}
+
+func (m *overBudgetStore) GetTeamBudgetPolicy() (*files.BudgetPolicy, string, error) {
+	return nil, "", nil
+}
+
+func (m *overBudgetStore) GetPersonalBudgetPolicy() (*files.BudgetPolicy, error) {
+	return &files.BudgetPolicy{MaxHourly: 5}, nil
+}
+
+func (m *overBudgetStore) GetWorkspaces(_ string, _ *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	return []entity.Workspace{{Status: entity.Running, InstanceType: "g5.xlarge"}}, nil
+}
+
+func (m *overBudgetStore) GetInstanceTypes(_ bool) (*gpusearch.InstanceTypesResponse, error) {
+	return &gpusearch.InstanceTypesResponse{Items: []gpusearch.InstanceType{
+		{Type: "g5.xlarge", BasePrice: gpusearch.BasePrice{Amount: "4.00"}},
+	}}, nil
+}
+
+func (m *overBudgetStore) CreateWorkspace(_ string, _ *store.CreateWorkspacesOptions) (*entity.Workspace, error) {
+	m.created++
+	return nil, assert.AnError
+}
+
+func TestCreateWorkspace_OverBudget(t *testing.T) {
+	s := &overBudgetStore{}
+	opts := StartOptions{InstanceType: "g5.xlarge"}
+	err := createWorkspace(nil, true, terminal.New(), MakeNewWorkspaceFromURL("https://github.com/brevdev/hello"), "org", s, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "over budget")
+	assert.Zero(t, s.created)
+
+	// --allow-over-budget goes on to create the instance
+	opts.AllowOverBudget = true
+	err = createWorkspace(nil, true, terminal.New(), MakeNewWorkspaceFromURL("https://github.com/brevdev/hello"), "org", s, opts)
+	require.Error(t, err)
+	assert.Equal(t, 1, s.created)
+}
@@ -6,19 +6,19 @@ import (
 	"strings"
 
 	"github.com/brevdev/brev-cli/pkg/auth"
+	"github.com/brevdev/brev-cli/pkg/cmd/bulk"
 	"github.com/brevdev/brev-cli/pkg/cmd/completions"
 	"github.com/brevdev/brev-cli/pkg/cmd/util"
 	"github.com/brevdev/brev-cli/pkg/entity"
 	breverrors "github.com/brevdev/brev-cli/pkg/errors"
 	"github.com/brevdev/brev-cli/pkg/store"
 	"github.com/brevdev/brev-cli/pkg/terminal"
-	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 )
 
 var (
 	stopLong    = "Stop a Brev machine that's in a running state"
-	stopExample = "brev stop <ws_name>...\nbrev stop --all\necho instance-name | brev stop"
+	stopExample = "brev stop <ws_name>...\nbrev stop --all\necho instance-name | brev stop\nbrev stop --status RUNNING --older-than 2d --parallel 8"
 )
 
 type StopStore interface {
@@ -33,6 +33,7 @@ type StopStore interface {
 
 func NewCmdStop(t *terminal.Terminal, loginStopStore StopStore, noLoginStopStore StopStore) *cobra.Command {
 	var all bool
+	var flags bulk.Flags
 
 	cmd := &cobra.Command{
 		Annotations:           map[string]string{"provider-dependent": ""},
@@ -45,134 +46,105 @@ func NewCmdStop(t *terminal.Terminal, loginStopStore StopStore, noLoginStopStore
 		ValidArgsFunction: completions.GetAllWorkspaceNameCompletionHandler(noLoginStopStore, t),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			piped := util.IsStdoutPiped()
-			if all {
-				return stopAllWorkspaces(t, loginStopStore, piped)
-			} else {
-				names, err := util.GetInstanceNames(args)
-				if err != nil {
-					return breverrors.WrapAndTrace(err)
-				}
-				var allErr error
-				var stoppedNames []string
-				for _, name := range names {
-					err := stopWorkspace(name, t, loginStopStore, piped)
-					if err != nil {
-						allErr = multierror.Append(allErr, err)
-					} else {
-						stoppedNames = append(stoppedNames, name)
-					}
-				}
-				if allErr != nil {
-					return breverrors.WrapAndTrace(allErr)
-				}
-				// Only output names for piping if all succeeded
-				if piped {
-					for _, name := range stoppedNames {
-						fmt.Println(name)
-					}
-				}
+			names, stdinPiped := util.GetInstanceNamesWithPipeInfo(args)
+			opts := bulk.Options{Names: names, All: all, Confirmer: bulk.Prompter(stdinPiped), Piped: piped}
+			if bulk.UseBulk(flags, opts) {
+				return bulk.Run(t, loginStopStore, stopOperation(loginStopStore), flags, opts)
+			}
+			if len(names) == 0 {
+				return breverrors.NewValidationError("instance name required: provide as argument or pipe from another command")
+			}
+			err := stopWorkspace(names[0], t, loginStopStore, piped)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+			// Output name for piping to next command
+			if piped {
+				fmt.Println(names[0])
 			}
 			return nil
 		},
 	}
 	cmd.Flags().BoolVarP(&all, "all", "a", false, "stop all instances")
+	bulk.AddFlags(cmd, &flags)
 
 	return cmd
 }
 
-func stopAllWorkspaces(t *terminal.Terminal, stopStore StopStore, piped bool) error {
-	org, err := stopStore.GetActiveOrganizationOrDefault()
-	if err != nil {
-		return breverrors.WrapAndTrace(err)
-	}
-
-	var workspaces []entity.Workspace
-	if auth.IsAPIKeyAuthStore(stopStore) {
-		workspaces, err = stopStore.GetWorkspaces(org.ID, nil)
-	} else {
-		user, userErr := stopStore.GetCurrentUser()
-		if userErr != nil {
-			return breverrors.WrapAndTrace(userErr)
-		}
-		workspaces, err = stopStore.GetWorkspaces(org.ID, &store.GetWorkspacesOptions{UserID: user.ID})
-	}
-	if err != nil {
-		return breverrors.WrapAndTrace(err)
-	}
-	if !piped {
-		t.Vprintf("Turning off all of your instances")
-	}
-	var stoppedNames []string
-	for _, v := range workspaces {
-		if v.Status == entity.Running {
-			if err := validateWorkspaceStoppable(&v); err != nil {
-				if !piped {
-					t.Vprintf("%s", t.Yellow("\n%s skipped (does not support stop)", v.Name))
-				}
-				continue
+// stopOperation stops running instances that support stop
+func stopOperation(stopStore StopStore) bulk.Operation {
+	return bulk.Operation{
+		Verb: "stop",
+		Done: "stopped",
+		Resolve: func(name string) (*entity.Workspace, error) {
+			return findWorkspace(name, stopStore)
+		},
+		Check: func(w entity.Workspace) error {
+			if w.Status != entity.Running {
+				return bulk.SkipError{Reason: fmt.Sprintf("instance is %s, not %s", w.Status, entity.Running)}
 			}
-			_, err = stopStore.StopWorkspace(v.ID)
+			return validateWorkspaceStoppable(&w)
+		},
+		Do: func(w entity.Workspace) error {
+			_, err := stopStore.StopWorkspace(w.ID)
 			if err != nil {
 				return breverrors.WrapAndTrace(err)
-			} else {
-				stoppedNames = append(stoppedNames, v.Name)
-				if !piped {
-					t.Vprintf("%s", t.Green("\n%s stopped ✓", v.Name))
-				}
 			}
-		}
-	}
-	// Output names for piping to next command
-	if piped {
-		for _, name := range stoppedNames {
-			fmt.Println(name)
-		}
+			return nil
+		},
 	}
-	return nil
 }
 
-func stopWorkspace(workspaceName string, t *terminal.Terminal, stopStore StopStore, piped bool) error {
-	user := &entity.User{}
-	apiKeyAuth := false
-	var err error
-	if auth.IsAPIKeyAuthStore(stopStore) {
-		apiKeyAuth = true
-	} else {
-		user, err = stopStore.GetCurrentUser()
+// findWorkspace looks up a workspace by name or ID, or the current one for
+// "self". Admins can find workspaces they don't own.
+func findWorkspace(workspaceName string, stopStore StopStore) (*entity.Workspace, error) {
+	if workspaceName == "self" {
+		wsID, err := stopStore.GetCurrentWorkspaceID()
 		if err != nil {
-			return breverrors.WrapAndTrace(err)
+			return nil, breverrors.WrapAndTrace(err)
 		}
+		workspace, err := util.GetAnyWorkspaceByIDOrNameInActiveOrgErr(stopStore, wsID)
+		if err != nil {
+			return nil, breverrors.WrapAndTrace(err)
+		}
+		return workspace, nil
+	}
+	workspace, err := util.GetUserWorkspaceByNameOrIDErr(stopStore, workspaceName)
+	if err == nil {
+		return workspace, nil
+	}
+	if !strings.Contains(err.Error(), "not found") || auth.IsAPIKeyAuthStore(stopStore) {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	user, userErr := stopStore.GetCurrentUser()
+	if userErr != nil {
+		return nil, breverrors.WrapAndTrace(userErr)
 	}
+	if user.GlobalUserType != entity.Admin {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	workspace, err = util.GetAnyWorkspaceByIDOrNameInActiveOrgErr(stopStore, workspaceName)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	return workspace, nil
+}
 
+func stopWorkspace(workspaceName string, t *terminal.Terminal, stopStore StopStore, piped bool) error {
 	var workspaceID string
-
 	if workspaceName == "self" {
-		wsID, err2 := stopStore.GetCurrentWorkspaceID()
-		if err2 != nil {
+		wsID, err := stopStore.GetCurrentWorkspaceID()
+		if err != nil {
 			if !piped {
-				t.Vprintf("\n Error: %s", t.Red(err2.Error()))
+				t.Vprintf("\n Error: %s", t.Red(err.Error()))
 			}
-			return breverrors.WrapAndTrace(err2)
+			return breverrors.WrapAndTrace(err)
 		}
 		workspaceID = wsID
 	} else {
-		workspace, err3 := util.GetUserWorkspaceByNameOrIDErr(stopStore, workspaceName)
-		if err3 != nil {
-			if !strings.Contains(err3.Error(), "not found") {
-				return breverrors.WrapAndTrace(err3)
-			} else {
-				if !apiKeyAuth && user.GlobalUserType == entity.Admin {
-					if !piped {
-						fmt.Println("admin trying to stop any instance")
-					}
-					workspace, err = util.GetAnyWorkspaceByIDOrNameInActiveOrgErr(stopStore, workspaceName)
-					if err != nil {
-						return breverrors.WrapAndTrace(err)
-					}
-				} else {
-					return breverrors.WrapAndTrace(err3)
-				}
-			}
+		workspace, err := findWorkspace(workspaceName, stopStore)
+		if err != nil {
+			return err
 		}
 		if err = validateWorkspaceStoppable(workspace); err != nil {
 			return err
@@ -180,7 +152,7 @@ func stopWorkspace(workspaceName string, t *terminal.Terminal, stopStore StopSto
 		workspaceID = workspace.ID
 	}
 
-	_, err = stopStore.StopWorkspace(workspaceID)
+	_, err := stopStore.StopWorkspace(workspaceID)
 	if err != nil {
 		return breverrors.WrapAndTrace(err)
 	} else if !piped {
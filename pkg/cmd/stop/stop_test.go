@@ -1,10 +1,22 @@
 package stop
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/bulk"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStopWorkspaceSelf(_ *testing.T) {
 	// err := stopThisWorkspace(nil, nil)
 	// assert.Nil(t, err)
 }
+
+func TestStopOperationSkipsInstancesNotRunning(t *testing.T) {
+	err := stopOperation(nil).Check(entity.Workspace{Name: "train-a", Status: entity.Stopped})
+	var skip bulk.SkipError
+	assert.True(t, errors.As(err, &skip))
+	assert.Contains(t, err.Error(), "not RUNNING")
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"context"
+	"time"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/cenkalti/backoff/v4"
+)
+
+// PollOptions control how often Poll checks
+type PollOptions struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	// Jitter spreads each wait randomly, e.g. 0.2 waits up to 20% more or
+	// less, so many clients polling the API do not line up
+	Jitter float64
+}
+
+// DefaultPollOptions start at 2s and back off to 30s
+var DefaultPollOptions = PollOptions{
+	InitialInterval: 2 * time.Second,
+	MaxInterval:     30 * time.Second,
+	Jitter:          0.2,
+}
+
+// Poll calls check until it returns true or an error, waiting longer after
+// each call. It returns ctx's error, wrapped, once ctx is cancelled or its
+// deadline passes.
+func Poll(ctx context.Context, opts PollOptions, check func() (bool, error)) error {
+	b := backoff.NewExponentialBackOff(
+		backoff.WithInitialInterval(opts.InitialInterval),
+		backoff.WithMaxInterval(opts.MaxInterval),
+		backoff.WithMaxElapsedTime(0),
+		backoff.WithRandomizationFactor(opts.Jitter),
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		timer := time.NewTimer(b.NextBackOff())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return breverrors.WrapAndTrace(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoll(t *testing.T) {
+	opts := PollOptions{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+	calls := 0
+	err := Poll(context.Background(), opts, func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	boom := errors.New("boom")
+	err = Poll(context.Background(), opts, func() (bool, error) { return false, boom })
+	assert.Equal(t, boom, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = Poll(ctx, opts, func() (bool, error) { return false, nil })
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = Poll(ctx, opts, func() (bool, error) { t.Fatal("check called after cancel"); return false, nil })
+	assert.True(t, errors.Is(err, context.Canceled))
+}
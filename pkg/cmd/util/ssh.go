@@ -1,6 +1,7 @@
 package util
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -30,24 +31,20 @@ type WorkspaceStartStore interface {
 func PollUntil(s *spinner.Spinner, wsid string, state string, pollingStore WorkspacePollingStore, waitMsg string, timeout time.Duration) error {
 	s.Suffix = waitMsg
 	s.Start()
-	deadline := time.Now().Add(timeout)
-	for {
-		if time.Now().After(deadline) {
-			s.Stop()
-			return breverrors.WrapAndTrace(fmt.Errorf("timed out waiting for instance to reach %s state after %v", state, timeout))
-		}
-		time.Sleep(5 * time.Second)
+	defer s.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := Poll(ctx, DefaultPollOptions, func() (bool, error) {
 		ws, err := pollingStore.GetWorkspace(wsid)
 		if err != nil {
-			s.Stop()
-			return breverrors.WrapAndTrace(err)
-		}
-		s.Suffix = waitMsg
-		if ws.Status == state {
-			s.Stop()
-			return nil
+			return false, breverrors.WrapAndTrace(err)
 		}
+		return ws.Status == state, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return breverrors.WrapAndTrace(fmt.Errorf("timed out waiting for instance to reach %s state after %v", state, timeout))
 	}
+	return err
 }
 
 // WaitForSSHToBeAvailable polls until an SSH connection can be established
@@ -0,0 +1,13 @@
+package util
+
+import "github.com/jedib0t/go-pretty/v6/table"
+
+// BrevTableOptions returns the borderless table style used by brev's list commands
+func BrevTableOptions() table.Options {
+	options := table.OptionsDefault
+	options.DrawBorder = false
+	options.SeparateColumns = false
+	options.SeparateRows = false
+	options.SeparateHeader = false
+	return options
+}
@@ -0,0 +1,332 @@
+// Package wait blocks until instances reach a state, so scripts can sequence
+// create, wait and exec
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/completions"
+	"github.com/brevdev/brev-cli/pkg/cmd/readiness"
+	cmdutil "github.com/brevdev/brev-cli/pkg/cmd/util"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes other than 0 and 1, for scripts
+const (
+	ExitTimeout = 2 // --timeout expired
+	ExitFailed  = 3 // an instance can no longer meet the condition
+)
+
+const (
+	defaultTimeout = 15 * time.Minute
+	// maxPollErrors is how many polls in a row may fail to look up instances
+	maxPollErrors = 5
+)
+
+const (
+	forStatus    = "status"
+	forDeleted   = "deleted"
+	forSSHReady  = "ssh-ready"
+	forSetupDone = "setup-done"
+)
+
+var (
+	waitLong = `Wait until every instance meets a condition, then exit.
+
+Conditions (--for):
+  status=STATUS[|STATUS]  the instance has one of the statuses, e.g. status=RUNNING or status=STOPPED
+  deleted                 the instance no longer exists
+  ssh-ready               the instance is RUNNING and accepts SSH connections
+  setup-done              the instance is RUNNING and its setup has completed
+
+Exit codes:
+  0  every instance met the condition
+  1  invalid arguments or an API error
+  2  --timeout expired
+  3  an instance failed: its status became FAILURE, its setup failed, or it was deleted`
+	waitExample = `  brev wait my-instance --for status=RUNNING
+  brev wait train-1 train-2 --for ssh-ready --timeout 20m
+  brev create my-instance && brev wait my-instance --for setup-done && brev exec my-instance "nvidia-smi"
+  brev delete my-instance && brev wait my-instance --for deleted`
+)
+
+// pollOptions is replaced in tests
+var pollOptions = cmdutil.DefaultPollOptions
+
+type WaitStore interface {
+	completions.CompletionStore
+	cmdutil.GetWorkspaceByNameOrIDErrStore
+}
+
+func NewCmdWait(t *terminal.Terminal, loginWaitStore WaitStore, noLoginWaitStore WaitStore) *cobra.Command {
+	var forCondition string
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Annotations:           map[string]string{"workspace": ""},
+		Use:                   "wait",
+		DisableFlagsInUseLine: true,
+		Short:                 "Wait until instances reach a state",
+		Long:                  waitLong,
+		Example:               waitExample,
+		ValidArgsFunction:     completions.GetAllWorkspaceNameCompletionHandler(noLoginWaitStore, t),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cond, err := parseCondition(forCondition)
+			if err != nil {
+				return err
+			}
+			if timeout < 0 {
+				return breverrors.NewValidationError("--timeout must not be negative")
+			}
+			names, err := cmdutil.GetInstanceNames(args)
+			if err != nil {
+				return breverrors.WrapAndTrace(err)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			w := &waiter{terminal: t, store: loginWaitStore, cond: cond, checker: readiness.SSHChecker{}, piped: cmdutil.IsStdoutPiped()}
+			return w.run(ctx, names, timeout)
+		},
+	}
+	cmd.Flags().StringVar(&forCondition, "for", "", "Condition to wait for: status=STATUS, deleted, ssh-ready or setup-done")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultTimeout, "Give up after this long, e.g. 30s or 20m; 0 waits forever")
+	_ = cmd.MarkFlagRequired("for")
+	err := cmd.RegisterFlagCompletionFunc("for", cobra.FixedCompletions([]string{"status=RUNNING", "status=STOPPED", forDeleted, forSSHReady, forSetupDone}, cobra.ShellCompDirectiveNoFileComp))
+	if err != nil {
+		breverrors.GetDefaultErrorReporter().ReportError(breverrors.WrapAndTrace(err))
+	}
+	return cmd
+}
+
+// condition is a parsed --for
+type condition struct {
+	kind     string
+	statuses map[string]bool
+}
+
+func parseCondition(s string) (*condition, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case forDeleted, forSSHReady, forSetupDone:
+		return &condition{kind: strings.ToLower(strings.TrimSpace(s))}, nil
+	}
+	key, statuses, found := strings.Cut(s, "=")
+	if !found || !strings.EqualFold(strings.TrimSpace(key), forStatus) {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --for %q: use status=STATUS, deleted, ssh-ready or setup-done", s))
+	}
+	c := &condition{kind: forStatus, statuses: map[string]bool{}}
+	for _, status := range strings.Split(statuses, "|") {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if status == "" {
+			return nil, breverrors.NewValidationError(fmt.Sprintf("invalid --for %q: missing status", s))
+		}
+		c.statuses[status] = true
+	}
+	return c, nil
+}
+
+func (c *condition) String() string {
+	switch c.kind {
+	case forDeleted:
+		return "deleted"
+	case forSSHReady:
+		return "ready for SSH"
+	case forSetupDone:
+		return "done with setup"
+	default:
+		statuses := make([]string, 0, len(c.statuses))
+		for status := range c.statuses {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		return strings.Join(statuses, " or ")
+	}
+}
+
+// instanceFailed means an instance can no longer meet the condition
+type instanceFailed struct {
+	name   string
+	reason string
+}
+
+func (e instanceFailed) Error() string {
+	return fmt.Sprintf("%s %s", e.name, e.reason)
+}
+
+// evaluate returns true if w meets the condition. w is nil once the instance
+// is gone. The returned error is an instanceFailed.
+func (c *condition) evaluate(name string, w *entity.Workspace) (bool, error) {
+	if w == nil {
+		if c.kind == forDeleted {
+			return true, nil
+		}
+		return false, instanceFailed{name: name, reason: "no longer exists"}
+	}
+	switch c.kind {
+	case forDeleted:
+		return false, nil
+	case forStatus:
+		if c.statuses[w.Status] || c.statuses[displayStatus(*w)] {
+			return true, nil
+		}
+	case forSetupDone:
+		if w.VerbBuildStatus == entity.CreateFailed {
+			return false, instanceFailed{name: name, reason: "failed to set up"}
+		}
+		if w.Status == entity.Running && w.VerbBuildStatus == entity.Completed {
+			return true, nil
+		}
+	case forSSHReady:
+		// the caller tries SSH once the instance is running
+		if w.Status == entity.Running {
+			return true, nil
+		}
+	}
+	if w.Status == entity.Failure {
+		return false, instanceFailed{name: name, reason: "is " + entity.Failure}
+	}
+	return false, nil
+}
+
+// displayStatus is the status brev ls shows
+func displayStatus(w entity.Workspace) string {
+	if w.Status == entity.Running && w.HealthStatus == entity.Unhealthy {
+		return w.HealthStatus
+	}
+	return w.Status
+}
+
+// waiter polls a set of instances until they all meet the condition
+type waiter struct {
+	terminal  *terminal.Terminal
+	store     WaitStore
+	cond      *condition
+	checker   readiness.Checker
+	piped     bool
+	refreshed bool // the SSH config was refreshed for ssh-ready
+}
+
+func (w *waiter) run(ctx context.Context, names []string, timeout time.Duration) error {
+	if !w.piped {
+		w.terminal.Vprintf("Waiting for %s to be %s\n", strings.Join(names, ", "), w.cond)
+	}
+	pending := names
+	statuses := map[string]string{}
+	pollErrors := 0
+	err := cmdutil.Poll(ctx, pollOptions, func() (bool, error) {
+		var still []string
+		var lookupErr error
+		for _, name := range pending {
+			met, status, err := w.check(ctx, name)
+			var failed instanceFailed
+			switch {
+			case errors.As(err, &failed):
+				return false, breverrors.NewExitCodeError(ExitFailed, breverrors.NewValidationError(failed.Error()))
+			case err != nil:
+				lookupErr = err
+				still = append(still, name)
+			case met:
+				w.printMet(name)
+			default:
+				statuses[name] = status
+				still = append(still, name)
+			}
+		}
+		pending = still
+
+		if lookupErr == nil {
+			pollErrors = 0
+			return len(pending) == 0, nil
+		}
+		pollErrors++
+		if pollErrors >= maxPollErrors {
+			return false, lookupErr
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "failed to check instances, retrying: %v\n", lookupErr)
+		return false, nil
+	})
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return breverrors.NewExitCodeError(ExitTimeout, breverrors.NewValidationError(fmt.Sprintf("timed out after %s waiting for %s to be %s",
+			timeout, describePending(pending, statuses), w.cond)))
+	case errors.Is(err, context.Canceled):
+		return breverrors.NewValidationError("interrupted")
+	default:
+		return err
+	}
+}
+
+// check looks up an instance and evaluates the condition
+func (w *waiter) check(ctx context.Context, name string) (bool, string, error) {
+	workspace, err := cmdutil.GetUserWorkspaceByNameOrIDErr(w.store, name)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return false, "", breverrors.WrapAndTrace(err)
+		}
+		workspace = nil
+	}
+	met, err := w.cond.evaluate(name, workspace)
+	if err != nil || !met {
+		status := ""
+		if workspace != nil {
+			status = displayStatus(*workspace)
+		}
+		return false, status, err
+	}
+	if w.cond.kind != forSSHReady {
+		return true, "", nil
+	}
+
+	if !w.refreshed {
+		if err := readiness.RefreshSSHConfig(w.store); err != nil {
+			return false, "", err
+		}
+		w.refreshed = true
+	}
+	probe := readiness.Probe{Kind: readiness.KindSSH, Target: "true"}
+	attemptCtx, cancel := context.WithTimeout(ctx, readiness.MaxAttempt)
+	defer cancel()
+	if err := w.checker.Check(attemptCtx, string(workspace.GetLocalIdentifier()), probe); err != nil {
+		return false, "RUNNING, no SSH yet", nil
+	}
+	return true, "", nil
+}
+
+// printMet reports an instance that met the condition. When piped only its
+// name is printed, for the next command.
+func (w *waiter) printMet(name string) {
+	if w.piped {
+		fmt.Println(name)
+		return
+	}
+	w.terminal.Vprint(w.terminal.Green("✓ %s is %s\n", name, w.cond))
+}
+
+// describePending lists the instances still waited for with their last status
+func describePending(pending []string, statuses map[string]string) string {
+	parts := make([]string, 0, len(pending))
+	for _, name := range pending {
+		if status := statuses[name]; status != "" {
+			parts = append(parts, fmt.Sprintf("%s (%s)", name, status))
+		} else {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,169 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/readiness"
+	cmdutil "github.com/brevdev/brev-cli/pkg/cmd/util"
+	"github.com/brevdev/brev-cli/pkg/entity"
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/store"
+	"github.com/brevdev/brev-cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockWaitStore returns one workspace per lookup of a name, repeating the
+// last. A nil workspace is not found.
+type mockWaitStore struct {
+	polls map[string][]*entity.Workspace
+	calls map[string]int
+}
+
+func newMockWaitStore(polls map[string][]*entity.Workspace) *mockWaitStore {
+	return &mockWaitStore{polls: polls, calls: map[string]int{}}
+}
+
+func (m *mockWaitStore) GetAuthTokens() (*entity.AuthTokens, error) {
+	return &entity.AuthTokens{}, nil
+}
+
+func (m *mockWaitStore) GetCurrentUser() (*entity.User, error) {
+	return &entity.User{ID: "u1"}, nil
+}
+
+func (m *mockWaitStore) GetActiveOrganizationOrDefault() (*entity.Organization, error) {
+	return &entity.Organization{ID: "org1"}, nil
+}
+
+func (m *mockWaitStore) GetOrganizations(_ *store.GetOrganizationsOptions) ([]entity.Organization, error) {
+	return nil, nil
+}
+
+func (m *mockWaitStore) GetWorkspaces(_ string, _ *store.GetWorkspacesOptions) ([]entity.Workspace, error) {
+	return nil, nil
+}
+
+func (m *mockWaitStore) GetWorkspaceByNameOrID(_ string, nameOrID string) ([]entity.Workspace, error) {
+	polls := m.polls[nameOrID]
+	i := m.calls[nameOrID]
+	m.calls[nameOrID]++
+	if i >= len(polls) {
+		i = len(polls) - 1
+	}
+	if i < 0 || polls[i] == nil {
+		return nil, nil
+	}
+	return []entity.Workspace{*polls[i]}, nil
+}
+
+func instance(name, status string) *entity.Workspace {
+	return &entity.Workspace{ID: name, Name: name, Status: status, CreatedByUserID: "u1"}
+}
+
+// mockChecker fails the first failures SSH attempts
+type mockChecker struct {
+	failures int
+	calls    int
+}
+
+func (m *mockChecker) Check(_ context.Context, _ string, _ readiness.Probe) error {
+	m.calls++
+	if m.calls <= m.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func fastPolling(t *testing.T) {
+	t.Helper()
+	orig := pollOptions
+	pollOptions = cmdutil.PollOptions{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, Jitter: 0.5}
+	t.Cleanup(func() { pollOptions = orig })
+}
+
+func runWaiter(t *testing.T, s *mockWaitStore, forCondition string, timeout time.Duration, names ...string) error {
+	t.Helper()
+	cond, err := parseCondition(forCondition)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	w := &waiter{terminal: terminal.New(), store: s, cond: cond, checker: &mockChecker{}, piped: true}
+	return w.run(ctx, names, timeout)
+}
+
+func TestParseCondition(t *testing.T) {
+	c, err := parseCondition("status=running|Stopped")
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING or STOPPED", c.String())
+
+	for _, good := range []string{"deleted", "ssh-ready", "Setup-Done", "STATUS=FAILURE"} {
+		_, err := parseCondition(good)
+		assert.NoError(t, err, good)
+	}
+	for _, bad := range []string{"", "running", "state=RUNNING", "status=", "status=RUNNING||STOPPED"} {
+		_, err := parseCondition(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestWait_StatusReached(t *testing.T) {
+	fastPolling(t)
+	s := newMockWaitStore(map[string][]*entity.Workspace{
+		"a": {instance("a", entity.Deploying), instance("a", entity.Starting), instance("a", entity.Running)},
+		"b": {instance("b", entity.Running)},
+	})
+	require.NoError(t, runWaiter(t, s, "status=RUNNING", time.Second, "a", "b"))
+	assert.Equal(t, 3, s.calls["a"])
+	// b met the condition on the first poll and is not looked up again
+	assert.Equal(t, 1, s.calls["b"])
+}
+
+func TestWait_TimeoutExitCode(t *testing.T) {
+	fastPolling(t)
+	s := newMockWaitStore(map[string][]*entity.Workspace{"a": {instance("a", entity.Deploying)}})
+	err := runWaiter(t, s, "status=RUNNING", 30*time.Millisecond, "a")
+	require.Error(t, err)
+	assert.Equal(t, ExitTimeout, breverrors.ExitCode(err))
+	assert.Contains(t, err.Error(), "a (DEPLOYING)")
+}
+
+func TestWait_FailureExitCode(t *testing.T) {
+	fastPolling(t)
+	cases := map[string][]*entity.Workspace{
+		"status=RUNNING": {instance("a", entity.Deploying), instance("a", entity.Failure)},
+		"ssh-ready":      {instance("a", entity.Deploying), nil},
+		"setup-done":     {{ID: "a", Name: "a", Status: entity.Running, VerbBuildStatus: entity.CreateFailed, CreatedByUserID: "u1"}},
+	}
+	for forCondition, polls := range cases {
+		s := newMockWaitStore(map[string][]*entity.Workspace{"a": polls})
+		err := runWaiter(t, s, forCondition, time.Second, "a")
+		require.Error(t, err, forCondition)
+		assert.Equal(t, ExitFailed, breverrors.ExitCode(err), forCondition)
+	}
+
+	// waiting for FAILURE is not a failure
+	s := newMockWaitStore(map[string][]*entity.Workspace{"a": {instance("a", entity.Failure)}})
+	assert.NoError(t, runWaiter(t, s, "status=FAILURE", time.Second, "a"))
+}
+
+func TestWait_Deleted(t *testing.T) {
+	fastPolling(t)
+	s := newMockWaitStore(map[string][]*entity.Workspace{"a": {instance("a", entity.Deleting), instance("a", entity.Deleting), nil}})
+	require.NoError(t, runWaiter(t, s, "deleted", time.Second, "a"))
+	assert.Equal(t, 3, s.calls["a"])
+}
+
+func TestWait_SSHReadyRetriesSSH(t *testing.T) {
+	fastPolling(t)
+	s := newMockWaitStore(map[string][]*entity.Workspace{"a": {instance("a", entity.Running)}})
+	cond, err := parseCondition("ssh-ready")
+	require.NoError(t, err)
+	checker := &mockChecker{failures: 2}
+	w := &waiter{terminal: terminal.New(), store: s, cond: cond, checker: checker, piped: true}
+	require.NoError(t, w.run(context.Background(), []string{"a"}, 0))
+	assert.Equal(t, 3, checker.calls)
+}
@@ -315,7 +315,7 @@ type Workspace struct {
 	VerbYaml             string            `json:"verbYaml"`
 	// PrimaryApplicationId         string `json:"primaryApplicationId,omitempty"`
 	// LastOnlineAt         string `json:"lastOnlineAt,omitempty"`
-	// CreatedAt         string `json:"createdAt,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"` // RFC 3339
 	// UpdatedAt         string `json:"updatedAt,omitempty"`
 	HealthStatus    string        `json:"healthStatus"`
 	IsStoppable     bool          `json:"isStoppable"` // used for autopstop only
@@ -323,6 +323,15 @@ type Workspace struct {
 	StopTimeout     time.Duration `json:"stopTimeout"`
 	AdditionalUsers []string      `json:"additionalUsers"`
 	Tunnel          Tunnel        `json:"tunnel"`
+	// Build configuration the instance was created with, when the API returns it
+	DiskStorage          string            `json:"diskStorage,omitempty"`
+	VMBuild              *VMBuild          `json:"vmBuild,omitempty"`
+	CustomContainer      *CustomContainer  `json:"customContainer,omitempty"`
+	DockerCompose        *DockerCompose    `json:"dockerCompose,omitempty"`
+	PortMappings         map[string]string `json:"portMappings,omitempty"`
+	FirewallRules        []FirewallRule    `json:"firewallRules,omitempty"`
+	LaunchJupyterOnStart bool              `json:"launchJupyterOnStart,omitempty"`
+	Labels               Labels            `json:"labels,omitempty"`
 }
 
 type APIKey struct {
@@ -367,6 +376,18 @@ const (
 	Completed    VerbBuildStatus = "COMPLETED"
 )
 
+// GetCreatedAt returns when the instance was created, if the API returned it
+func (w Workspace) GetCreatedAt() (time.Time, bool) {
+	if w.CreatedAt == "" {
+		return time.Time{}, false
+	}
+	created, err := time.Parse(time.RFC3339Nano, w.CreatedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
+
 func (w Workspace) GetStopTimeout() time.Duration {
 	return w.StopTimeout
 }
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// labelKeyRe follows the Kubernetes label key syntax, with an optional prefix/
+var labelKeyRe = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]*[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateLabelKey checks that key can be used as an instance label
+func ValidateLabelKey(key string) error {
+	if !labelKeyRe.MatchString(key) {
+		return fmt.Errorf("invalid label key %q: use letters, digits, '-', '_' and '.', starting and ending with a letter or digit", key)
+	}
+	return nil
+}
+
+// ParseLabels parses key=value labels
+func ParseLabels(values []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, value := range values {
+		key, val, found := strings.Cut(value, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", value)
+		}
+		if err := ValidateLabelKey(key); err != nil {
+			return nil, err
+		}
+		labels[key] = val
+	}
+	return labels, nil
+}
+
+// Labels are the key/value labels of an instance. Values that are not strings
+// are kept in their JSON form, and anything but an object reads as no labels,
+// so unexpected label data never breaks listing instances.
+type Labels map[string]string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *Labels) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || raw == nil {
+		*l = nil
+		return nil //nolint:nilerr // see Labels
+	}
+	labels := make(Labels, len(raw))
+	for key, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			labels[key] = s
+		} else {
+			labels[key] = string(value)
+		}
+	}
+	*l = labels
+	return nil
+}
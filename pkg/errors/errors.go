@@ -148,6 +148,33 @@ func (e WorkspaceNotRunning) Error() string {
 	return fmt.Sprintf("workspace status %s is not RUNNING", e.Status)
 }
 
+// ExitCodeError makes the CLI exit with Code instead of 1, so scripts can tell
+// failure modes apart
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func NewExitCodeError(code int, err error) *ExitCodeError {
+	return &ExitCodeError{Code: code, Err: err}
+}
+
+func (e *ExitCodeError) Error() string { return e.Err.Error() }
+func (e *ExitCodeError) Unwrap() error { return e.Err }
+
+// Cause lets pkg/errors.Cause find the error underneath, so it is displayed
+// like any other
+func (e *ExitCodeError) Cause() error { return e.Err }
+
+// ExitCode returns the code of the first ExitCodeError in err's chain, or 1
+func ExitCode(err error) int {
+	var exitErr *ExitCodeError
+	if stderrors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
 var New = stderrors.New
 
 var Errorf = fmt.Errorf
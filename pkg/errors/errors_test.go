@@ -234,3 +234,13 @@ func Test_combine(t *testing.T) {
 
 	assert.Equal(t, "my error 1", cerr.Error())
 }
+
+func Test_ExitCode(t *testing.T) {
+	validation := NewValidationError("timed out")
+	err := NewExitCodeError(2, validation)
+
+	assert.Equal(t, 2, ExitCode(err))
+	assert.Equal(t, 2, ExitCode(WrapAndTrace(err)))
+	assert.Equal(t, 1, ExitCode(validation))
+	assert.Equal(t, validation, pkgerrors.Cause(err)) // displayed as the validation error
+}
@@ -0,0 +1,72 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// TeamPolicyFileName is the team policy file, usually committed at the root of a repository
+const TeamPolicyFileName = "brev-policy.json"
+
+// Budget policy actions when a cap would be exceeded
+const (
+	OnExceedConfirm = "confirm"
+	OnExceedRefuse  = "refuse"
+)
+
+// BudgetPolicy caps the hourly spend of running instances. Zero values mean no cap.
+type BudgetPolicy struct {
+	MaxHourly     float64 `json:"max_hourly,omitempty"`      // $/hr of all running instances in the org, including the ones being started
+	MaxPerCommand float64 `json:"max_per_command,omitempty"` // $/hr added by a single create or start
+	OnExceed      string  `json:"on_exceed,omitempty"`       // confirm (default) or refuse
+}
+
+// Validate checks that on_exceed is one of the known actions. A nil policy is valid.
+func (b *BudgetPolicy) Validate() error {
+	if b != nil && b.OnExceed != "" && b.OnExceed != OnExceedConfirm && b.OnExceed != OnExceedRefuse {
+		return fmt.Errorf("on_exceed must be %q or %q", OnExceedConfirm, OnExceedRefuse)
+	}
+	return nil
+}
+
+// TeamPolicy is the content of a team policy file
+type TeamPolicy struct {
+	Budget *BudgetPolicy `json:"budget,omitempty"`
+}
+
+// FindTeamPolicyFile looks for the team policy file in dir and its parents.
+// It returns an empty path if there is none.
+func FindTeamPolicyFile(fs afero.Fs, dir string) string {
+	dir = filepath.Clean(dir)
+	for {
+		path := filepath.Join(dir, TeamPolicyFileName)
+		if exists, err := afero.Exists(fs, path); err == nil && exists {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ReadTeamPolicy reads a team policy file. Unlike personal settings, a
+// malformed file is an error so that a broken policy is not silently ignored.
+func ReadTeamPolicy(fs afero.Fs, path string) (*TeamPolicy, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading team policy: %w", err)
+	}
+	var policy TeamPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parsing team policy %s: %w", path, err)
+	}
+	if err := policy.Budget.Validate(); err != nil {
+		return nil, fmt.Errorf("team policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
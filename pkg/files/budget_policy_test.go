@@ -0,0 +1,42 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTeamPolicyFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/work/repo/brev-policy.json", []byte(`{}`), 0o600))
+
+	assert.Equal(t, "/work/repo/brev-policy.json", FindTeamPolicyFile(fs, "/work/repo/src/pkg"))
+	assert.Equal(t, "/work/repo/brev-policy.json", FindTeamPolicyFile(fs, "/work/repo"))
+	assert.Empty(t, FindTeamPolicyFile(fs, "/work/other"))
+}
+
+func TestReadTeamPolicy(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/p.json", []byte(`{"budget": {"max_hourly": 120, "on_exceed": "refuse"}}`), 0o600))
+	policy, err := ReadTeamPolicy(fs, "/p.json")
+	require.NoError(t, err)
+	assert.Equal(t, &BudgetPolicy{MaxHourly: 120, OnExceed: OnExceedRefuse}, policy.Budget)
+
+	require.NoError(t, afero.WriteFile(fs, "/bad.json", []byte(`{"budget": {"on_exceed": "warn"}}`), 0o600))
+	_, err = ReadTeamPolicy(fs, "/bad.json")
+	assert.ErrorContains(t, err, "on_exceed")
+
+	require.NoError(t, afero.WriteFile(fs, "/broken.json", []byte(`{`), 0o600))
+	_, err = ReadTeamPolicy(fs, "/broken.json")
+	assert.Error(t, err)
+}
+
+func TestBudgetPolicyValidate(t *testing.T) {
+	var none *BudgetPolicy
+	assert.NoError(t, none.Validate())
+	assert.NoError(t, (&BudgetPolicy{MaxHourly: 10}).Validate())
+	assert.NoError(t, (&BudgetPolicy{OnExceed: OnExceedRefuse}).Validate())
+	assert.ErrorContains(t, (&BudgetPolicy{OnExceed: "refuse "}).Validate(), "on_exceed")
+}
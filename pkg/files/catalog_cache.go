@@ -0,0 +1,57 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// CatalogCacheEntry is a cached instance-type API response persisted under
+// ~/.brev/catalog_cache/. Data holds the raw JSON response body.
+type CatalogCacheEntry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Age returns how long ago the entry was fetched.
+func (e *CatalogCacheEntry) Age(now time.Time) time.Duration {
+	return now.Sub(e.FetchedAt)
+}
+
+// CatalogCachePath returns the path of the cache file for key within the
+// given brev home directory (e.g. ~/.brev/catalog_cache/<key>.json).
+func CatalogCachePath(brevHome, key string) string {
+	return filepath.Join(brevHome, catalogCacheDirName, key+".json")
+}
+
+// ReadCatalogCache reads a cache entry. It returns an error if the file is
+// missing or malformed so callers can distinguish "no cache" from a hit.
+func ReadCatalogCache(fs afero.Fs, path string) (*CatalogCacheEntry, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading catalog cache: %w", err)
+	}
+	var entry CatalogCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("parsing catalog cache %s: %w", path, err)
+	}
+	return &entry, nil
+}
+
+// WriteCatalogCache writes a cache entry, creating the cache directory if needed.
+func WriteCatalogCache(fs afero.Fs, path string, entry *CatalogCacheEntry) error {
+	if err := fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating catalog cache directory: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling catalog cache: %w", err)
+	}
+	if err := afero.WriteFile(fs, path, data, 0o600); err != nil {
+		return fmt.Errorf("writing catalog cache: %w", err)
+	}
+	return nil
+}
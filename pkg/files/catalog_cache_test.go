@@ -0,0 +1,48 @@
+package files
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogCachePath(t *testing.T) {
+	assert.Equal(t, "/home/test/.brev/catalog_cache/instance_types.json", CatalogCachePath("/home/test/.brev", "instance_types"))
+}
+
+func TestReadCatalogCache_MissingFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	entry, err := ReadCatalogCache(fs, "/home/test/.brev/catalog_cache/instance_types.json")
+	assert.Error(t, err)
+	assert.Nil(t, entry)
+}
+
+func TestReadCatalogCache_MalformedJSON(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	path := "/home/test/.brev/catalog_cache/instance_types.json"
+	require.NoError(t, fs.MkdirAll("/home/test/.brev/catalog_cache", 0o755))
+	require.NoError(t, afero.WriteFile(fs, path, []byte("{invalid"), 0o600))
+
+	entry, err := ReadCatalogCache(fs, path)
+	assert.Error(t, err)
+	assert.Nil(t, entry)
+}
+
+func TestWriteCatalogCache_RoundTrip(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	path := "/home/test/.brev/catalog_cache/instance_types.json"
+	fetchedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := WriteCatalogCache(fs, path, &CatalogCacheEntry{FetchedAt: fetchedAt, Data: json.RawMessage(`{"items":[]}`)})
+	require.NoError(t, err)
+
+	entry, err := ReadCatalogCache(fs, path)
+	require.NoError(t, err)
+	assert.True(t, fetchedAt.Equal(entry.FetchedAt))
+	assert.JSONEq(t, `{"items":[]}`, string(entry.Data))
+	assert.Equal(t, 10*time.Minute, entry.Age(fetchedAt.Add(10*time.Minute)))
+}
@@ -16,16 +16,24 @@ import (
 )
 
 type PersonalSettings struct {
-	DefaultEditor    string `json:"default_editor"`
-	AnalyticsEnabled *bool  `json:"analytics_enabled,omitempty"` // nil = default on (opt-out model), true = explicit opt-in, false = opted out
-	AnalyticsID      string `json:"analytics_id,omitempty"`      // stable anonymous ID for analytics
+	DefaultEditor    string                  `json:"default_editor"`
+	AnalyticsEnabled *bool                   `json:"analytics_enabled,omitempty"` // nil = default on (opt-out model), true = explicit opt-in, false = opted out
+	AnalyticsID      string                  `json:"analytics_id,omitempty"`      // stable anonymous ID for analytics
+	CatalogCacheTTL  string                  `json:"catalog_cache_ttl,omitempty"` // Go duration for cached instance types, e.g. "30m"; empty = default
+	SearchPresets    map[string]SearchPreset `json:"search_presets,omitempty"`    // named filter presets for brev search/create
+	Budget           *BudgetPolicy           `json:"budget,omitempty"`            // personal spending caps for brev create/start
 }
 
+// SearchPreset maps flag names (without the leading --) to their values, e.g. {"min-vram": "80", "stoppable": "true"}
+type SearchPreset map[string]string
+
 const (
 	brevDirectory = ".brev"
 	// This might be better as a context.json??
 	activeOrgFile     = "active_org.json"
 	userCacheFileName = "user_cache.json"
+	// Cached instance-type listings used by search/create/ls
+	catalogCacheDirName = "catalog_cache"
 	// WIP: This will be used to let people "brev open" with editors other than VS Code
 	personalSettingsCache         = "personal_settings.json"
 	kubeCertFileName              = "brev.crt"
@@ -0,0 +1,118 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const schedulesFileName = "schedules.json"
+
+// Catch-up policies for runs missed while the schedule daemon was not running
+const (
+	CatchUpLast = "last" // run the most recent missed action once (default)
+	CatchUpSkip = "skip" // only record the missed runs
+)
+
+// maxScheduleHistory is how many runs are kept per schedule
+const maxScheduleHistory = 20
+
+// Schedules are the start/stop rules persisted to ~/.brev/schedules.json
+type Schedules struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+// Schedule starts and stops instances on cron schedules
+type Schedule struct {
+	ID        string           `json:"id"`
+	OrgID     string           `json:"org_id"`
+	Instances []string         `json:"instances,omitempty"` // names or IDs
+	Selector  ScheduleSelector `json:"selector,omitempty"`
+	Stop      string           `json:"stop,omitempty"`     // cron expression
+	Start     string           `json:"start,omitempty"`    // cron expression
+	Timezone  string           `json:"timezone,omitempty"` // IANA name; empty is the daemon's local time
+	CatchUp   string           `json:"catch_up,omitempty"` // last (default) or skip
+	CreatedAt time.Time        `json:"created_at"`
+	// CheckedAt is when the daemon last evaluated the schedule. Runs due
+	// between it and now are run or caught up on the next evaluation.
+	CheckedAt time.Time     `json:"checked_at,omitempty"`
+	History   []ScheduleRun `json:"history,omitempty"` // oldest first
+}
+
+// ScheduleSelector holds the instance selector flags a schedule was added with
+type ScheduleSelector struct {
+	Statuses  []string `json:"statuses,omitempty"`
+	CreatedBy string   `json:"created_by,omitempty"`
+	NameRegex string   `json:"name_regex,omitempty"`
+	OlderThan string   `json:"older_than,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+}
+
+// ScheduleRun is one start or stop the daemon ran, or skipped
+type ScheduleRun struct {
+	Action    string    `json:"action"`    // start or stop
+	Due       time.Time `json:"due"`       // the cron time the run was for
+	RanAt     time.Time `json:"ran_at"`    // when the daemon ran it
+	CatchUp   bool      `json:"catch_up"`  // the run was missed and caught up, or skipped
+	Skipped   bool      `json:"skipped"`   // missed and not caught up
+	Succeeded []string  `json:"succeeded"` // instances started or stopped
+	Error     string    `json:"error,omitempty"`
+}
+
+// AddRun appends a run to the history, dropping the oldest past the limit
+func (s *Schedule) AddRun(run ScheduleRun) {
+	s.History = append(s.History, run)
+	if len(s.History) > maxScheduleHistory {
+		s.History = s.History[len(s.History)-maxScheduleHistory:]
+	}
+}
+
+// LastRun returns the most recent run, or nil
+func (s *Schedule) LastRun() *ScheduleRun {
+	if len(s.History) == 0 {
+		return nil
+	}
+	return &s.History[len(s.History)-1]
+}
+
+// SchedulesPath returns the path to the schedules file within the given brev
+// home directory (e.g. ~/.brev)
+func SchedulesPath(brevHome string) string {
+	return filepath.Join(brevHome, schedulesFileName)
+}
+
+// ReadSchedules reads the schedules file, returning no schedules if it does
+// not exist. A malformed file is an error so rules are not silently dropped.
+func ReadSchedules(fs afero.Fs, path string) (*Schedules, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Schedules{}, nil
+		}
+		return nil, fmt.Errorf("reading schedules: %w", err)
+	}
+	var schedules Schedules
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("parsing schedules %s: %w", path, err)
+	}
+	return &schedules, nil
+}
+
+// WriteSchedules writes the schedules file
+func WriteSchedules(fs afero.Fs, path string, schedules *Schedules) error {
+	if err := fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating schedules directory: %w", err)
+	}
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling schedules: %w", err)
+	}
+	if err := afero.WriteFile(fs, path, data, 0o600); err != nil {
+		return fmt.Errorf("writing schedules: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package files
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSchedules_MissingFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	schedules, err := ReadSchedules(fs, "/home/test/.brev/schedules.json")
+	require.NoError(t, err)
+	assert.Empty(t, schedules.Schedules)
+}
+
+func TestReadSchedules_MalformedJSON(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	path := "/home/test/.brev/schedules.json"
+	require.NoError(t, afero.WriteFile(fs, path, []byte("{invalid"), 0o600))
+
+	_, err := ReadSchedules(fs, path)
+	assert.Error(t, err)
+}
+
+func TestSchedules_RoundTrip(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	path := SchedulesPath("/home/test/.brev")
+	created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	in := &Schedules{Schedules: []Schedule{{
+		ID:        "1",
+		OrgID:     "org1",
+		Selector:  ScheduleSelector{NameRegex: "^train-"},
+		Stop:      "0 20 * * 1-5",
+		Start:     "0 8 * * 1-5",
+		CatchUp:   CatchUpSkip,
+		CreatedAt: created,
+		History:   []ScheduleRun{{Action: "stop", Due: created, RanAt: created, Succeeded: []string{"train-a"}}},
+	}}}
+	require.NoError(t, WriteSchedules(fs, path, in))
+
+	out, err := ReadSchedules(fs, path)
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+}
+
+func TestSchedule_AddRunKeepsRecentHistory(t *testing.T) {
+	var s Schedule
+	assert.Nil(t, s.LastRun())
+	for i := 0; i < maxScheduleHistory+5; i++ {
+		s.AddRun(ScheduleRun{Action: "stop", Due: time.Unix(int64(i), 0)})
+	}
+	assert.Len(t, s.History, maxScheduleHistory)
+	assert.Equal(t, time.Unix(5, 0), s.History[0].Due)
+	assert.Equal(t, time.Unix(maxScheduleHistory+4, 0), s.LastRun().Due)
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"fmt"
+	"os"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+)
+
+// teamPolicyEnv points at a team policy file, overriding the search from the working directory
+const teamPolicyEnv = "BREV_POLICY_FILE"
+
+// GetPersonalBudgetPolicy returns the spending caps from personal settings, or nil
+func (f FileStore) GetPersonalBudgetPolicy() (*files.BudgetPolicy, error) {
+	home, err := f.UserHomeDir()
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	settings, err := files.ReadPersonalSettings(f.fs, home)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if err := settings.Budget.Validate(); err != nil {
+		return nil, breverrors.NewValidationError(fmt.Sprintf("personal settings %s: budget %v", files.GetPersonalSettingsPath(home), err))
+	}
+	return settings.Budget, nil
+}
+
+// GetTeamBudgetPolicy returns the spending caps from the team policy file and
+// its path, or nil if there is no policy file
+func (f FileStore) GetTeamBudgetPolicy() (*files.BudgetPolicy, string, error) {
+	path := os.Getenv(teamPolicyEnv)
+	if path == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, "", breverrors.WrapAndTrace(err)
+		}
+		path = files.FindTeamPolicyFile(f.fs, wd)
+	}
+	if path == "" {
+		return nil, "", nil
+	}
+	policy, err := files.ReadTeamPolicy(f.fs, path)
+	if err != nil {
+		return nil, "", breverrors.WrapAndTrace(err)
+	}
+	return policy.Budget, path, nil
+}
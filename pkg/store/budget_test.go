@@ -0,0 +1,24 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPersonalBudgetPolicy_ValidatesOnExceed(t *testing.T) {
+	s := newTestFileStore(t)
+	path := files.GetPersonalSettingsPath("/home/testuser")
+
+	require.NoError(t, afero.WriteFile(s.fs, path, []byte(`{"budget": {"max_hourly": 20, "on_exceed": "refuse"}}`), 0o600))
+	policy, err := s.GetPersonalBudgetPolicy()
+	require.NoError(t, err)
+	assert.Equal(t, &files.BudgetPolicy{MaxHourly: 20, OnExceed: files.OnExceedRefuse}, policy)
+
+	require.NoError(t, afero.WriteFile(s.fs, path, []byte(`{"budget": {"max_hourly": 20, "on_exceed": "block"}}`), 0o600))
+	_, err = s.GetPersonalBudgetPolicy()
+	assert.ErrorContains(t, err, "on_exceed")
+}
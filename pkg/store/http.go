@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
 	"github.com/brevdev/brev-cli/pkg/cmd/version"
 	breverrors "github.com/brevdev/brev-cli/pkg/errors"
 	"github.com/brevdev/brev-cli/pkg/featureflag"
@@ -17,10 +18,11 @@ type NoAuthHTTPStore struct {
 	FileStore
 	noAuthHTTPClient *NoAuthHTTPClient
 	BasicStore
+	catalogCachePolicy gpusearch.CatalogCachePolicy
 }
 
 func (f *FileStore) WithNoAuthHTTPClient(c *NoAuthHTTPClient) *NoAuthHTTPStore {
-	return &NoAuthHTTPStore{*f, c, f.b}
+	return &NoAuthHTTPStore{FileStore: *f, noAuthHTTPClient: c, BasicStore: f.b}
 }
 
 // Used if need new instance to customize settings
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
 	"github.com/brevdev/brev-cli/pkg/config"
 	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
 )
 
 const (
@@ -16,14 +18,100 @@ const (
 	allInstanceTypesPathPattern = "api/instances/alltypesavailable/%s"
 )
 
+// catalogCacheNow is a var so tests can control cache freshness
+var catalogCacheNow = time.Now
+
+// SetCatalogCachePolicy controls how instance-type listings use the on-disk cache
+func (s *NoAuthHTTPStore) SetCatalogCachePolicy(policy gpusearch.CatalogCachePolicy) {
+	s.catalogCachePolicy = policy
+}
+
 // GetInstanceTypes fetches all available instance types from the public API
 func (s NoAuthHTTPStore) GetInstanceTypes(includeCPU bool) (*gpusearch.InstanceTypesResponse, error) {
-	return fetchInstanceTypes(includeCPU)
+	return cachedInstanceTypes(s.FileStore, s.catalogCachePolicy, includeCPU)
 }
 
 // GetInstanceTypes fetches all available instance types from the public API
 func (s AuthHTTPStore) GetInstanceTypes(includeCPU bool) (*gpusearch.InstanceTypesResponse, error) {
-	return fetchInstanceTypes(includeCPU)
+	return cachedInstanceTypes(s.FileStore, s.catalogCachePolicy, includeCPU)
+}
+
+// cachedInstanceTypes serves the public instance-type listing through the catalog cache
+func cachedInstanceTypes(f FileStore, policy gpusearch.CatalogCachePolicy, includeCPU bool) (*gpusearch.InstanceTypesResponse, error) {
+	key := "instance_types"
+	if includeCPU {
+		key = "instance_types_cpu"
+	}
+	var result gpusearch.InstanceTypesResponse
+	err := f.withCatalogCache(policy, key, &result, func() (interface{}, error) {
+		return fetchInstanceTypes(includeCPU)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// withCatalogCache loads key into out according to policy, calling fetch when the
+// cache is missing, stale or bypassed. Cache write failures are not fatal.
+func (f FileStore) withCatalogCache(policy gpusearch.CatalogCachePolicy, key string, out interface{}, fetch func() (interface{}, error)) error {
+	path := ""
+	if brevHome, err := f.GetBrevHomePath(); err == nil {
+		path = files.CatalogCachePath(brevHome, key)
+	}
+
+	var cached *files.CatalogCacheEntry
+	if path != "" && policy.Mode != gpusearch.CatalogCacheRefresh {
+		cached, _ = files.ReadCatalogCache(f.fs, path)
+	}
+
+	if policy.Mode == gpusearch.CatalogCacheOffline {
+		if cached == nil {
+			return breverrors.NewValidationError("no cached instance types available offline; run once without --offline to populate the cache")
+		}
+		return breverrors.WrapAndTrace(json.Unmarshal(cached.Data, out))
+	}
+
+	if cached != nil && cached.Age(catalogCacheNow()) < f.catalogCacheTTL(policy) {
+		if err := json.Unmarshal(cached.Data, out); err == nil {
+			return nil
+		}
+	}
+
+	fresh, err := fetch()
+	if err != nil {
+		// Fall back to a stale entry rather than failing on flaky networks
+		if cached != nil && policy.Mode == gpusearch.CatalogCacheDefault {
+			if jsonErr := json.Unmarshal(cached.Data, out); jsonErr == nil {
+				return nil
+			}
+		}
+		return breverrors.WrapAndTrace(err)
+	}
+
+	data, err := json.Marshal(fresh)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	if path != "" {
+		_ = files.WriteCatalogCache(f.fs, path, &files.CatalogCacheEntry{FetchedAt: catalogCacheNow(), Data: data})
+	}
+	return breverrors.WrapAndTrace(json.Unmarshal(data, out))
+}
+
+// catalogCacheTTL resolves the TTL from the policy, then personal settings, then the default
+func (f FileStore) catalogCacheTTL(policy gpusearch.CatalogCachePolicy) time.Duration {
+	if policy.TTL > 0 {
+		return policy.TTL
+	}
+	if home, err := f.UserHomeDir(); err == nil {
+		if settings, err := files.ReadPersonalSettings(f.fs, home); err == nil && settings.CatalogCacheTTL != "" {
+			if ttl, err := time.ParseDuration(settings.CatalogCacheTTL); err == nil && ttl >= 0 {
+				return ttl
+			}
+		}
+	}
+	return gpusearch.DefaultCatalogCacheTTL
 }
 
 // fetchInstanceTypes fetches instance types from the public Brev API
@@ -55,6 +143,18 @@ func fetchInstanceTypes(includeCPU bool) (*gpusearch.InstanceTypesResponse, erro
 
 // GetAllInstanceTypesWithWorkspaceGroups fetches instance types with workspace groups from the authenticated API
 func (s AuthHTTPStore) GetAllInstanceTypesWithWorkspaceGroups(orgID string) (*gpusearch.AllInstanceTypesResponse, error) {
+	var result gpusearch.AllInstanceTypesResponse
+	err := s.withCatalogCache(s.catalogCachePolicy, "all_instance_types_"+orgID, &result, func() (interface{}, error) {
+		return s.fetchAllInstanceTypesWithWorkspaceGroups(orgID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// fetchAllInstanceTypesWithWorkspaceGroups fetches the org's instance types and workspace groups from the API
+func (s AuthHTTPStore) fetchAllInstanceTypesWithWorkspaceGroups(orgID string) (*gpusearch.AllInstanceTypesResponse, error) {
 	path := fmt.Sprintf(allInstanceTypesPathPattern, orgID)
 
 	var result gpusearch.AllInstanceTypesResponse
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brevdev/brev-cli/pkg/cmd/gpusearch"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withFixedCatalogNow(t *testing.T, now time.Time) {
+	t.Helper()
+	orig := catalogCacheNow
+	catalogCacheNow = func() time.Time { return now }
+	t.Cleanup(func() { catalogCacheNow = orig })
+}
+
+func fetchTypes(calls *int, types ...string) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		*calls++
+		resp := &gpusearch.InstanceTypesResponse{}
+		for _, typ := range types {
+			resp.Items = append(resp.Items, gpusearch.InstanceType{Type: typ})
+		}
+		return resp, nil
+	}
+}
+
+func failFetch(calls *int) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		*calls++
+		return nil, errors.New("network down")
+	}
+}
+
+func TestCatalogCache_FreshEntryServedWithoutFetch(t *testing.T) {
+	s := newTestFileStore(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	withFixedCatalogNow(t, now)
+	calls := 0
+
+	var first gpusearch.InstanceTypesResponse
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &first, fetchTypes(&calls, "g5.xlarge")))
+	assert.Equal(t, 1, calls)
+
+	withFixedCatalogNow(t, now.Add(5*time.Minute))
+	var second gpusearch.InstanceTypesResponse
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &second, fetchTypes(&calls, "p4d.24xlarge")))
+	assert.Equal(t, 1, calls)
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, "g5.xlarge", second.Items[0].Type)
+}
+
+func TestCatalogCache_StaleEntryRefetched(t *testing.T) {
+	s := newTestFileStore(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	withFixedCatalogNow(t, now)
+	calls := 0
+
+	var out gpusearch.InstanceTypesResponse
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &out, fetchTypes(&calls, "g5.xlarge")))
+
+	withFixedCatalogNow(t, now.Add(gpusearch.DefaultCatalogCacheTTL+time.Minute))
+	out = gpusearch.InstanceTypesResponse{}
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &out, fetchTypes(&calls, "p4d.24xlarge")))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "p4d.24xlarge", out.Items[0].Type)
+}
+
+func TestCatalogCache_RefreshIgnoresFreshEntry(t *testing.T) {
+	s := newTestFileStore(t)
+	withFixedCatalogNow(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	calls := 0
+
+	var out gpusearch.InstanceTypesResponse
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &out, fetchTypes(&calls, "g5.xlarge")))
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{Mode: gpusearch.CatalogCacheRefresh}, "instance_types", &out, fetchTypes(&calls, "p4d.24xlarge")))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "p4d.24xlarge", out.Items[0].Type)
+}
+
+func TestCatalogCache_StaleFallbackOnFetchError(t *testing.T) {
+	s := newTestFileStore(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	withFixedCatalogNow(t, now)
+	calls := 0
+
+	var out gpusearch.InstanceTypesResponse
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &out, fetchTypes(&calls, "g5.xlarge")))
+
+	withFixedCatalogNow(t, now.Add(24*time.Hour))
+	out = gpusearch.InstanceTypesResponse{}
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &out, failFetch(&calls)))
+	assert.Equal(t, "g5.xlarge", out.Items[0].Type)
+
+	err := s.withCatalogCache(gpusearch.CatalogCachePolicy{Mode: gpusearch.CatalogCacheRefresh}, "instance_types", &out, failFetch(&calls))
+	assert.Error(t, err)
+}
+
+func TestCatalogCache_Offline(t *testing.T) {
+	s := newTestFileStore(t)
+	withFixedCatalogNow(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	calls := 0
+	offline := gpusearch.CatalogCachePolicy{Mode: gpusearch.CatalogCacheOffline}
+
+	var out gpusearch.InstanceTypesResponse
+	err := s.withCatalogCache(offline, "instance_types", &out, failFetch(&calls))
+	assert.Error(t, err)
+	assert.Equal(t, 0, calls)
+
+	require.NoError(t, s.withCatalogCache(gpusearch.CatalogCachePolicy{}, "instance_types", &out, fetchTypes(&calls, "g5.xlarge")))
+
+	withFixedCatalogNow(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	out = gpusearch.InstanceTypesResponse{}
+	require.NoError(t, s.withCatalogCache(offline, "instance_types", &out, failFetch(&calls)))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "g5.xlarge", out.Items[0].Type)
+}
+
+func TestCatalogCache_TTLFromPersonalSettings(t *testing.T) {
+	s := newTestFileStore(t)
+	require.NoError(t, afero.WriteFile(s.fs, files.GetPersonalSettingsPath("/home/testuser"), []byte(`{"catalog_cache_ttl":"2h"}`), 0o600))
+
+	assert.Equal(t, 2*time.Hour, s.catalogCacheTTL(gpusearch.CatalogCachePolicy{}))
+	assert.Equal(t, time.Minute, s.catalogCacheTTL(gpusearch.CatalogCachePolicy{TTL: time.Minute}))
+}
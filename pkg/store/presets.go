@@ -0,0 +1,77 @@
+package store
+
+import (
+	"sort"
+
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/spf13/afero"
+)
+
+// GetSearchPresets returns the saved search presets from personal settings
+func (f FileStore) GetSearchPresets() (map[string]files.SearchPreset, error) {
+	home, err := f.UserHomeDir()
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	settings, err := files.ReadPersonalSettings(f.fs, home)
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	if settings.SearchPresets == nil {
+		return map[string]files.SearchPreset{}, nil
+	}
+	return settings.SearchPresets, nil
+}
+
+// SaveSearchPreset creates or replaces a named search preset
+func (f FileStore) SaveSearchPreset(name string, preset files.SearchPreset) error {
+	return f.updatePersonalSettings(func(settings *files.PersonalSettings) {
+		if settings.SearchPresets == nil {
+			settings.SearchPresets = map[string]files.SearchPreset{}
+		}
+		settings.SearchPresets[name] = preset
+	})
+}
+
+// DeleteSearchPresets removes the named presets and returns the names that did not exist
+func (f FileStore) DeleteSearchPresets(names []string) ([]string, error) {
+	var missing []string
+	err := f.updatePersonalSettings(func(settings *files.PersonalSettings) {
+		for _, name := range names {
+			if _, ok := settings.SearchPresets[name]; !ok {
+				missing = append(missing, name)
+				continue
+			}
+			delete(settings.SearchPresets, name)
+		}
+	})
+	sort.Strings(missing)
+	return missing, err
+}
+
+// updatePersonalSettings applies update to the personal settings file, keeping other fields.
+// Unlike files.ReadPersonalSettings it fails on an unreadable or corrupt file rather than
+// overwriting it with defaults.
+func (f FileStore) updatePersonalSettings(update func(settings *files.PersonalSettings)) error {
+	home, err := f.UserHomeDir()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	settingsPath := files.GetPersonalSettingsPath(home)
+	exists, err := afero.Exists(f.fs, settingsPath)
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	settings := &files.PersonalSettings{}
+	if exists {
+		if err := files.ReadJSON(f.fs, settingsPath, settings); err != nil {
+			return breverrors.WrapAndTrace(err)
+		}
+	}
+	update(settings)
+	if err := files.WritePersonalSettings(f.fs, home, settings); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/brevdev/brev-cli/pkg/files"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchPresets_RoundTrip(t *testing.T) {
+	s, _, _ := newAuthTokenTestStore(t)
+
+	presets, err := s.GetSearchPresets()
+	require.NoError(t, err)
+	assert.Empty(t, presets)
+
+	require.NoError(t, s.SaveSearchPreset("h100-fast", files.SearchPreset{"min-vram": "80", "stoppable": "true"}))
+	require.NoError(t, s.SaveSearchPreset("cheap", files.SearchPreset{"sort": "price"}))
+
+	presets, err = s.GetSearchPresets()
+	require.NoError(t, err)
+	assert.Equal(t, files.SearchPreset{"min-vram": "80", "stoppable": "true"}, presets["h100-fast"])
+	assert.Len(t, presets, 2)
+
+	missing, err := s.DeleteSearchPresets([]string{"cheap", "nope"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nope"}, missing)
+
+	presets, err = s.GetSearchPresets()
+	require.NoError(t, err)
+	assert.Len(t, presets, 1)
+}
+
+func TestSearchPresets_KeepOtherSettings(t *testing.T) {
+	s, fs, home := newAuthTokenTestStore(t)
+	require.NoError(t, fs.MkdirAll(filepath.Dir(files.GetPersonalSettingsPath(home)), 0o755))
+	require.NoError(t, files.WritePersonalSettings(fs, home, &files.PersonalSettings{DefaultEditor: "cursor", AnalyticsID: "abc"}))
+
+	require.NoError(t, s.SaveSearchPreset("h100-fast", files.SearchPreset{"min-vram": "80"}))
+
+	settings, err := files.ReadPersonalSettings(fs, home)
+	require.NoError(t, err)
+	assert.Equal(t, "cursor", settings.DefaultEditor)
+	assert.Equal(t, "abc", settings.AnalyticsID)
+	assert.Len(t, settings.SearchPresets, 1)
+}
+
+func TestSearchPresets_CorruptSettingsAreNotOverwritten(t *testing.T) {
+	s, fs, home := newAuthTokenTestStore(t)
+	settingsPath := files.GetPersonalSettingsPath(home)
+	require.NoError(t, fs.MkdirAll(filepath.Dir(settingsPath), 0o755))
+	require.NoError(t, afero.WriteFile(fs, settingsPath, []byte(`{"default_editor": "cursor",`), 0o600))
+
+	err := s.SaveSearchPreset("h100-fast", files.SearchPreset{"min-vram": "80"})
+	require.Error(t, err)
+
+	data, err := afero.ReadFile(fs, settingsPath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"default_editor": "cursor",`, string(data))
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	breverrors "github.com/brevdev/brev-cli/pkg/errors"
+	"github.com/brevdev/brev-cli/pkg/files"
+)
+
+// GetSchedules reads ~/.brev/schedules.json, returning no schedules if it doesn't exist.
+func (f FileStore) GetSchedules() (*files.Schedules, error) {
+	brevHome, err := f.GetBrevHomePath()
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	schedules, err := files.ReadSchedules(f.fs, files.SchedulesPath(brevHome))
+	if err != nil {
+		return nil, breverrors.WrapAndTrace(err)
+	}
+	return schedules, nil
+}
+
+// SaveSchedules writes the schedules to ~/.brev/schedules.json.
+func (f FileStore) SaveSchedules(schedules *files.Schedules) error {
+	brevHome, err := f.GetBrevHomePath()
+	if err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	if err := files.WriteSchedules(f.fs, files.SchedulesPath(brevHome), schedules); err != nil {
+		return breverrors.WrapAndTrace(err)
+	}
+	return nil
+}
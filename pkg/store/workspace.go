@@ -35,48 +35,17 @@ type ModifyWorkspaceRequest struct {
 	InstanceType      string            `json:"instanceType,omitempty"`
 }
 
-// LifeCycleScriptAttr holds the lifecycle script configuration
-type LifeCycleScriptAttr struct {
-	Script string `json:"script,omitempty"`
-	ID     string `json:"id,omitempty"`
-	Name   string `json:"name,omitempty"`
-}
-
-// VMBuild holds VM-specific build configuration
-type VMBuild struct {
-	ForceJupyterInstall bool                 `json:"forceJupyterInstall,omitempty"`
-	LifeCycleScriptAttr *LifeCycleScriptAttr `json:"lifeCycleScriptAttr,omitempty"`
-	K8s                 *K8sConfig           `json:"k8s,omitempty"`
-}
-
-// K8sConfig holds Kubernetes configuration for VM builds
-type K8sConfig struct {
-	IsDisabled         bool `json:"isDisabled"`
-	IsDashboardEnabled bool `json:"isDashboardEnabled"`
-}
-
-// CustomContainer holds custom container build configuration
-type CustomContainer struct {
-	ContainerURL string    `json:"containerUrl"`
-	EntryPoint   string    `json:"entryPoint"`
-	Registry     *Registry `json:"registry,omitempty"`
-}
-
-// DockerCompose holds Docker Compose build configuration
-type DockerCompose struct {
-	FileURL              string            `json:"fileUrl,omitempty"`
-	YamlString           string            `json:"yamlString,omitempty"`
-	JupyterInstall       bool              `json:"jupyterInstall"`
-	EnvironmentVariables map[string]string `json:"environmentVariables,omitempty"`
-	Registries           []*Registry       `json:"registries,omitempty"`
-}
-
-// Registry holds container registry credentials
-type Registry struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Url      string `json:"url"`
-}
+// Build configuration is defined in entity so that it can also be read back
+// from a workspace
+type (
+	LifeCycleScriptAttr = entity.LifeCycleScriptAttr
+	VMBuild             = entity.VMBuild
+	K8sConfig           = entity.K8sConfig
+	CustomContainer     = entity.CustomContainer
+	DockerCompose       = entity.DockerCompose
+	Registry            = entity.Registry
+	CreateFirewallRule  = entity.FirewallRule
+)
 
 type CreateWorkspacesOptions struct {
 	Name                 string               `json:"name"`
@@ -116,14 +85,6 @@ type CreateWorkspacesOptions struct {
 	LaunchableConfig     *LaunchableConfig    `json:"launchableConfig,omitempty"`
 }
 
-// CreateFirewallRule mirrors brev-deploy's CreateFirewallRule. AllowedIPs is
-// either "all" (open to 0.0.0.0/0) or "user-ip" (open to ClientIPs).
-type CreateFirewallRule struct {
-	Port       string   `json:"port"`
-	AllowedIPs string   `json:"allowedIPs"`
-	ClientIPs  []string `json:"clientIPs,omitempty"`
-}
-
 type LaunchableConfig struct {
 	ID string `json:"id"`
 }
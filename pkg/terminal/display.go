@@ -142,3 +142,21 @@ func PromptGetInput(pc PromptContent) string {
 
 	return result
 }
+
+// Prompter implements Confirmer and Selector with interactive terminal prompts.
+type Prompter struct{}
+
+func (Prompter) ConfirmYesNo(label string) bool {
+	result := PromptSelectInput(PromptSelectContent{
+		Label: label,
+		Items: []string{"Yes, proceed", "No, cancel"},
+	})
+	return result == "Yes, proceed"
+}
+
+func (Prompter) Select(label string, items []string) string {
+	return PromptSelectInput(PromptSelectContent{
+		Label: label,
+		Items: items,
+	})
+}
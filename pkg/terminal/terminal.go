@@ -52,6 +52,15 @@ func New() (t *Terminal) {
 	}
 }
 
+// ToStderr returns a copy of the terminal that writes all output to stderr,
+// leaving stdout for machine-readable output
+func (t *Terminal) ToStderr() *Terminal {
+	c := *t
+	c.out = t.err
+	c.verbose = t.err
+	return &c
+}
+
 // Explicit  error handling for Print functions
 func (t *Terminal) Print(a string) {
 	_, _ = fmt.Fprintln(t.out, a)